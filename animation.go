@@ -0,0 +1,583 @@
+package css
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Animation is one animation of the animation properties, with the
+// defaults of omitted values filled in.
+type Animation struct {
+	// Name is the name of the @keyframes rule, or "none".
+	Name     string
+	Duration time.Duration
+	// Easing is the timing function of the keyframes that don't have one.
+	Easing EasingFunction
+	Delay  time.Duration
+	// IterationCount is +Inf for infinite.
+	IterationCount float64
+	// Direction, FillMode and PlayState are the keywords of the
+	// animation-direction, animation-fill-mode and animation-play-state
+	// properties.
+	Direction string
+	FillMode  string
+	PlayState string
+}
+
+func initialAnimation() Animation {
+	return Animation{
+		Name:           "none",
+		Easing:         easingKeywords["ease"],
+		IterationCount: 1,
+		Direction:      "normal",
+		FillMode:       "none",
+		PlayState:      "running",
+	}
+}
+
+func (a Animation) String() string {
+	return strings.Join([]string{
+		formatDuration(a.Duration), a.Easing.String(), formatDuration(a.Delay), formatIterationCount(a.IterationCount),
+		a.Direction, a.FillMode, a.PlayState, formatKeyframesName(a.Name),
+	}, " ")
+}
+
+func formatDuration(d time.Duration) string {
+	return formatNumber(d.Seconds()) + "s"
+}
+
+func formatIterationCount(n float64) string {
+	if math.IsInf(n, 1) {
+		return "infinite"
+	}
+	return formatNumber(n)
+}
+
+// formatKeyframesName returns name as an identifier if it is one, and as a
+// string otherwise.
+func formatKeyframesName(name string) string {
+	if isIdentifier(name) && isCustomIdent(ComponentValue{Type: IdentValue, Text: name}) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// timeOf returns a <time> as a duration.
+func timeOf(v ComponentValue) time.Duration {
+	seconds := func(v ComponentValue) float64 {
+		if v.Unit == "ms" {
+			return v.Number / 1000
+		}
+		return v.Number
+	}
+	s := seconds(v)
+	if v.Type == FunctionValue {
+		s, _ = evalMath(v, func(v ComponentValue) (float64, bool) {
+			return seconds(v), v.Type == DimensionValue
+		})
+	}
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+func iterationCountOf(v ComponentValue) float64 {
+	if v.Is("infinite") {
+		return math.Inf(1)
+	}
+	return numberValue([]ComponentValue{v}).(float64)
+}
+
+func keyframesNameOf(v ComponentValue) string {
+	if v.Type == IdentValue && v.Is("none") {
+		return "none"
+	}
+	return v.Text
+}
+
+func animation(values []ComponentValue) interface{} {
+	var animations []Animation
+	for _, layer := range splitCommas(values) {
+		productionGrammars["single-animation"].MatchValues(layer)
+		a := initialAnimation()
+		duration := false
+		for _, v := range layer {
+			switch v.Production {
+			case "easing-function":
+				a.Easing = easingOf(v)
+			case "single-animation-iteration-count":
+				a.IterationCount = iterationCountOf(v)
+			case "single-animation-direction":
+				a.Direction = strings.ToLower(v.Text)
+			case "single-animation-fill-mode":
+				a.FillMode = strings.ToLower(v.Text)
+			case "single-animation-play-state":
+				a.PlayState = strings.ToLower(v.Text)
+			case "keyframes-name":
+				a.Name = keyframesNameOf(v)
+			default:
+				if v.Is("none") {
+					continue
+				}
+				// the first time is the duration, unless it is negative
+				// and can only be the delay
+				if t := timeOf(v); !duration && t >= 0 {
+					a.Duration, duration = t, true
+				} else {
+					a.Delay = t
+				}
+			}
+		}
+		animations = append(animations, a)
+	}
+	return animations
+}
+
+func expandAnimation(values []ComponentValue) map[string]string {
+	lists := make(map[string][]string)
+	for _, a := range animation(values).([]Animation) {
+		lists["animation-name"] = append(lists["animation-name"], formatKeyframesName(a.Name))
+		lists["animation-duration"] = append(lists["animation-duration"], formatDuration(a.Duration))
+		lists["animation-timing-function"] = append(lists["animation-timing-function"], a.Easing.String())
+		lists["animation-delay"] = append(lists["animation-delay"], formatDuration(a.Delay))
+		lists["animation-iteration-count"] = append(lists["animation-iteration-count"], formatIterationCount(a.IterationCount))
+		lists["animation-direction"] = append(lists["animation-direction"], a.Direction)
+		lists["animation-fill-mode"] = append(lists["animation-fill-mode"], a.FillMode)
+		lists["animation-play-state"] = append(lists["animation-play-state"], a.PlayState)
+	}
+	longhands := make(map[string]string, len(lists))
+	for name, list := range lists {
+		longhands[name] = strings.Join(list, ", ")
+	}
+	return longhands
+}
+
+func animationNames(values []ComponentValue) interface{} {
+	var names []string
+	for _, layer := range splitCommas(values) {
+		names = append(names, keyframesNameOf(layer[0]))
+	}
+	return names
+}
+
+func timeList(values []ComponentValue) interface{} {
+	var times []time.Duration
+	for _, layer := range splitCommas(values) {
+		times = append(times, timeOf(layer[0]))
+	}
+	return times
+}
+
+func easingList(values []ComponentValue) interface{} {
+	var easings []EasingFunction
+	for _, layer := range splitCommas(values) {
+		easings = append(easings, easingOf(layer[0]))
+	}
+	return easings
+}
+
+func iterationCounts(values []ComponentValue) interface{} {
+	var counts []float64
+	for _, layer := range splitCommas(values) {
+		counts = append(counts, iterationCountOf(layer[0]))
+	}
+	return counts
+}
+
+// Animations returns the animations of the computed style, one for each
+// name of animation-name including none. The lists of the other animation
+// properties are repeated or truncated to the length of animation-name.
+func Animations(style map[string]string) ([]Animation, error) {
+	style, err := expandStyle(style)
+	if err != nil {
+		return nil, err
+	}
+	b := &layoutBox{style: style}
+	v, err := b.value("animation-name")
+	if err != nil {
+		return nil, err
+	}
+	names, _ := v.([]string)
+	animations := make([]Animation, len(names))
+	for i, name := range names {
+		animations[i] = initialAnimation()
+		animations[i].Name = name
+	}
+	if len(animations) == 0 {
+		return nil, nil
+	}
+
+	for _, name := range []string{
+		"animation-duration", "animation-timing-function", "animation-delay", "animation-iteration-count",
+		"animation-direction", "animation-fill-mode", "animation-play-state",
+	} {
+		v, err := b.value(name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range animations {
+			a := &animations[i]
+			switch list := v.(type) {
+			case []time.Duration:
+				if name == "animation-duration" {
+					a.Duration = list[i%len(list)]
+				} else {
+					a.Delay = list[i%len(list)]
+				}
+			case []EasingFunction:
+				a.Easing = list[i%len(list)]
+			case []float64:
+				a.IterationCount = list[i%len(list)]
+			case []string:
+				keyword := list[i%len(list)]
+				switch name {
+				case "animation-direction":
+					a.Direction = keyword
+				case "animation-fill-mode":
+					a.FillMode = keyword
+				default:
+					a.PlayState = keyword
+				}
+			}
+		}
+	}
+	return animations, nil
+}
+
+// Progress returns the progress of a through the current iteration at
+// the time t since the animation was applied, in the direction of the
+// iteration. It returns false if the animation has no effect at t, before
+// its delay or after its end without fill. The play state is up to the
+// caller, who stops advancing t while the animation is paused.
+func (a Animation) Progress(t time.Duration) (float64, bool) {
+	duration := a.Duration.Seconds()
+	active := 0.0
+	if duration > 0 {
+		active = duration * a.IterationCount
+	}
+	elapsed := (t - a.Delay).Seconds()
+	before := elapsed < 0
+	activeTime := elapsed
+	switch {
+	case before:
+		if a.FillMode != "backwards" && a.FillMode != "both" {
+			return 0, false
+		}
+		activeTime = 0
+	case elapsed >= active:
+		if a.FillMode != "forwards" && a.FillMode != "both" {
+			return 0, false
+		}
+		activeTime = active
+	}
+
+	overall := 0.0
+	switch {
+	case duration > 0:
+		overall = activeTime / duration
+	case !before:
+		overall = a.IterationCount
+	}
+	progress := math.Mod(overall, 1)
+	if math.IsInf(overall, 1) {
+		progress = 0
+	}
+	// the end of an iteration is the end of the iteration, not the start
+	// of the next one
+	if progress == 0 && overall != 0 && !before && activeTime == active && a.IterationCount != 0 {
+		progress = 1
+	}
+	iteration := math.Floor(overall)
+	if progress == 1 {
+		iteration--
+	}
+	odd := math.Mod(iteration, 2) == 1
+	switch a.Direction {
+	case "reverse":
+		progress = 1 - progress
+	case "alternate":
+		if odd {
+			progress = 1 - progress
+		}
+	case "alternate-reverse":
+		if !odd {
+			progress = 1 - progress
+		}
+	}
+	return progress, true
+}
+
+// Keyframes is a @keyframes rule.
+type Keyframes struct {
+	Name string
+	// Keyframes are sorted by their offset.
+	Keyframes []Keyframe
+}
+
+// Keyframe is a keyframe of a @keyframes rule.
+type Keyframe struct {
+	// Offset is the position of the keyframe in an iteration, from 0 for
+	// "from" to 1 for "to".
+	Offset float64
+	// Declarations are the animated properties, with shorthands expanded.
+	Declarations map[string]string
+	// Easing is the animation-timing-function of the keyframe, or nil if
+	// it uses the one of the animation.
+	Easing *EasingFunction
+}
+
+// UnmarshalKeyframes returns the top level @keyframes rules of a
+// stylesheet by name. If several rules have the same name, the last one
+// wins. Keyframes with the same offset and timing function are merged.
+func UnmarshalKeyframes(b []byte) (map[string]Keyframes, error) {
+	h := &keyframesHandler{keyframes: make(map[string]Keyframes)}
+	if err := parseBytes(context.Background(), b, h); err != nil {
+		return h.keyframes, err
+	}
+	return h.keyframes, nil
+}
+
+// keyframesHandler collects the @keyframes rules of a stylesheet.
+type keyframesHandler struct {
+	NopHandler
+
+	keyframes map[string]Keyframes
+	// rule is the @keyframes rule being read, if any
+	rule *Keyframes
+	// frames are the keyframes of the keyframe block being read
+	frames []*Keyframe
+	depth  int
+}
+
+func (h *keyframesHandler) StartRule(selectors []string) error {
+	h.depth++
+	if h.depth != 2 || h.rule == nil {
+		return nil
+	}
+	for _, selector := range selectors {
+		offset, err := parseKeyframeSelector(selector)
+		if err != nil {
+			return fmt.Errorf("@keyframes %s: %w", h.rule.Name, err)
+		}
+		h.frames = append(h.frames, &Keyframe{Offset: offset, Declarations: make(map[string]string)})
+	}
+	return nil
+}
+
+func (h *keyframesHandler) EndRule() error {
+	h.depth--
+	if h.depth != 1 || h.rule == nil {
+		return nil
+	}
+	for _, frame := range h.frames {
+		h.rule.Keyframes = append(h.rule.Keyframes, *frame)
+	}
+	h.frames = nil
+	return nil
+}
+
+func (h *keyframesHandler) StartAtRule(name, prelude string) error {
+	h.depth++
+	if h.depth != 1 || name != "keyframes" {
+		return nil
+	}
+	values, err := ParseValue(prelude)
+	if err != nil || len(values) != 1 || values[0].Is("none") || !productionGrammars["keyframes-name"].MatchValues(values) {
+		return fmt.Errorf("invalid @keyframes name %q", strings.TrimSpace(prelude))
+	}
+	h.rule = &Keyframes{Name: values[0].Text}
+	return nil
+}
+
+// Declaration adds declarations to the keyframes being read. Declarations
+// with !important and the animation properties other than
+// animation-timing-function are ignored in keyframes.
+func (h *keyframesHandler) Declaration(name, value string, important bool) error {
+	if h.depth != 2 || len(h.frames) == 0 || important {
+		return nil
+	}
+	name = strings.ToLower(name)
+	switch {
+	case name == "animation-timing-function":
+		easing, err := ParseEasingFunction(value)
+		if err != nil {
+			return fmt.Errorf("@keyframes %s: %w", h.rule.Name, err)
+		}
+		for _, frame := range h.frames {
+			frame.Easing = &easing
+		}
+	case strings.HasPrefix(name, "animation"):
+	default:
+		longhands := map[string]string{name: value}
+		if _, ok := shorthandExpanders[name]; ok {
+			var err error
+			if longhands, err = ExpandShorthand(name, value); err != nil {
+				return fmt.Errorf("@keyframes %s: %s: %w", h.rule.Name, name, err)
+			}
+		}
+		for _, frame := range h.frames {
+			for longhand, v := range longhands {
+				frame.Declarations[longhand] = v
+			}
+		}
+	}
+	return nil
+}
+
+func (h *keyframesHandler) EndAtRule(name string) error {
+	h.depth--
+	if h.depth == 0 && h.rule != nil {
+		h.keyframes[h.rule.Name] = mergeKeyframes(*h.rule)
+		h.rule = nil
+	}
+	return nil
+}
+
+// parseKeyframeSelector returns the offset of a keyframe selector like
+// "from", "to" or "25%".
+func parseKeyframeSelector(selector string) (float64, error) {
+	switch strings.ToLower(selector) {
+	case "from":
+		return 0, nil
+	case "to":
+		return 1, nil
+	}
+	values, err := ParseValue(selector)
+	if err != nil || len(values) != 1 || values[0].Type != PercentageValue || values[0].Number < 0 || values[0].Number > 100 {
+		return 0, fmt.Errorf("invalid keyframe selector %q", selector)
+	}
+	return values[0].Number / 100, nil
+}
+
+// mergeKeyframes sorts the keyframes of k by offset, and merges those
+// with the same offset and timing function.
+func mergeKeyframes(k Keyframes) Keyframes {
+	sort.SliceStable(k.Keyframes, func(i, j int) bool {
+		return k.Keyframes[i].Offset < k.Keyframes[j].Offset
+	})
+	var merged []Keyframe
+	for _, frame := range k.Keyframes {
+		if n := len(merged); n > 0 && merged[n-1].Offset == frame.Offset && sameEasing(merged[n-1].Easing, frame.Easing) {
+			for name, v := range frame.Declarations {
+				merged[n-1].Declarations[name] = v
+			}
+			continue
+		}
+		merged = append(merged, frame)
+	}
+	k.Keyframes = merged
+	return k
+}
+
+func sameEasing(a, b *EasingFunction) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
+
+// keyframeValue is the value of a property at an offset of an animation.
+type keyframeValue struct {
+	offset float64
+	style  Style
+	easing *EasingFunction
+}
+
+// Sample returns the values of the properties animated by the keyframes k
+// at the time t since a was applied. Keyframes at offsets 0 and 1 that
+// don't set a property take its value from the underlying style, or its
+// initial value. It returns nil if the animation has no effect at t.
+// Properties that aren't animatable or registered are left out, and
+// registered properties without a style handler are an error.
+func (a Animation) Sample(k Keyframes, t time.Duration, underlying map[string]string) (map[string]Style, error) {
+	progress, ok := a.Progress(t)
+	if !ok || a.Name == "none" {
+		return nil, nil
+	}
+	underlying, err := expandStyle(underlying)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string][]keyframeValue)
+	for _, frame := range k.Keyframes {
+		for name, value := range frame.Declarations {
+			m, known := DefaultRegistry.Metadata(name)
+			if _, ok := DefaultRegistry.Lookup(name); !ok {
+				if known && m.AnimationType != AnimationNotAnimatable {
+					return nil, fmt.Errorf("@keyframes %s: %s has no style handler", k.Name, name)
+				}
+				continue
+			}
+			if m.AnimationType == AnimationNotAnimatable {
+				continue
+			}
+			style, err := animatedStyle(name, value)
+			if err != nil {
+				return nil, fmt.Errorf("@keyframes %s: %w", k.Name, err)
+			}
+			values := properties[name]
+			// a later keyframe at the same offset overrides the value
+			if n := len(values); n > 0 && values[n-1].offset == frame.Offset {
+				values = values[:n-1]
+			}
+			properties[name] = append(values, keyframeValue{frame.Offset, style, frame.Easing})
+		}
+	}
+
+	samples := make(map[string]Style, len(properties))
+	for name, values := range properties {
+		if values[0].offset != 0 || values[len(values)-1].offset != 1 {
+			value, ok := underlying[name]
+			if !ok {
+				value = "initial"
+			}
+			style, err := animatedStyle(name, value)
+			if err != nil {
+				return nil, err
+			}
+			if values[0].offset != 0 {
+				values = append([]keyframeValue{{0, style, nil}}, values...)
+			}
+			if values[len(values)-1].offset != 1 {
+				values = append(values, keyframeValue{1, style, nil})
+			}
+		}
+
+		i := 0
+		for i+1 < len(values) && values[i+1].offset <= progress {
+			i++
+		}
+		if i == len(values)-1 {
+			samples[name] = values[i].style
+			continue
+		}
+		from, to := values[i], values[i+1]
+		easing := a.Easing
+		if from.easing != nil {
+			easing = *from.easing
+		}
+		p := easing.Eval((progress - from.offset) / (to.offset - from.offset))
+		samples[name] = interpolateStyle(name, from.style, to.style, p)
+	}
+	return samples, nil
+}
+
+// animatedStyle returns the typed value of a property in an animation,
+// with initial replaced by the initial value if it can be parsed.
+func animatedStyle(name, value string) (Style, error) {
+	style, err := DefaultRegistry.CSSStyle(name, map[string]string{name: value})
+	if err != nil {
+		return Style{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if style.Value == CSSWideKeyword("initial") {
+		m, _ := DefaultRegistry.Metadata(name)
+		if initial, err := DefaultRegistry.CSSStyle(name, map[string]string{name: m.Initial}); err == nil {
+			return initial, nil
+		}
+	}
+	return style, nil
+}
@@ -0,0 +1,299 @@
+package css
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimationProperties(t *testing.T) {
+	ease := easingKeywords["ease"]
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"animation-name", "none, slide, \"fade in\"", []string{"none", "slide", "fade in"}},
+		{"animation-duration", "1s, 250ms", []time.Duration{time.Second, 250 * time.Millisecond}},
+		{"animation-delay", "-1.5s, calc(1s + 10ms)", []time.Duration{-1500 * time.Millisecond, 1010 * time.Millisecond}},
+		{"animation-timing-function", "ease, steps(2)", []EasingFunction{ease, {Name: "steps", Steps: 2, Position: "jump-end"}}},
+		{"animation-iteration-count", "infinite, 2.5", []float64{math.Inf(1), 2.5}},
+		{"animation-direction", "alternate, Reverse", []string{"alternate", "reverse"}},
+		{"animation-fill-mode", "both", []string{"both"}},
+		{"animation-play-state", "paused, running", []string{"paused", "running"}},
+		{"animation", "slide 1s", []Animation{{
+			Name: "slide", Duration: time.Second, Easing: ease, IterationCount: 1,
+			Direction: "normal", FillMode: "none", PlayState: "running",
+		}}},
+		{"animation", "2s linear -1s infinite alternate-reverse forwards paused \"a b\", -1s ease-in x", []Animation{{
+			Name: "a b", Duration: 2 * time.Second, Easing: EasingFunction{Name: "linear"}, Delay: -time.Second,
+			IterationCount: math.Inf(1), Direction: "alternate-reverse", FillMode: "forwards", PlayState: "paused",
+		}, {
+			Name: "x", Easing: easingKeywords["ease-in"], Delay: -time.Second, IterationCount: 1,
+			Direction: "normal", FillMode: "none", PlayState: "running",
+		}}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for _, tt := range [][2]string{
+		{"animation-name", "initial, slide"},
+		{"animation-duration", "-1s"},
+		{"animation-duration", "1"},
+		{"animation-iteration-count", "-1"},
+		{"animation-timing-function", "steps(1, jump-none)"},
+		{"animation", "1s 2s 3s slide"},
+		{"animation", "slide other"},
+		{"animation", "linear(1) slide"},
+	} {
+		_, err := CSSStyle(tt[0], map[string]string{tt[0]: tt[1]})
+		assert.Error(t, err, tt[1])
+	}
+}
+
+func TestExpandAnimation(t *testing.T) {
+	longhands, err := ExpandShorthand("animation", "slide 1s steps(4, start) 2, 500ms \"fade in\" reverse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"animation-name":            "slide, \"fade in\"",
+		"animation-duration":        "1s, 0.5s",
+		"animation-timing-function": "steps(4, jump-start), ease",
+		"animation-delay":           "0s, 0s",
+		"animation-iteration-count": "2, 1",
+		"animation-direction":       "normal, reverse",
+		"animation-fill-mode":       "none, none",
+		"animation-play-state":      "running, running",
+	}, longhands)
+}
+
+func TestAnimations(t *testing.T) {
+	animations, err := Animations(map[string]string{
+		"animation":                 "slide 1s",
+		"animation-name":            "a, b, none",
+		"animation-duration":        "1s, 2s",
+		"animation-iteration-count": "3, 4, 5, 6",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	var durations []time.Duration
+	var counts []float64
+	for _, a := range animations {
+		names = append(names, a.Name)
+		durations = append(durations, a.Duration)
+		counts = append(counts, a.IterationCount)
+	}
+	assert.Equal(t, []string{"a", "b", "none"}, names)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, time.Second}, durations)
+	assert.Equal(t, []float64{3, 4, 5}, counts)
+
+	animations, err = Animations(map[string]string{"color": "red"})
+	assert.NoError(t, err)
+	assert.Nil(t, animations)
+}
+
+func TestUnmarshalKeyframes(t *testing.T) {
+	keyframes, err := UnmarshalKeyframes([]byte(`
+@keyframes slide {
+	to { margin: 4px; animation-name: other; color: red !important }
+	from, 50% { width: 10px; animation-timing-function: steps(2) }
+	50% { height: 5px; animation-timing-function: steps(2) }
+	50% { width: 20px; animation-timing-function: linear }
+}
+div { width: 1px }
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := &EasingFunction{Name: "steps", Steps: 2, Position: "jump-end"}
+	linear := &EasingFunction{Name: "linear"}
+	assert.Equal(t, map[string]Keyframes{"slide": {Name: "slide", Keyframes: []Keyframe{
+		{Offset: 0, Declarations: map[string]string{"width": "10px"}, Easing: steps},
+		{Offset: 0.5, Declarations: map[string]string{"width": "10px", "height": "5px"}, Easing: steps},
+		{Offset: 0.5, Declarations: map[string]string{"width": "20px"}, Easing: linear},
+		{Offset: 1, Declarations: map[string]string{
+			"margin-top": "4px", "margin-right": "4px", "margin-bottom": "4px", "margin-left": "4px",
+		}},
+	}}}, keyframes)
+
+	keyframes, err = UnmarshalKeyframes([]byte(`
+@keyframes slide { to { width: 0 } }
+@keyframes "slide" { 0% { width: 0 } }
+@keyframes fade { 0% { color: red } }
+@media print {
+	@keyframes fade { to { color: blue } }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]Keyframes{
+		"slide": {Name: "slide", Keyframes: []Keyframe{{Offset: 0, Declarations: map[string]string{"width": "0"}}}},
+		"fade":  {Name: "fade", Keyframes: []Keyframe{{Offset: 0, Declarations: map[string]string{"color": "red"}}}},
+	}, keyframes)
+
+	for _, css := range []string{
+		"@keyframes none { from { width: 0 } }",
+		"@keyframes a b { from { width: 0 } }",
+		"@keyframes a { 101% { width: 0 } }",
+		"@keyframes a { 10px { width: 0 } }",
+		"@keyframes a { from { margin: 1px 2px 3px 4px 5px } }",
+		"@keyframes a { from { animation-timing-function: steps(0) } }",
+	} {
+		_, err := UnmarshalKeyframes([]byte(css))
+		assert.Error(t, err, css)
+	}
+}
+
+func TestAnimationProgress(t *testing.T) {
+	cases := []struct {
+		animation string
+		times     []time.Duration
+		expected  []float64
+	}{
+		{"1s", []time.Duration{-1, 0, 250 * time.Millisecond, time.Second - 1, time.Second}, []float64{-1, 0, 0.25, 1, -1}},
+		{"1s 1s both", []time.Duration{0, time.Second, 3 * time.Second}, []float64{0, 0, 1}},
+		{"1s 2 forwards", []time.Duration{1250 * time.Millisecond, 2 * time.Second, 5 * time.Second}, []float64{0.25, 1, 1}},
+		{"1s 1.5 forwards", []time.Duration{5 * time.Second}, []float64{0.5}},
+		{"1s 3 alternate forwards", []time.Duration{250 * time.Millisecond, 1250 * time.Millisecond, 2250 * time.Millisecond, 3 * time.Second}, []float64{0.25, 0.75, 0.25, 1}},
+		{"1s 2 alternate-reverse forwards", []time.Duration{250 * time.Millisecond, 1250 * time.Millisecond, 2 * time.Second}, []float64{0.75, 0.25, 1}},
+		{"1s reverse backwards 1s", []time.Duration{0, 1250 * time.Millisecond, 2 * time.Second}, []float64{1, 0.75, -1}},
+		{"1s infinite", []time.Duration{100 * time.Hour}, []float64{0}},
+		{"0s 2 alternate both", []time.Duration{-1, 0}, []float64{0, 0}},
+		{"0s 3 alternate both", []time.Duration{0}, []float64{1}},
+		{"0s infinite forwards", []time.Duration{0}, []float64{1}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.animation, func(t *testing.T) {
+			style, err := CSSStyle("animation", map[string]string{"animation": tt.animation})
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := style.Value.([]Animation)[0]
+			progress := make([]float64, len(tt.times))
+			for i, at := range tt.times {
+				var ok bool
+				if progress[i], ok = a.Progress(at); !ok {
+					progress[i] = -1
+				}
+			}
+			assert.InDeltaSlice(t, tt.expected, progress, 1e-9)
+		})
+	}
+}
+
+func TestAnimationSample(t *testing.T) {
+	keyframes, err := UnmarshalKeyframes([]byte(`
+@keyframes move {
+	from { width: 10px; background-color: red; transform: none; display: block }
+	50% { width: 20px; animation-timing-function: steps(2) }
+	to { width: 40px; background-color: blue; transform: rotate(90deg); display: none; margin-top: 4px; animation-duration: 10s }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := keyframes["move"]
+	underlying := map[string]string{"margin": "10px", "width": "5px"}
+	style, err := CSSStyle("animation", map[string]string{"animation": "move 2s linear"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := style.Value.([]Animation)[0]
+
+	cases := []struct {
+		at       time.Duration
+		expected map[string]string
+	}{
+		{0, map[string]string{
+			"width": "10px", "background-color": "#ff0000", "transform": "rotate(0deg)", "display": "block", "margin-top": "10px",
+		}},
+		{500 * time.Millisecond, map[string]string{
+			"width": "15px", "background-color": "#bf0040", "transform": "rotate(22.5deg)", "display": "block", "margin-top": "8.5px",
+		}},
+		{time.Second, map[string]string{
+			"width": "20px", "background-color": "#800080", "transform": "rotate(45deg)", "display": "none", "margin-top": "7px",
+		}},
+		// the 50% keyframe has two steps
+		{1250 * time.Millisecond, map[string]string{
+			"width": "20px", "background-color": "#60009f", "transform": "rotate(56.25deg)", "display": "none", "margin-top": "6.25px",
+		}},
+		{1500 * time.Millisecond, map[string]string{
+			"width": "30px", "background-color": "#4000bf", "transform": "rotate(67.5deg)", "display": "none", "margin-top": "5.5px",
+		}},
+	}
+	for _, tt := range cases {
+		samples, err := a.Sample(k, tt.at, underlying)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := make(map[string]string)
+		for name, s := range samples {
+			actual[name] = s.String()
+		}
+		assert.Equal(t, tt.expected, actual, tt.at.String())
+	}
+
+	samples, err := a.Sample(k, 2*time.Second, underlying)
+	assert.NoError(t, err)
+	assert.Nil(t, samples)
+
+	a.Name = "none"
+	samples, err = a.Sample(k, time.Second, underlying)
+	assert.NoError(t, err)
+	assert.Nil(t, samples)
+
+	a.Name = "move"
+	_, err = a.Sample(Keyframes{Name: "bad", Keyframes: []Keyframe{{Declarations: map[string]string{"width": "red"}}}}, 0, nil)
+	assert.Error(t, err)
+	_, err = a.Sample(Keyframes{Name: "content", Keyframes: []Keyframe{{Declarations: map[string]string{"content": "'a'"}}}}, 0, nil)
+	assert.Error(t, err, "known properties without a handler can't be sampled")
+}
+
+func TestAnimationSampleOpacity(t *testing.T) {
+	keyframes, err := UnmarshalKeyframes([]byte(`
+@keyframes fade {
+	from { opacity: 0; outline-width: 0; box-shadow: 0 0 red }
+	50% { opacity: 100% }
+	to { opacity: .5; outline-width: 10px; box-shadow: 4px 8px red }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	style, err := CSSStyle("animation", map[string]string{"animation": "fade 2s linear"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := style.Value.([]Animation)[0]
+
+	cases := []struct {
+		at       time.Duration
+		expected map[string]string
+	}{
+		{0, map[string]string{"opacity": "0", "outline-width": "0px", "box-shadow": "[#ff0000 0px 0px 0 0]"}},
+		{500 * time.Millisecond, map[string]string{"opacity": "0.5", "outline-width": "2.5px", "box-shadow": "[#ff0000 1px 2px 0 0]"}},
+		{time.Second, map[string]string{"opacity": "1", "outline-width": "5px", "box-shadow": "[#ff0000 2px 4px 0 0]"}},
+		{1500 * time.Millisecond, map[string]string{"opacity": "0.75", "outline-width": "7.5px", "box-shadow": "[#ff0000 3px 6px 0 0]"}},
+	}
+	for _, tt := range cases {
+		samples, err := a.Sample(keyframes["fade"], tt.at, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := make(map[string]string)
+		for name, s := range samples {
+			actual[name] = s.String()
+		}
+		assert.Equal(t, tt.expected, actual, tt.at.String())
+	}
+}
@@ -0,0 +1,190 @@
+package css
+
+import "strings"
+
+// Background is the typed value of the background shorthand.
+type Background struct {
+	Layers []BackgroundLayer
+	Color  Color
+}
+
+// BackgroundLayer is one of the comma separated layers of a background.
+type BackgroundLayer struct {
+	Image    Image
+	Position Position
+	Size     BackgroundSize
+	Repeat   BackgroundRepeat
+	// Attachment is "scroll", "fixed" or "local".
+	Attachment string
+	// Origin and Clip are "border-box", "padding-box" or "content-box".
+	Origin string
+	Clip   string
+}
+
+// BackgroundSize is a typed <bg-size>. Keyword is "cover" or "contain", or
+// empty if the size is given by Width and Height, which may be auto.
+type BackgroundSize struct {
+	Keyword       string
+	Width, Height Length
+}
+
+func (s BackgroundSize) String() string {
+	switch {
+	case s.Keyword != "":
+		return s.Keyword
+	case s.Height.Is("auto"):
+		return s.Width.String()
+	}
+	return s.Width.String() + " " + s.Height.String()
+}
+
+// BackgroundRepeat is a <repeat-style> with both axes spelled out, e.g.
+// repeat-x is {X: "repeat", Y: "no-repeat"}.
+type BackgroundRepeat struct {
+	X, Y string
+}
+
+func (r BackgroundRepeat) String() string {
+	switch {
+	case r.X == r.Y:
+		return r.X
+	case r == BackgroundRepeat{"repeat", "no-repeat"}:
+		return "repeat-x"
+	case r == BackgroundRepeat{"no-repeat", "repeat"}:
+		return "repeat-y"
+	}
+	return r.X + " " + r.Y
+}
+
+func initialBackgroundLayer() BackgroundLayer {
+	return BackgroundLayer{
+		Position:   Position{XEdge: "left", X: percent(0), YEdge: "top", Y: percent(0)},
+		Size:       BackgroundSize{Width: Length{Keyword: "auto"}, Height: Length{Keyword: "auto"}},
+		Repeat:     BackgroundRepeat{"repeat", "repeat"},
+		Attachment: "scroll",
+		Origin:     "padding-box",
+		Clip:       "border-box",
+	}
+}
+
+// background converts the background shorthand. Longhands that a layer
+// omits have their initial value.
+func background(values []ComponentValue) interface{} {
+	var bg Background
+	for _, layer := range splitCommas(values) {
+		productionGrammars["final-bg-layer"].MatchValues(layer)
+		l := initialBackgroundLayer()
+		var position, size, repeat, boxes []ComponentValue
+		for _, v := range layer {
+			switch v.Production {
+			case "bg-image":
+				l.Image = imageOf(v)
+			case "bg-position":
+				position = append(position, v)
+			case "bg-size":
+				size = append(size, v)
+			case "repeat-style":
+				repeat = append(repeat, v)
+			case "attachment":
+				l.Attachment = strings.ToLower(v.Text)
+			case "visual-box":
+				boxes = append(boxes, v)
+			case "background-color":
+				bg.Color = colorOf(v)
+			}
+		}
+		if position != nil {
+			l.Position = positionOf(position)
+		}
+		if size != nil {
+			l.Size = backgroundSizeOf(size)
+		}
+		if repeat != nil {
+			l.Repeat = backgroundRepeatOf(repeat)
+		}
+		// a single box sets both the origin and the clip
+		if len(boxes) > 0 {
+			l.Origin, l.Clip = strings.ToLower(boxes[0].Text), strings.ToLower(boxes[len(boxes)-1].Text)
+		}
+		bg.Layers = append(bg.Layers, l)
+	}
+	return bg
+}
+
+func backgroundImages(values []ComponentValue) interface{} {
+	var images []Image
+	for _, layer := range splitCommas(values) {
+		images = append(images, imageOf(layer[0]))
+	}
+	return images
+}
+
+func backgroundPositions(values []ComponentValue) interface{} {
+	var positions []Position
+	for _, layer := range splitCommas(values) {
+		positions = append(positions, positionOf(layer))
+	}
+	return positions
+}
+
+func backgroundSizes(values []ComponentValue) interface{} {
+	var sizes []BackgroundSize
+	for _, layer := range splitCommas(values) {
+		sizes = append(sizes, backgroundSizeOf(layer))
+	}
+	return sizes
+}
+
+func backgroundSizeOf(values []ComponentValue) BackgroundSize {
+	if values[0].Is("cover") || values[0].Is("contain") {
+		return BackgroundSize{Keyword: strings.ToLower(values[0].Text)}
+	}
+	size := BackgroundSize{Width: lengthOf(values[0]), Height: Length{Keyword: "auto"}}
+	if len(values) > 1 {
+		size.Height = lengthOf(values[1])
+	}
+	return size
+}
+
+func backgroundRepeats(values []ComponentValue) interface{} {
+	var repeats []BackgroundRepeat
+	for _, layer := range splitCommas(values) {
+		repeats = append(repeats, backgroundRepeatOf(layer))
+	}
+	return repeats
+}
+
+func backgroundRepeatOf(values []ComponentValue) BackgroundRepeat {
+	x := strings.ToLower(values[0].Text)
+	switch x {
+	case "repeat-x":
+		return BackgroundRepeat{"repeat", "no-repeat"}
+	case "repeat-y":
+		return BackgroundRepeat{"no-repeat", "repeat"}
+	}
+	if len(values) == 1 {
+		return BackgroundRepeat{x, x}
+	}
+	return BackgroundRepeat{x, strings.ToLower(values[1].Text)}
+}
+
+// expandBackground sets every longhand to the comma separated values of
+// the layers.
+func expandBackground(values []ComponentValue) map[string]string {
+	bg := background(values).(Background)
+	lists := make(map[string][]string)
+	for _, l := range bg.Layers {
+		lists["background-image"] = append(lists["background-image"], l.Image.String())
+		lists["background-position"] = append(lists["background-position"], l.Position.String())
+		lists["background-size"] = append(lists["background-size"], l.Size.String())
+		lists["background-repeat"] = append(lists["background-repeat"], l.Repeat.String())
+		lists["background-attachment"] = append(lists["background-attachment"], l.Attachment)
+		lists["background-origin"] = append(lists["background-origin"], l.Origin)
+		lists["background-clip"] = append(lists["background-clip"], l.Clip)
+	}
+	longhands := map[string]string{"background-color": bg.Color.String()}
+	for name, list := range lists {
+		longhands[name] = strings.Join(list, ", ")
+	}
+	return longhands
+}
@@ -0,0 +1,176 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundPosition(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"left", "0% 50%"},
+		{"top", "50% 0%"},
+		{"center", "50% 50%"},
+		{"10px", "10px 50%"},
+		{"10px 20%", "10px 20%"},
+		{"left 10px", "0% 10px"},
+		{"top right", "right 0% top 0%"},
+		{"center bottom", "left 50% bottom 0%"},
+		{"right 10px bottom", "right 10px bottom 0%"},
+		{"left 5% top 10px", "5% 10px"},
+		{"bottom 10px center", "left 50% bottom 10px"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			style, err := CSSStyle("background-position", map[string]string{"background-position": tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			positions := style.Value.([]Position)
+			assert.Equal(t, tt.expected, positions[0].String())
+		})
+	}
+}
+
+func TestBackgroundLonghands(t *testing.T) {
+	style, err := CSSStyle("background-image", map[string]string{"background-image": "url(a.png), none, linear-gradient(to right, red, blue 50%)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := style.Value.([]Image)
+	assert.Len(t, images, 3)
+	assert.Equal(t, "a.png", images[0].URL)
+	assert.True(t, images[1].IsNone())
+	assert.Equal(t, "linear", images[2].Gradient.Kind)
+	assert.Equal(t, 90.0, images[2].Gradient.Angle)
+
+	style, err = CSSStyle("background-repeat", map[string]string{"background-repeat": "repeat-x, space round, no-repeat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []BackgroundRepeat{{"repeat", "no-repeat"}, {"space", "round"}, {"no-repeat", "no-repeat"}}, style.Value)
+
+	style, err = CSSStyle("background-size", map[string]string{"background-size": "cover, 50%, 10px auto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []BackgroundSize{
+		{Keyword: "cover"},
+		{Width: percent(50), Height: Length{Keyword: "auto"}},
+		{Width: Length{Value: 10, Unit: "px"}, Height: Length{Keyword: "auto"}},
+	}, style.Value)
+
+	style, err = CSSStyle("background-attachment", map[string]string{"background-attachment": "FIXED, local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"fixed", "local"}, style.Value)
+
+	style, err = CSSStyle("background-clip", map[string]string{"background-clip": "inherit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, CSSWideKeyword("inherit"), style.Value)
+
+	for name, value := range map[string]string{
+		"background-size":   "-1px",
+		"background-origin": "margin-box",
+		"background-repeat": "repeat-x repeat-y",
+		"background-image":  "linear-gradient(to middle, red, blue)",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected Gradient
+	}{
+		{"linear-gradient(red, blue)", Gradient{Kind: "linear", Angle: 180, Position: centerPosition, Stops: []ColorStop{
+			{Color: Color{R: 1, A: 1}},
+			{Color: Color{B: 1, A: 1}},
+		}}},
+		{"repeating-linear-gradient(to top left, red 10px, 30%, blue 40% 60%)", Gradient{Kind: "linear", Repeating: true, Angle: 315, Corner: "top left", Position: centerPosition, Stops: []ColorStop{
+			{Color: Color{R: 1, A: 1}, Position: &Length{Value: 10, Unit: "px"}},
+			{Position: &Length{Value: 30, Unit: "%"}, Hint: true},
+			{Color: Color{B: 1, A: 1}, Position: &Length{Value: 40, Unit: "%"}},
+			{Color: Color{B: 1, A: 1}, Position: &Length{Value: 60, Unit: "%"}},
+		}}},
+		{"radial-gradient(10px at left top, red, blue)", Gradient{Kind: "radial", Shape: "circle", Size: []Length{{Value: 10, Unit: "px"}},
+			Position: Position{XEdge: "left", X: percent(0), YEdge: "top", Y: percent(0)}, Stops: []ColorStop{
+				{Color: Color{R: 1, A: 1}},
+				{Color: Color{B: 1, A: 1}},
+			}}},
+		{"conic-gradient(from 0.25turn, red, blue)", Gradient{Kind: "conic", Angle: 90, Position: centerPosition, Stops: []ColorStop{
+			{Color: Color{R: 1, A: 1}},
+			{Color: Color{B: 1, A: 1}},
+		}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			values, err := productionGrammars["image"].Match(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := imageOf(values[0]).Gradient
+			g.src = ""
+			assert.Equal(t, &tt.expected, g)
+		})
+	}
+}
+
+func TestBackground(t *testing.T) {
+	style, err := CSSStyle("background", map[string]string{"background": "url(a.png) right 10px top / contain no-repeat fixed content-box, #00f"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bg := style.Value.(Background)
+	assert.Len(t, bg.Layers, 2)
+	assert.Equal(t, BackgroundLayer{
+		Image:      Image{URL: "a.png"},
+		Position:   Position{XEdge: "right", X: Length{Value: 10, Unit: "px"}, YEdge: "top", Y: percent(0)},
+		Size:       BackgroundSize{Keyword: "contain"},
+		Repeat:     BackgroundRepeat{"no-repeat", "no-repeat"},
+		Attachment: "fixed",
+		Origin:     "content-box",
+		Clip:       "content-box",
+	}, bg.Layers[0])
+	assert.Equal(t, initialBackgroundLayer(), bg.Layers[1])
+	assert.Equal(t, Color{B: 1, A: 1}, bg.Color)
+}
+
+func TestExpandBackground(t *testing.T) {
+	longhands, err := ExpandShorthand("background", "url(a.png) center / 50% repeat-y padding-box border-box, linear-gradient(red, blue) red")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"background-image":      `url("a.png"), linear-gradient(red, blue)`,
+		"background-position":   "50% 50%, 0% 0%",
+		"background-size":       "50%, auto",
+		"background-repeat":     "repeat-y, repeat",
+		"background-attachment": "scroll, scroll",
+		"background-origin":     "padding-box, padding-box",
+		"background-clip":       "border-box, border-box",
+		"background-color":      "#ff0000",
+	}, longhands)
+
+	longhands, err = ExpandShorthand("background", "inherit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, longhands, 8)
+	assert.Equal(t, "inherit", longhands["background-size"])
+
+	_, err = ExpandShorthand("background", "red, blue")
+	assert.Error(t, err)
+	_, err = ExpandShorthand("color", "red")
+	assert.Error(t, err)
+}
@@ -0,0 +1,604 @@
+package css
+
+// Element is an element of a document tree laid out by BlockLayout.
+type Element struct {
+	Style map[string]string
+	// MinContent and MaxContent are the intrinsic sizes of the content of
+	// an element without children, like an image or a run of text. The
+	// height of the content is MaxContent.Height.
+	MinContent, MaxContent Size
+	Children               []*Element
+}
+
+// Box is a box of the box tree generated by BlockLayout. Its rectangles are
+// relative to the initial containing block.
+type Box struct {
+	Element                          *Element
+	Content, Padding, Border, Margin Rect
+	Children                         []*Box
+}
+
+// BlockLayout lays out the element tree of root in a block formatting
+// context, with viewport as the initial containing block, following
+// CSS 2.1 chapter 10. It collapses vertical margins, resolves auto margins
+// and sizes, and positions relatively and absolutely positioned boxes.
+//
+// Floats and line layout aren't supported: every element that generates a
+// box is laid out as a block-level box. BlockLayout returns nil if root
+// generates no box.
+func BlockLayout(root *Element, viewport Size) (*Box, error) {
+	l := &blockLayout{viewport: viewport}
+	boxes, err := l.blockBoxes([]*Element{root}, nil, viewport.Width)
+	if err != nil || len(boxes) == 0 {
+		return nil, err
+	}
+	b := boxes[0]
+	// the root element's margins don't collapse
+	b.context = true
+	b.position = PositionStatic
+	if err := b.layoutWidth(viewport.Width); err != nil {
+		return nil, err
+	}
+	if _, err := l.layoutBlock(b, viewport.Height); err != nil {
+		return nil, err
+	}
+	b.box.Border.X, b.box.Border.Y = b.used[3], b.used[0]
+	b.place(0, 0)
+
+	// absolutely positioned boxes are laid out once their containing blocks
+	// are, and may add more of them
+	for i := 0; i < len(l.absolute); i++ {
+		if err := l.layoutAbsolute(l.absolute[i]); err != nil {
+			return nil, err
+		}
+	}
+	return b.box, nil
+}
+
+// blockLayout is the state of a BlockLayout call.
+type blockLayout struct {
+	viewport Size
+	// absolute are the absolutely positioned boxes in tree order.
+	absolute []*blockBox
+}
+
+// blockBox is a box during block layout. Until it is placed, the border box
+// of box is relative to the content box of its parent.
+type blockBox struct {
+	*layoutBox
+	element  *Element
+	box      *Box
+	parent   *blockBox
+	children []*blockBox
+	position PositionScheme
+	// context is set for boxes that establish a block formatting context,
+	// whose margins don't collapse with those of their children.
+	context bool
+	// used are the used margins in top, right, bottom, left order.
+	used [4]float64
+	// relative is the offset of a relatively positioned box, and static
+	// the static position of an absolutely positioned one.
+	relative, static [2]float64
+}
+
+// marginCollapse is a set of adjoining margins, which collapse into the
+// sum of the largest positive and the smallest negative margin.
+type marginCollapse struct {
+	positive, negative float64
+}
+
+func (m *marginCollapse) add(margin float64) {
+	if margin > m.positive {
+		m.positive = margin
+	}
+	if margin < m.negative {
+		m.negative = margin
+	}
+}
+
+func (m *marginCollapse) merge(o marginCollapse) {
+	m.add(o.positive)
+	m.add(o.negative)
+}
+
+func (m marginCollapse) value() float64 {
+	return m.positive + m.negative
+}
+
+// blockFlow is the result of laying out the children of a block box.
+type blockFlow struct {
+	// height is the height of the content.
+	height float64
+	// top and bottom are the margins of the children that collapse
+	// through the top and bottom edges of the box.
+	top, bottom marginCollapse
+	// empty is set if the box has no in-flow content.
+	empty bool
+}
+
+// blockBoxes returns the boxes generated by elements. Elements with
+// display: none generate no box, and those with display: contents the
+// boxes of their children.
+func (l *blockLayout) blockBoxes(elements []*Element, parent *blockBox, containingWidth float64) ([]*blockBox, error) {
+	var boxes []*blockBox
+	for _, el := range elements {
+		lb, err := newLayoutBox(el.Style, containingWidth)
+		if err != nil {
+			return nil, err
+		}
+		d, err := lb.value("display")
+		if err != nil {
+			return nil, err
+		}
+		display, _ := d.(Display)
+		if display.IsNone() {
+			continue
+		}
+		if display.Box == "contents" {
+			children, err := l.blockBoxes(el.Children, parent, containingWidth)
+			if err != nil {
+				return nil, err
+			}
+			boxes = append(boxes, children...)
+			continue
+		}
+
+		b := &blockBox{layoutBox: lb, element: el, box: &Box{Element: el}, parent: parent}
+		if p, err := lb.value("position"); err != nil {
+			return nil, err
+		} else if p, ok := p.(PositionScheme); ok {
+			b.position = p
+		}
+		overflow, err := keywordOr(lb, "overflow-y", "visible")
+		if err != nil {
+			return nil, err
+		}
+		f, err := lb.value("float")
+		if err != nil {
+			return nil, err
+		}
+		switch display.Inside {
+		case "flow-root", "flex", "grid", "table":
+			b.context = true
+		}
+		if overflow != "visible" && overflow != "clip" || b.position.IsAbsolute() || f != nil && f != FloatNone {
+			b.context = true
+		}
+		boxes = append(boxes, b)
+	}
+	return boxes, nil
+}
+
+// layoutWidth resolves the used width and horizontal margins of an in-flow
+// box, following CSS 2.1 section 10.3.3.
+func (b *blockBox) layoutWidth(containingWidth float64) error {
+	edges := b.edges(1, 3)
+	width, err := b.size("width", containingWidth, edges)
+	if err != nil {
+		return err
+	}
+	min, err := b.size("min-width", containingWidth, edges)
+	if err != nil {
+		return err
+	}
+	max, err := b.size("max-width", containingWidth, edges)
+	if err != nil {
+		return err
+	}
+	b.solveWidth(width, containingWidth)
+	if max.definite() && b.box.Border.Width > max.px {
+		b.solveWidth(max, containingWidth)
+	}
+	if min.definite() && b.box.Border.Width < min.px {
+		b.solveWidth(min, containingWidth)
+	}
+	return nil
+}
+
+func (b *blockBox) solveWidth(width resolvedLength, containingWidth float64) {
+	left, right := b.margin[3], b.margin[1]
+	edges := b.edges(1, 3)
+	if !width.definite() {
+		width.px = containingWidth - left - right
+		if width.px < edges {
+			width.px = edges
+		}
+	}
+	free := containingWidth - left - width.px - right
+	switch {
+	case width.definite() && free > 0 && b.autoMargin[1] && b.autoMargin[3]:
+		left += free / 2
+		right += free / 2
+	case width.definite() && free > 0 && b.autoMargin[3]:
+		left += free
+	default:
+		// over-constrained, margin-right is ignored
+		right += free
+	}
+	b.box.Border.Width = width.px
+	b.used[1], b.used[3] = right, left
+}
+
+// layoutBlock lays out the children of an in-flow box and resolves its
+// height, following CSS 2.1 section 10.6.3.
+func (l *blockLayout) layoutBlock(b *blockBox, containingHeight float64) (blockFlow, error) {
+	b.used[0], b.used[2] = b.margin[0], b.margin[2]
+	edges := b.edges(0, 2)
+	height, err := b.size("height", containingHeight, edges)
+	if err != nil {
+		return blockFlow{}, err
+	}
+	min, err := b.size("min-height", containingHeight, edges)
+	if err != nil {
+		return blockFlow{}, err
+	}
+	max, err := b.size("max-height", containingHeight, edges)
+	if err != nil {
+		return blockFlow{}, err
+	}
+	contentHeight := Indefinite
+	if height.definite() {
+		contentHeight = clampSize(height.px, min, max) - edges
+	}
+	flow, err := l.layoutChildren(b, contentHeight)
+	if err != nil {
+		return blockFlow{}, err
+	}
+	if !height.definite() {
+		height.px = flow.height + edges
+	}
+	b.box.Border.Height = clampSize(height.px, min, max)
+
+	// the top and bottom margins of an empty box adjoin, otherwise the
+	// margins of its children collapse with only one of them
+	if flow.empty && (b.box.Border.Height != 0 || b.context) {
+		if b.padding[0]+b.border[0] == 0 {
+			flow.bottom = marginCollapse{}
+		}
+		flow.empty = false
+	}
+	return flow, nil
+}
+
+// layoutChildren lays out the children of b in normal flow, collapsing
+// their margins. contentHeight is the height of the content box of b, or
+// Indefinite if it depends on the children.
+func (l *blockLayout) layoutChildren(b *blockBox, contentHeight float64) (blockFlow, error) {
+	contentWidth := b.box.Border.Width - b.edges(1, 3)
+	children, err := l.blockBoxes(b.element.Children, b, contentWidth)
+	if err != nil {
+		return blockFlow{}, err
+	}
+	collapseTop := !b.context && b.padding[0]+b.border[0] == 0
+	collapseBottom := !b.context && b.padding[2]+b.border[2] == 0 && contentHeight == Indefinite
+
+	flow := blockFlow{empty: true}
+	// escaping is set while the margins of the children collapse with the
+	// top margin of b
+	escaping := collapseTop
+	var pending marginCollapse
+	cursor := 0.0
+	for _, c := range children {
+		b.children = append(b.children, c)
+		if c.position.IsAbsolute() {
+			c.static[1] = cursor + pending.value()
+			if escaping {
+				c.static[1] = 0
+			}
+			l.absolute = append(l.absolute, c)
+			continue
+		}
+		if err := c.layoutWidth(contentWidth); err != nil {
+			return blockFlow{}, err
+		}
+		if err := c.relativeOffset(contentWidth, contentHeight); err != nil {
+			return blockFlow{}, err
+		}
+		child, err := l.layoutBlock(c, contentHeight)
+		if err != nil {
+			return blockFlow{}, err
+		}
+		c.box.Border.X = c.used[3]
+		pending.add(c.used[0])
+		pending.merge(child.top)
+		if child.empty {
+			// the margins collapse through the box, which is placed as if
+			// it had a bottom border
+			c.box.Border.Y = cursor + pending.value()
+			if escaping {
+				c.box.Border.Y = 0
+			}
+			pending.add(c.used[2])
+			pending.merge(child.bottom)
+			continue
+		}
+
+		flow.empty = false
+		if escaping {
+			flow.top = pending
+			escaping = false
+			c.box.Border.Y = 0
+		} else {
+			c.box.Border.Y = cursor + pending.value()
+		}
+		cursor = c.box.Border.Y + c.box.Border.Height
+		pending = child.bottom
+		pending.add(c.used[2])
+	}
+
+	switch {
+	case len(b.element.Children) == 0:
+		flow.height = b.element.MaxContent.Height
+		flow.empty = flow.height == 0
+	case escaping:
+		flow.top = pending
+		if collapseBottom {
+			flow.bottom = pending
+		}
+	case collapseBottom:
+		flow.bottom = pending
+		flow.height = cursor
+	default:
+		flow.height = cursor + pending.value()
+	}
+	return flow, nil
+}
+
+// relativeOffset resolves the offset of a relatively positioned box.
+func (b *blockBox) relativeOffset(containingWidth, containingHeight float64) error {
+	if b.position != PositionRelative {
+		return nil
+	}
+	offsets, err := b.offsets(containingWidth, containingHeight)
+	if err != nil {
+		return err
+	}
+	if offsets[3].definite() {
+		b.relative[0] = offsets[3].px
+	} else if offsets[1].definite() {
+		b.relative[0] = -offsets[1].px
+	}
+	if offsets[0].definite() {
+		b.relative[1] = offsets[0].px
+	} else if offsets[2].definite() {
+		b.relative[1] = -offsets[2].px
+	}
+	return nil
+}
+
+// offsets resolves the top, right, bottom and left properties.
+func (b *blockBox) offsets(containingWidth, containingHeight float64) (offsets [4]resolvedLength, err error) {
+	for i, side := range physicalSides {
+		base := containingWidth
+		if i%2 == 0 {
+			base = containingHeight
+		}
+		if offsets[i], err = b.length(side, base); err != nil {
+			return offsets, err
+		}
+	}
+	return offsets, nil
+}
+
+// place makes the border box of b and its in-flow descendants relative to
+// the initial containing block, given the position of the content box of
+// its parent, and sets their other rectangles.
+func (b *blockBox) place(x, y float64) {
+	r := &b.box.Border
+	r.X += x + b.relative[0]
+	r.Y += y + b.relative[1]
+	b.box.Padding = Rect{r.X + b.border[3], r.Y + b.border[0], r.Width - b.border[1] - b.border[3], r.Height - b.border[0] - b.border[2]}
+	p := b.box.Padding
+	b.box.Content = Rect{p.X + b.padding[3], p.Y + b.padding[0], p.Width - b.padding[1] - b.padding[3], p.Height - b.padding[0] - b.padding[2]}
+	b.box.Margin = Rect{r.X - b.used[3], r.Y - b.used[0], r.Width + b.used[1] + b.used[3], r.Height + b.used[0] + b.used[2]}
+
+	c := b.box.Content
+	for _, child := range b.children {
+		b.box.Children = append(b.box.Children, child.box)
+		if child.position.IsAbsolute() {
+			child.static[0] += c.X
+			child.static[1] += c.Y
+			continue
+		}
+		child.place(c.X, c.Y)
+	}
+}
+
+// containingBlock returns the padding box of the nearest positioned
+// ancestor of an absolutely positioned box, or the initial containing block.
+func (l *blockLayout) containingBlock(b *blockBox) Rect {
+	if b.position == PositionAbsolute {
+		for p := b.parent; p != nil; p = p.parent {
+			if p.position != PositionStatic {
+				return p.box.Padding
+			}
+		}
+	}
+	return Rect{Width: l.viewport.Width, Height: l.viewport.Height}
+}
+
+// layoutAbsolute lays out an absolutely positioned box, following CSS 2.1
+// sections 10.3.7 and 10.6.4.
+func (l *blockLayout) layoutAbsolute(b *blockBox) error {
+	cb := l.containingBlock(b)
+	var err error
+	if b.layoutBox, err = newLayoutBox(b.element.Style, cb.Width); err != nil {
+		return err
+	}
+	offsets, err := b.offsets(cb.Width, cb.Height)
+	if err != nil {
+		return err
+	}
+
+	edges := b.edges(1, 3)
+	minContent, maxContent, err := l.intrinsicWidths(b.element)
+	if err != nil {
+		return err
+	}
+	shrinkToFit := func(available float64) float64 {
+		if available > maxContent {
+			available = maxContent
+		}
+		if available < minContent {
+			available = minContent
+		}
+		return available + edges
+	}
+	horizontal := absoluteAxis{start: offsets[3], end: offsets[1], static: b.static[0] - cb.X, horizontal: true}
+	horizontal.margin = [2]float64{b.margin[3], b.margin[1]}
+	horizontal.auto = [2]bool{b.autoMargin[3], b.autoMargin[1]}
+	if horizontal.size, err = b.size("width", cb.Width, edges); err != nil {
+		return err
+	}
+	if err := horizontal.constrain(b, "width", cb.Width, edges, shrinkToFit); err != nil {
+		return err
+	}
+	b.box.Border.X = horizontal.offset + horizontal.margin[0]
+	b.box.Border.Width = horizontal.used
+	b.used[3], b.used[1] = horizontal.margin[0], horizontal.margin[1]
+
+	edges = b.edges(0, 2)
+	laidOut := false
+	var flowErr error
+	contentHeight := func(float64) float64 {
+		var flow blockFlow
+		flow, flowErr = l.layoutChildren(b, Indefinite)
+		laidOut = true
+		return flow.height + edges
+	}
+	vertical := absoluteAxis{start: offsets[0], end: offsets[2], static: b.static[1] - cb.Y}
+	vertical.margin = [2]float64{b.margin[0], b.margin[2]}
+	vertical.auto = [2]bool{b.autoMargin[0], b.autoMargin[2]}
+	if vertical.size, err = b.size("height", cb.Height, edges); err != nil {
+		return err
+	}
+	if err := vertical.constrain(b, "height", cb.Height, edges, contentHeight); err != nil {
+		return err
+	}
+	if flowErr != nil {
+		return flowErr
+	}
+	b.box.Border.Y = vertical.offset + vertical.margin[0]
+	b.box.Border.Height = vertical.used
+	b.used[0], b.used[2] = vertical.margin[0], vertical.margin[1]
+	if !laidOut {
+		if _, err := l.layoutChildren(b, vertical.used-edges); err != nil {
+			return err
+		}
+	}
+	b.place(cb.X, cb.Y)
+	return nil
+}
+
+// absoluteAxis solves the constraint that the offsets, margins and border
+// box size of an absolutely positioned box along one axis add up to the
+// size of its containing block.
+type absoluteAxis struct {
+	start, end, size resolvedLength
+	margin           [2]float64
+	auto             [2]bool
+	static           float64
+	horizontal       bool
+	// offset and used are the used start offset and border box size.
+	offset, used float64
+}
+
+// constrain solves the constraint and then again with the min and max
+// sizes if the used size violates them. auto returns the size the box
+// takes if it depends on its content, given the available space.
+func (a *absoluteAxis) constrain(b *blockBox, name string, containing, edges float64, auto func(available float64) float64) error {
+	min, err := b.size("min-"+name, containing, edges)
+	if err != nil {
+		return err
+	}
+	max, err := b.size("max-"+name, containing, edges)
+	if err != nil {
+		return err
+	}
+	if !min.definite() {
+		min = resolvedLength{px: edges}
+	}
+	margin := a.margin
+	a.solve(containing, auto)
+	if max.definite() && a.used > max.px {
+		a.size, a.margin = max, margin
+		a.solve(containing, auto)
+	}
+	if a.used < min.px {
+		a.size, a.margin = min, margin
+		a.solve(containing, auto)
+	}
+	return nil
+}
+
+func (a *absoluteAxis) solve(containing float64, auto func(available float64) float64) {
+	start, end, size := a.start, a.end, a.size
+	a.offset, a.used = start.px, size.px
+	switch {
+	case !start.definite() && !size.definite() && !end.definite():
+		a.offset = a.static
+		a.used = auto(containing - a.static - a.margin[0] - a.margin[1])
+	case start.definite() && size.definite() && end.definite():
+		free := containing - start.px - a.margin[0] - size.px - a.margin[1] - end.px
+		switch {
+		case a.auto[0] && a.auto[1] && a.horizontal && free < 0:
+			a.margin[1] += free
+		case a.auto[0] && a.auto[1]:
+			a.margin[0] += free / 2
+			a.margin[1] += free / 2
+		case a.auto[0]:
+			a.margin[0] += free
+		case a.auto[1]:
+			a.margin[1] += free
+		}
+		// otherwise the box is over-constrained and the end offset ignored
+	case !start.definite() && !size.definite():
+		a.used = auto(containing - end.px - a.margin[0] - a.margin[1])
+		a.offset = containing - end.px - a.margin[0] - a.margin[1] - a.used
+	case !start.definite() && !end.definite():
+		a.offset = a.static
+	case !size.definite() && !end.definite():
+		a.used = auto(containing - start.px - a.margin[0] - a.margin[1])
+	case !start.definite():
+		a.offset = containing - end.px - a.margin[0] - a.margin[1] - a.used
+	case !size.definite():
+		a.used = containing - start.px - a.margin[0] - a.margin[1] - end.px
+	}
+}
+
+// intrinsicWidths returns the min-content and max-content widths of the
+// content box of el. Percentages are treated as auto.
+func (l *blockLayout) intrinsicWidths(el *Element) (min, max float64, err error) {
+	if len(el.Children) == 0 {
+		return el.MinContent.Width, el.MaxContent.Width, nil
+	}
+	children, err := l.blockBoxes(el.Children, nil, Indefinite)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, c := range children {
+		if c.position.IsAbsolute() {
+			continue
+		}
+		edges := c.edges(1, 3)
+		width, err := c.size("width", Indefinite, edges)
+		if err != nil {
+			return 0, 0, err
+		}
+		childMin, childMax := width.px, width.px
+		if !width.definite() {
+			if childMin, childMax, err = l.intrinsicWidths(c.element); err != nil {
+				return 0, 0, err
+			}
+			childMin += edges
+			childMax += edges
+		}
+		if childMin+c.margins(1, 3) > min {
+			min = childMin + c.margins(1, 3)
+		}
+		if childMax+c.margins(1, 3) > max {
+			max = childMax + c.margins(1, 3)
+		}
+	}
+	return min, max, nil
+}
@@ -0,0 +1,121 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockLayout(t *testing.T) {
+	el := func(style map[string]string, height float64, children ...*Element) *Element {
+		return &Element{Style: style, MaxContent: Size{0, height}, Children: children}
+	}
+	cases := []struct {
+		name string
+		root *Element
+		// expected are the border boxes in tree order
+		expected []Rect
+	}{
+		{
+			"sibling and parent margins collapse",
+			el(nil, 0,
+				el(map[string]string{"margin": "10px 0"}, 0,
+					el(map[string]string{"margin": "20px 0 -5px", "height": "50px"}, 0)),
+				el(map[string]string{"margin-top": "30px", "padding": "5px", "border": "1px solid"}, 20)),
+			[]Rect{{0, 0, 800, 127}, {0, 20, 800, 50}, {0, 20, 800, 50}, {0, 95, 800, 32}},
+		},
+		{
+			"margins collapse through empty boxes",
+			el(map[string]string{"padding": "1px"}, 0,
+				el(nil, 10),
+				el(map[string]string{"margin": "20px 0 40px"}, 0),
+				el(nil, 10)),
+			[]Rect{{0, 0, 800, 62}, {1, 1, 798, 10}, {1, 31, 798, 0}, {1, 51, 798, 10}},
+		},
+		{
+			"padding prevents collapsing",
+			el(nil, 0,
+				el(map[string]string{"padding-top": "1px", "margin-top": "10px"}, 0,
+					el(map[string]string{"margin-top": "20px"}, 10))),
+			[]Rect{{0, 0, 800, 41}, {0, 10, 800, 31}, {0, 31, 800, 10}},
+		},
+		{
+			"auto margins and max-width",
+			el(map[string]string{"margin": "8px"}, 0,
+				el(map[string]string{"width": "200px", "margin": "0 auto", "padding": "10px"}, 10),
+				el(map[string]string{"max-width": "50%", "box-sizing": "border-box", "padding": "0 10px"}, 10),
+				el(map[string]string{"width": "1000px", "margin-left": "auto"}, 10)),
+			[]Rect{{8, 8, 784, 50}, {290, 8, 220, 30}, {8, 38, 392, 10}, {8, 48, 1000, 10}},
+		},
+		{
+			"percentage heights and min-height",
+			el(map[string]string{"height": "100%"}, 0,
+				el(map[string]string{"height": "25%"}, 0),
+				el(map[string]string{"min-height": "20px"}, 10, el(map[string]string{"height": "50%"}, 10))),
+			[]Rect{{0, 0, 800, 600}, {0, 0, 800, 150}, {0, 150, 800, 20}, {0, 150, 800, 10}},
+		},
+		{
+			"relative and absolute positioning",
+			el(nil, 0,
+				el(map[string]string{"position": "relative", "height": "200px", "top": "10px"}, 0,
+					el(map[string]string{"position": "absolute", "left": "10px", "right": "20px", "top": "5px"}, 30),
+					el(nil, 40),
+					el(map[string]string{"position": "absolute", "right": "0", "bottom": "0", "padding": "5px"}, 0,
+						&Element{MinContent: Size{30, 10}, MaxContent: Size{100, 10}}),
+					el(map[string]string{"position": "absolute", "width": "100px", "height": "50px", "inset": "0", "margin": "auto"}, 0),
+					el(map[string]string{"position": "absolute"}, 10))),
+			[]Rect{
+				{0, 0, 800, 200}, {0, 10, 800, 200},
+				{10, 15, 770, 30}, {0, 10, 800, 40}, {690, 190, 110, 20}, {695, 195, 100, 10}, {350, 85, 100, 50}, {0, 50, 0, 10},
+			},
+		},
+		{
+			"fixed positioning and nested containing blocks",
+			el(map[string]string{"padding": "10px"}, 0,
+				el(map[string]string{"position": "absolute", "left": "50%", "top": "20px", "width": "100px", "height": "100px"}, 0,
+					el(map[string]string{"position": "fixed", "right": "0", "bottom": "0", "width": "10px", "height": "10px"}, 0),
+					el(map[string]string{"position": "absolute", "left": "10%", "bottom": "10px", "min-width": "5px"}, 20))),
+			[]Rect{{0, 0, 800, 20}, {400, 20, 100, 100}, {790, 590, 10, 10}, {410, 90, 5, 20}},
+		},
+		{
+			"display none and contents",
+			el(nil, 0,
+				el(map[string]string{"display": "none"}, 10),
+				el(map[string]string{"display": "contents", "margin": "100px"}, 0, el(nil, 10), el(nil, 20))),
+			[]Rect{{0, 0, 800, 30}, {0, 0, 800, 10}, {0, 10, 800, 20}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			box, err := BlockLayout(tt.root, Size{800, 600})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var borders []Rect
+			var walk func(*Box)
+			walk = func(b *Box) {
+				borders = append(borders, b.Border)
+				for _, c := range b.Children {
+					walk(c)
+				}
+			}
+			walk(box)
+			assert.Equal(t, tt.expected, borders)
+		})
+	}
+
+	box, err := BlockLayout(el(map[string]string{"margin": "1px 2px", "padding": "3px", "border": "4px solid", "width": "100px"}, 10), Size{800, 600})
+	if assert.NoError(t, err) {
+		assert.Equal(t, Rect{2, 1, 114, 24}, box.Border)
+		assert.Equal(t, Rect{6, 5, 106, 16}, box.Padding)
+		assert.Equal(t, Rect{9, 8, 100, 10}, box.Content)
+		assert.Equal(t, Rect{0, 0, 800, 26}, box.Margin)
+	}
+
+	box, err = BlockLayout(el(map[string]string{"display": "none"}, 0), Size{800, 600})
+	assert.NoError(t, err)
+	assert.Nil(t, box)
+	_, err = BlockLayout(el(nil, 0, el(map[string]string{"width": "-1px"}, 0)), Size{800, 600})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package css
+
+import "strings"
+
+// Border is the typed value of border, outline and of the shorthands for
+// the borders of one side, like border-top or border-inline-start.
+type Border struct {
+	Width Length
+	Style string
+	Color Color
+}
+
+var initialBorder = Border{Width: Length{Keyword: "medium"}, Style: "none", Color: Color{CurrentColor: true}}
+
+// BorderRadius is the typed value of border-radius, the horizontal and
+// vertical radii of the top-left, top-right, bottom-right and bottom-left
+// corners.
+type BorderRadius struct {
+	Horizontal, Vertical [4]Length
+}
+
+// BorderImage is the typed value of border-image.
+type BorderImage struct {
+	Source Image
+	Slice  BorderImageSlice
+	Width  [4]Length
+	Outset [4]Length
+	// Repeat holds the horizontal and the vertical repeat keyword.
+	Repeat [2]string
+}
+
+// BorderImageSlice is the typed value of border-image-slice, the inward
+// offsets from the top, right, bottom and left edges of the image.
+type BorderImageSlice struct {
+	Offsets [4]Length
+	Fill    bool
+}
+
+func initialBorderImage() BorderImage {
+	return BorderImage{
+		Slice:  BorderImageSlice{Offsets: [4]Length{percent(100), percent(100), percent(100), percent(100)}},
+		Width:  [4]Length{{Value: 1}, {Value: 1}, {Value: 1}, {Value: 1}},
+		Repeat: [2]string{"stretch", "stretch"},
+	}
+}
+
+func border(values []ComponentValue) interface{} {
+	b := initialBorder
+	for _, v := range values {
+		switch v.Production {
+		case "line-width":
+			b.Width = lengthOf(v)
+		case "line-style":
+			b.Style = strings.ToLower(v.Text)
+		case "color":
+			b.Color = colorOf(v)
+		}
+	}
+	return b
+}
+
+func borderRadius(values []ComponentValue) interface{} {
+	horizontal, vertical := values, values
+	for i, v := range values {
+		if v.IsDelim("/") {
+			horizontal, vertical = values[:i], values[i+1:]
+		}
+	}
+	return BorderRadius{Horizontal: lengthSides(horizontal), Vertical: lengthSides(vertical)}
+}
+
+func cornerRadius(values []ComponentValue) interface{} {
+	return [2]Length{lengthOf(values[0]), lengthOf(values[len(values)-1])}
+}
+
+func borderImage(values []ComponentValue) interface{} {
+	b := initialBorderImage()
+	var slice, width, outset, repeat []ComponentValue
+	for _, v := range values {
+		switch v.Production {
+		case "border-image-source":
+			b.Source = imageOf(v)
+		case "border-image-slice":
+			slice = append(slice, v)
+		case "border-image-width":
+			width = append(width, v)
+		case "border-image-outset":
+			outset = append(outset, v)
+		case "border-image-repeat":
+			repeat = append(repeat, v)
+		}
+	}
+	if slice != nil {
+		b.Slice = borderImageSlice(slice).(BorderImageSlice)
+	}
+	if width != nil {
+		b.Width = lengthSides(width)
+	}
+	if outset != nil {
+		b.Outset = lengthSides(outset)
+	}
+	if repeat != nil {
+		b.Repeat = borderImageRepeat(repeat).([2]string)
+	}
+	return b
+}
+
+func borderImageSlice(values []ComponentValue) interface{} {
+	var slice BorderImageSlice
+	offsets := values[:0:0]
+	for _, v := range values {
+		if v.Is("fill") {
+			slice.Fill = true
+		} else {
+			offsets = append(offsets, v)
+		}
+	}
+	slice.Offsets = lengthSides(offsets)
+	return slice
+}
+
+func borderImageRepeat(values []ComponentValue) interface{} {
+	return [2]string{strings.ToLower(values[0].Text), strings.ToLower(values[len(values)-1].Text)}
+}
+
+// expandBorderSides returns an expander for shorthands like border-top and
+// border-inline, which set the width, style and color of the prefixed sides.
+func expandBorderSides(prefixes ...string) func([]ComponentValue) map[string]string {
+	return func(values []ComponentValue) map[string]string {
+		b := border(values).(Border)
+		longhands := make(map[string]string, 3*len(prefixes))
+		for _, prefix := range prefixes {
+			longhands[prefix+"-width"] = b.Width.String()
+			longhands[prefix+"-style"] = b.Style
+			longhands[prefix+"-color"] = b.Color.String()
+		}
+		return longhands
+	}
+}
+
+// expandBorder sets all four sides, and resets the border image.
+func expandBorder(values []ComponentValue) map[string]string {
+	longhands := expandBorderSides("border-top", "border-right", "border-bottom", "border-left")(values)
+	for name, value := range expandBorderImage(nil) {
+		longhands[name] = value
+	}
+	return longhands
+}
+
+func outline(values []ComponentValue) interface{} {
+	o := Border{Width: Length{Keyword: "medium"}, Style: "none", Color: Color{CurrentColor: true}}
+	for _, v := range values {
+		switch v.Production {
+		case "outline-width":
+			o.Width = lengthOf(v)
+		case "outline-style":
+			o.Style = strings.ToLower(v.Text)
+		case "outline-color":
+			o.Color = outlineColorOf(v)
+		}
+	}
+	return o
+}
+
+// outlineColorOf converts an outline color. auto is currentcolor, which it
+// computes to unless the outline style is auto.
+func outlineColorOf(v ComponentValue) Color {
+	if v.Is("auto") {
+		return Color{CurrentColor: true}
+	}
+	return colorOf(v)
+}
+
+func outlineColor(values []ComponentValue) interface{} {
+	return outlineColorOf(values[0])
+}
+
+func expandOutline(values []ComponentValue) map[string]string {
+	o := outline(values).(Border)
+	return map[string]string{
+		"outline-width": o.Width.String(),
+		"outline-style": o.Style,
+		"outline-color": o.Color.String(),
+	}
+}
+
+var radiusCorners = []string{"top-left", "top-right", "bottom-right", "bottom-left"}
+
+func expandBorderRadius(values []ComponentValue) map[string]string {
+	r := borderRadius(values).(BorderRadius)
+	longhands := make(map[string]string, 4)
+	for i, corner := range radiusCorners {
+		value := r.Horizontal[i].String()
+		if r.Vertical[i] != r.Horizontal[i] {
+			value += " " + r.Vertical[i].String()
+		}
+		longhands["border-"+corner+"-radius"] = value
+	}
+	return longhands
+}
+
+func expandBorderImage(values []ComponentValue) map[string]string {
+	b := borderImage(values).(BorderImage)
+	slice := lengthSidesString(b.Slice.Offsets)
+	if b.Slice.Fill {
+		slice += " fill"
+	}
+	repeat := b.Repeat[0]
+	if b.Repeat[1] != repeat {
+		repeat += " " + b.Repeat[1]
+	}
+	return map[string]string{
+		"border-image-source": b.Source.String(),
+		"border-image-slice":  slice,
+		"border-image-width":  lengthSidesString(b.Width),
+		"border-image-outset": lengthSidesString(b.Outset),
+		"border-image-repeat": repeat,
+	}
+}
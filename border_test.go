@@ -0,0 +1,134 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBorderStyles(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"border", "2px dashed red", Border{Width: Length{Value: 2, Unit: "px"}, Style: "dashed", Color: Color{R: 1, A: 1}}},
+		{"border-top", "Solid", Border{Width: Length{Keyword: "medium"}, Style: "solid", Color: Color{CurrentColor: true}}},
+		{"border-inline-start", "thin blue", Border{Width: Length{Keyword: "thin"}, Style: "none", Color: Color{B: 1, A: 1}}},
+		{"border-left-width", "thick", Length{Keyword: "thick"}},
+		{"border-bottom-style", "GROOVE", "groove"},
+		{"border-right-color", "#000", Color{A: 1}},
+		{"border-width", "1px 2px", [4]Length{{Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}, {Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}}},
+		{"border-style", "solid none dotted", [4]string{"solid", "none", "dotted", "none"}},
+		{"border-block-width", "thin", [2]Length{{Keyword: "thin"}, {Keyword: "thin"}}},
+		{"border-inline-style", "solid double", [2]string{"solid", "double"}},
+		{"border-top-left-radius", "10px 5%", [2]Length{{Value: 10, Unit: "px"}, percent(5)}},
+		{"border-radius", "1px 2px / 3px", BorderRadius{
+			Horizontal: [4]Length{{Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}, {Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}},
+			Vertical:   [4]Length{{Value: 3, Unit: "px"}, {Value: 3, Unit: "px"}, {Value: 3, Unit: "px"}, {Value: 3, Unit: "px"}},
+		}},
+		{"border-spacing", "1px 2px", [2]Length{{Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}}},
+		{"outline", "auto 2px", Border{Width: Length{Value: 2, Unit: "px"}, Style: "auto", Color: Color{CurrentColor: true}}},
+		{"outline", "red dotted", Border{Width: Length{Keyword: "medium"}, Style: "dotted", Color: Color{R: 1, A: 1}}},
+		{"outline-color", "auto", Color{CurrentColor: true}},
+		{"outline-offset", "-1px", Length{Value: -1, Unit: "px"}},
+		{"border-image-slice", "fill 10 20%", BorderImageSlice{Offsets: [4]Length{{Value: 10}, percent(20), {Value: 10}, percent(20)}, Fill: true}},
+		{"border-image", "url(b.png) 30 / 2px / 1 round stretch", BorderImage{
+			Source: Image{URL: "b.png"},
+			Slice:  BorderImageSlice{Offsets: [4]Length{{Value: 30}, {Value: 30}, {Value: 30}, {Value: 30}}},
+			Width:  [4]Length{{Value: 2, Unit: "px"}, {Value: 2, Unit: "px"}, {Value: 2, Unit: "px"}, {Value: 2, Unit: "px"}},
+			Outset: [4]Length{{Value: 1}, {Value: 1}, {Value: 1}, {Value: 1}},
+			Repeat: [2]string{"round", "stretch"},
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"border-top":          "1px 2px",
+		"border-left-width":   "-1px",
+		"border-style":        "solid wavy",
+		"border-inline-width": "1px 2px 3px",
+		"border-radius":       "1px / 2px / 3px",
+		"border-image":        "url(a.png) / / 2px stretch",
+		"border-image-slice":  "fill",
+		"border-spacing":      "-1px",
+		"outline-style":       "hidden",
+		"outline":             "auto auto auto",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestExpandBorder(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{"border-width", "1px 2px 3px", map[string]string{
+			"border-top-width": "1px", "border-right-width": "2px", "border-bottom-width": "3px", "border-left-width": "2px",
+		}},
+		{"border-color", "red", map[string]string{
+			"border-top-color": "#ff0000", "border-right-color": "#ff0000", "border-bottom-color": "#ff0000", "border-left-color": "#ff0000",
+		}},
+		{"border-top", "dotted", map[string]string{
+			"border-top-width": "medium", "border-top-style": "dotted", "border-top-color": "currentcolor",
+		}},
+		{"border-block", "1px solid", map[string]string{
+			"border-block-start-width": "1px", "border-block-start-style": "solid", "border-block-start-color": "currentcolor",
+			"border-block-end-width": "1px", "border-block-end-style": "solid", "border-block-end-color": "currentcolor",
+		}},
+		{"border-inline-style", "solid dashed", map[string]string{
+			"border-inline-start-style": "solid", "border-inline-end-style": "dashed",
+		}},
+		{"outline", "thin solid blue", map[string]string{
+			"outline-width": "thin", "outline-style": "solid", "outline-color": "#0000ff",
+		}},
+		{"border-radius", "10px 5% / 20px", map[string]string{
+			"border-top-left-radius": "10px 20px", "border-top-right-radius": "5% 20px",
+			"border-bottom-right-radius": "10px 20px", "border-bottom-left-radius": "5% 20px",
+		}},
+		{"border-image", "linear-gradient(red, blue) 10% fill repeat", map[string]string{
+			"border-image-source": "linear-gradient(red, blue)", "border-image-slice": "10% fill",
+			"border-image-width": "1", "border-image-outset": "0", "border-image-repeat": "repeat",
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			longhands, err := ExpandShorthand(tt.name, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, longhands)
+		})
+	}
+
+	longhands, err := ExpandShorthand("border", "thick double #00f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, longhands, 17)
+	assert.Equal(t, "thick", longhands["border-left-width"])
+	assert.Equal(t, "double", longhands["border-bottom-style"])
+	assert.Equal(t, "#0000ff", longhands["border-right-color"])
+	assert.Equal(t, "none", longhands["border-image-source"])
+	assert.Equal(t, "100%", longhands["border-image-slice"])
+}
+
+func TestCollapseSides(t *testing.T) {
+	assert.Equal(t, "1px", collapseSides([4]string{"1px", "1px", "1px", "1px"}))
+	assert.Equal(t, "1px 2px", collapseSides([4]string{"1px", "2px", "1px", "2px"}))
+	assert.Equal(t, "1px 2px 3px", collapseSides([4]string{"1px", "2px", "3px", "2px"}))
+	assert.Equal(t, "1px 2px 3px 4px", collapseSides([4]string{"1px", "2px", "3px", "4px"}))
+}
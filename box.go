@@ -0,0 +1,211 @@
+package css
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// physicalSides are the sides set by box shorthands like margin, in the
+	// order of their values.
+	physicalSides = []string{"top", "right", "bottom", "left"}
+	// logicalSides are the sides set by shorthands like margin-inline.
+	logicalSides = []string{"start", "end"}
+)
+
+// sideIndexes returns the index of the value that applies to each side if
+// n values are given for a shorthand of the given number of sides.
+func sideIndexes(n, sides int) []int {
+	if sides == 2 {
+		if n == 1 {
+			return []int{0, 0}
+		}
+		return []int{0, 1}
+	}
+	switch n {
+	case 1:
+		return []int{0, 0, 0, 0}
+	case 2:
+		return []int{0, 1, 0, 1}
+	case 3:
+		return []int{0, 1, 2, 1}
+	}
+	return []int{0, 1, 2, 3}
+}
+
+// collapseSides returns the shortest value list of a box shorthand that
+// sets the top, right, bottom and left sides.
+func collapseSides(sides [4]string) string {
+	switch {
+	case sides[1] != sides[3]:
+		return strings.Join(sides[:], " ")
+	case sides[0] != sides[2]:
+		return strings.Join(sides[:3], " ")
+	case sides[0] != sides[1]:
+		return strings.Join(sides[:2], " ")
+	}
+	return sides[0]
+}
+
+func lengthSides(values []ComponentValue) (sides [4]Length) {
+	for i, j := range sideIndexes(len(values), 4) {
+		sides[i] = lengthOf(values[j])
+	}
+	return sides
+}
+
+func lengthSidesString(sides [4]Length) string {
+	var s [4]string
+	for i, l := range sides {
+		s[i] = l.String()
+	}
+	return collapseSides(s)
+}
+
+// boxLengths converts the values of a box shorthand to an array in top,
+// right, bottom, left order. The logical variants below convert to an array
+// in start, end order.
+func boxLengths(values []ComponentValue) interface{} {
+	return lengthSides(values)
+}
+
+func boxKeywords(values []ComponentValue) interface{} {
+	var sides [4]string
+	for i, j := range sideIndexes(len(values), 4) {
+		sides[i] = strings.ToLower(values[j].Text)
+	}
+	return sides
+}
+
+func boxColors(values []ComponentValue) interface{} {
+	var sides [4]Color
+	for i, j := range sideIndexes(len(values), 4) {
+		sides[i] = colorOf(values[j])
+	}
+	return sides
+}
+
+func logicalLengths(values []ComponentValue) interface{} {
+	var sides [2]Length
+	for i, j := range sideIndexes(len(values), 2) {
+		sides[i] = lengthOf(values[j])
+	}
+	return sides
+}
+
+func logicalKeywords(values []ComponentValue) interface{} {
+	var sides [2]string
+	for i, j := range sideIndexes(len(values), 2) {
+		sides[i] = strings.ToLower(values[j].Text)
+	}
+	return sides
+}
+
+func logicalColors(values []ComponentValue) interface{} {
+	var sides [2]Color
+	for i, j := range sideIndexes(len(values), 2) {
+		sides[i] = colorOf(values[j])
+	}
+	return sides
+}
+
+func serializeLength(v ComponentValue) string {
+	return lengthOf(v).String()
+}
+
+func serializeKeyword(v ComponentValue) string {
+	return strings.ToLower(v.Text)
+}
+
+func serializeColor(v ComponentValue) string {
+	return colorOf(v).String()
+}
+
+// boxShorthand is a shorthand that sets one longhand per side, like
+// margin or border-inline-width.
+type boxShorthand struct {
+	// format is the name of the longhands, with %s for the side.
+	format    string
+	sides     []string
+	serialize func(ComponentValue) string
+}
+
+var boxShorthands = map[string]boxShorthand{
+	"border-block-color":  {"border-block-%s-color", logicalSides, serializeColor},
+	"border-block-style":  {"border-block-%s-style", logicalSides, serializeKeyword},
+	"border-block-width":  {"border-block-%s-width", logicalSides, serializeLength},
+	"border-color":        {"border-%s-color", physicalSides, serializeColor},
+	"border-inline-color": {"border-inline-%s-color", logicalSides, serializeColor},
+	"border-inline-style": {"border-inline-%s-style", logicalSides, serializeKeyword},
+	"border-inline-width": {"border-inline-%s-width", logicalSides, serializeLength},
+	"border-style":        {"border-%s-style", physicalSides, serializeKeyword},
+	"border-width":        {"border-%s-width", physicalSides, serializeLength},
+	"inset":               {"%s", physicalSides, serializeLength},
+	"inset-block":         {"inset-block-%s", logicalSides, serializeLength},
+	"inset-inline":        {"inset-inline-%s", logicalSides, serializeLength},
+	"margin":              {"margin-%s", physicalSides, serializeLength},
+	"margin-block":        {"margin-block-%s", logicalSides, serializeLength},
+	"margin-inline":       {"margin-inline-%s", logicalSides, serializeLength},
+	"padding":             {"padding-%s", physicalSides, serializeLength},
+	"padding-block":       {"padding-block-%s", logicalSides, serializeLength},
+	"padding-inline":      {"padding-inline-%s", logicalSides, serializeLength},
+}
+
+func (b boxShorthand) expand(values []ComponentValue) map[string]string {
+	longhands := make(map[string]string, len(b.sides))
+	for i, j := range sideIndexes(len(values), len(b.sides)) {
+		longhands[fmt.Sprintf(b.format, b.sides[i])] = b.serialize(values[j])
+	}
+	return longhands
+}
+
+func (b boxShorthand) collapse(longhands map[string]string) (string, error) {
+	sides := make([]string, len(b.sides))
+	keywords := 0
+	for i, side := range b.sides {
+		name := fmt.Sprintf(b.format, side)
+		value, ok := longhands[name]
+		if !ok {
+			return "", fmt.Errorf("missing %s", name)
+		}
+		values, err := ParseValue(value)
+		if err != nil {
+			return "", err
+		}
+		if isCSSWideKeyword(values) {
+			sides[i] = strings.ToLower(values[0].Text)
+			keywords++
+			continue
+		}
+		if g := propertyGrammars[name]; !g.MatchValues(values) {
+			return "", fmt.Errorf("invalid value %q for %s, expected %s", value, name, g.def)
+		}
+		sides[i] = b.serialize(values[0])
+	}
+
+	// CSS-wide keywords can only be set for all sides at once
+	for _, side := range sides {
+		if keywords > 0 && side != sides[0] {
+			return "", fmt.Errorf("can't combine CSS-wide keywords with other values")
+		}
+	}
+	if len(sides) == 2 {
+		if sides[0] == sides[1] {
+			return sides[0], nil
+		}
+		return sides[0] + " " + sides[1], nil
+	}
+	return collapseSides([4]string{sides[0], sides[1], sides[2], sides[3]}), nil
+}
+
+// CollapseShorthand is the reverse of ExpandShorthand for box shorthands
+// like margin, padding or border-width. It returns the shortest value of
+// the shorthand name that sets the given longhands.
+func CollapseShorthand(name string, longhands map[string]string) (string, error) {
+	name = strings.ToLower(name)
+	b, ok := boxShorthands[name]
+	if !ok {
+		return "", fmt.Errorf("%s is not a box shorthand property", name)
+	}
+	return b.collapse(longhands)
+}
@@ -0,0 +1,116 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarginPadding(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"margin-top", "auto", Length{Keyword: "auto"}},
+		{"margin-left", "-5%", percent(-5)},
+		{"padding-bottom", "1.5em", Length{Value: 1.5, Unit: "em"}},
+		{"margin", "1px auto", [4]Length{{Value: 1, Unit: "px"}, {Keyword: "auto"}, {Value: 1, Unit: "px"}, {Keyword: "auto"}}},
+		{"padding", "0 1px 2px", [4]Length{{}, {Value: 1, Unit: "px"}, {Value: 2, Unit: "px"}, {Value: 1, Unit: "px"}}},
+		{"margin-inline", "auto 0", [2]Length{{Keyword: "auto"}, {}}},
+		{"padding-block", "3px", [2]Length{{Value: 3, Unit: "px"}, {Value: 3, Unit: "px"}}},
+		{"padding-inline-end", "10%", percent(10)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	style, err := CSSStyle("margin-top", map[string]string{"margin-top": "calc(100% - 2em)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "calc(100% - 2em)", style.Value.(Length).String())
+
+	for name, value := range map[string]string{
+		"padding":             "auto",
+		"padding-left":        "-1px",
+		"margin":              "1px 2px 3px 4px 5px",
+		"margin-top":          "calc(1px + 2)",
+		"margin-block":        "1px 2px 3px",
+		"padding-inline":      "calc(10px * 2px)",
+		"margin-inline-end":   "none",
+		"padding-block-start": "1",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestExpandBox(t *testing.T) {
+	longhands, err := ExpandShorthand("margin", "1px auto 2%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"margin-top": "1px", "margin-right": "auto", "margin-bottom": "2%", "margin-left": "auto"}, longhands)
+
+	longhands, err = ExpandShorthand("padding-inline", "1px 2px")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"padding-inline-start": "1px", "padding-inline-end": "2px"}, longhands)
+
+	longhands, err = ExpandShorthand("padding", "unset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"padding-top": "unset", "padding-right": "unset", "padding-bottom": "unset", "padding-left": "unset"}, longhands)
+}
+
+func TestCollapseShorthand(t *testing.T) {
+	cases := []struct {
+		name      string
+		longhands map[string]string
+		expected  string
+	}{
+		{"margin", map[string]string{"margin-top": "1px", "margin-right": "1px", "margin-bottom": "1px", "margin-left": "1px"}, "1px"},
+		{"margin", map[string]string{"margin-top": "0", "margin-right": "AUTO", "margin-bottom": "0", "margin-left": "auto"}, "0 auto"},
+		{"padding", map[string]string{"padding-top": "1px", "padding-right": "2px", "padding-bottom": "3px", "padding-left": "2px"}, "1px 2px 3px"},
+		{"padding", map[string]string{"padding-top": "1px", "padding-right": "2px", "padding-bottom": "1px", "padding-left": "3px"}, "1px 2px 1px 3px"},
+		{"margin-block", map[string]string{"margin-block-start": "1em", "margin-block-end": "1em"}, "1em"},
+		{"border-color", map[string]string{"border-top-color": "red", "border-right-color": "#f00", "border-bottom-color": "red", "border-left-color": "rgb(255 0 0)"}, "#ff0000"},
+		{"margin", map[string]string{"margin-top": "inherit", "margin-right": "inherit", "margin-bottom": "inherit", "margin-left": "inherit"}, "inherit"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expected, func(t *testing.T) {
+			value, err := CollapseShorthand(tt.name, tt.longhands)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, value)
+
+			// expanding the value gives the longhands back
+			longhands, err := ExpandShorthand(tt.name, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Len(t, longhands, len(tt.longhands))
+		})
+	}
+
+	_, err := CollapseShorthand("margin", map[string]string{"margin-top": "1px"})
+	assert.EqualError(t, err, "missing margin-right")
+	_, err = CollapseShorthand("margin", map[string]string{"margin-top": "inherit", "margin-right": "1px", "margin-bottom": "1px", "margin-left": "1px"})
+	assert.Error(t, err)
+	_, err = CollapseShorthand("padding", map[string]string{"padding-top": "auto", "padding-right": "1px", "padding-bottom": "1px", "padding-left": "1px"})
+	assert.Error(t, err)
+	_, err = CollapseShorthand("font", nil)
+	assert.Error(t, err)
+}
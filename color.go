@@ -0,0 +1,373 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is an sRGB color with components in the range [0,1]. Colors given
+// in other color spaces are converted to sRGB and clipped to its gamut.
+type Color struct {
+	R, G, B, A float64
+	// CurrentColor is set for the currentcolor keyword, which takes the
+	// value of the color property.
+	CurrentColor bool
+	// Modern is set for colors given in a syntax other than the legacy sRGB
+	// ones (hex, named colors, rgb(), hsl() and hwb()), like lab(), oklch()
+	// or color(). Legacy colors interpolate in sRGB, the others in OKLab.
+	Modern bool
+}
+
+// RGBA returns the components of c as 8-bit values.
+func (c Color) RGBA() (r, g, b, a uint8) {
+	return to8bit(c.R), to8bit(c.G), to8bit(c.B), to8bit(c.A)
+}
+
+func to8bit(f float64) uint8 {
+	return uint8(math.Round(clamp(f, 0, 1) * 255))
+}
+
+// String returns c in hex notation, or in rgba() notation if it is not opaque.
+func (c Color) String() string {
+	if c.CurrentColor {
+		return "currentcolor"
+	}
+	r, g, b, _ := c.RGBA()
+	if c.A >= 1 {
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, formatNumber(math.Round(c.A*1000)/1000))
+}
+
+// ParseColor parses a CSS color, like "red", "#f00", "rgb(255 0 0 / 50%)"
+// or "oklch(60% 0.2 30)".
+func ParseColor(s string) (Color, error) {
+	values, err := ParseValue(s)
+	if err != nil {
+		return Color{}, err
+	}
+	if len(values) != 1 || !productionGrammars["color"].MatchValues(values) {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	return colorOf(values[0]), nil
+}
+
+// colorOf converts a value that matched <color> to a Color.
+func colorOf(v ComponentValue) Color {
+	switch v.Type {
+	case HashValue:
+		return hexColor(v.Text)
+	case IdentValue:
+		name := strings.ToLower(v.Text)
+		switch name {
+		case "currentcolor":
+			return Color{CurrentColor: true}
+		case "transparent":
+			return Color{}
+		}
+		return hexColor(fmt.Sprintf("%06x", namedColors[name]))
+	case FunctionValue:
+		return colorFunction(v)
+	}
+	return Color{A: 1}
+}
+
+func hexColor(hex string) Color {
+	if len(hex) <= 4 { // short notation, every digit is doubled
+		long := make([]byte, 0, 8)
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	n, _ := strconv.ParseUint(hex, 16, 32)
+	return Color{
+		R: float64(n>>24&0xff) / 255,
+		G: float64(n>>16&0xff) / 255,
+		B: float64(n>>8&0xff) / 255,
+		A: float64(n&0xff) / 255,
+	}
+}
+
+// colorChannels splits the arguments of a color function into its
+// channels and the alpha value, which is nil if it is omitted. The keyword
+// none is returned as 0.
+func colorChannels(args []ComponentValue) ([]ComponentValue, *ComponentValue) {
+	var channels []ComponentValue
+	var alpha *ComponentValue
+	for i, v := range args {
+		switch {
+		case v.IsDelim(","):
+			if len(channels) == 3 && i+1 < len(args) {
+				alpha = &args[i+1]
+			}
+			if alpha != nil {
+				return channels, alpha
+			}
+		case v.IsDelim("/"):
+			if i+1 < len(args) {
+				alpha = &args[i+1]
+			}
+			return channels, alpha
+		case v.Is("none"):
+			channels = append(channels, ComponentValue{Type: NumberValue})
+		default:
+			channels = append(channels, v)
+		}
+	}
+	return channels, alpha
+}
+
+// channel returns v as a fraction, where percentages and numbers are
+// relative to the given ranges.
+func channel(v ComponentValue, percentRange, numberRange float64) float64 {
+	if v.Type == PercentageValue {
+		return v.Number / 100 * percentRange
+	}
+	return v.Number / numberRange
+}
+
+func colorFunction(v ComponentValue) Color {
+	channels, alphaValue := colorChannels(v.Args)
+	alpha := 1.0
+	if alphaValue != nil && !alphaValue.Is("none") {
+		alpha = clamp(channel(*alphaValue, 1, 1), 0, 1)
+	}
+	var c Color
+	switch v.Text {
+	case "rgb", "rgba":
+		c = Color{
+			R: channel(channels[0], 1, 255),
+			G: channel(channels[1], 1, 255),
+			B: channel(channels[2], 1, 255),
+		}
+	case "hsl", "hsla":
+		c = hslColor(hue(channels[0]), channel(channels[1], 1, 100), channel(channels[2], 1, 100))
+	case "hwb":
+		c = hwbColor(hue(channels[0]), channel(channels[1], 1, 100), channel(channels[2], 1, 100))
+	case "lab":
+		c = labColor(channel(channels[0], 100, 1), channel(channels[1], 125, 1), channel(channels[2], 125, 1))
+	case "lch":
+		c = lchColor(channel(channels[0], 100, 1), channel(channels[1], 150, 1), hue(channels[2]))
+	case "oklab":
+		c = oklabColor(channel(channels[0], 1, 1), channel(channels[1], 0.4, 1), channel(channels[2], 0.4, 1))
+	case "oklch":
+		l, chroma, h := channel(channels[0], 1, 1), channel(channels[1], 0.4, 1), hue(channels[2])*math.Pi/180
+		c = oklabColor(l, chroma*math.Cos(h), chroma*math.Sin(h))
+	case "color":
+		c = predefinedColor(channels)
+	}
+	c.R, c.G, c.B, c.A = clamp(c.R, 0, 1), clamp(c.G, 0, 1), clamp(c.B, 0, 1), alpha
+	switch v.Text {
+	case "lab", "lch", "oklab", "oklch", "color":
+		c.Modern = true
+	}
+	return c
+}
+
+// hue returns a hue in degrees.
+func hue(v ComponentValue) float64 {
+	if v.Type == DimensionValue {
+		return angleDegrees(v)
+	}
+	return v.Number
+}
+
+func hslColor(h, s, l float64) Color {
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		if k < 0 {
+			k += 12
+		}
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(k-3, math.Min(9-k, 1)))
+	}
+	return Color{R: f(0), G: f(8), B: f(4)}
+}
+
+func hwbColor(h, white, black float64) Color {
+	if white+black >= 1 {
+		gray := white / (white + black)
+		return Color{R: gray, G: gray, B: gray}
+	}
+	c := hslColor(h, 1, 0.5)
+	scale := 1 - white - black
+	return Color{R: c.R*scale + white, G: c.G*scale + white, B: c.B*scale + white}
+}
+
+func lchColor(l, chroma, h float64) Color {
+	h *= math.Pi / 180
+	return labColor(l, chroma*math.Cos(h), chroma*math.Sin(h))
+}
+
+// labColor converts CIE Lab with a D50 white point to sRGB.
+func labColor(l, a, b float64) Color {
+	const (
+		kappa   = 24389.0 / 27
+		epsilon = 216.0 / 24389
+	)
+	fy := (l + 16) / 116
+	fx := a/500 + fy
+	fz := fy - b/200
+	f := func(t float64) float64 {
+		if t*t*t > epsilon {
+			return t * t * t
+		}
+		return (116*t - 16) / kappa
+	}
+	y := l / kappa
+	if l > kappa*epsilon {
+		y = fy * fy * fy
+	}
+	x, y, z := f(fx)*0.3457/0.3585, y, f(fz)*(1-0.3457-0.3585)/0.3585
+	return xyzD65Color(mul3(d50ToD65, [3]float64{x, y, z}))
+}
+
+func oklabColor(l, a, b float64) Color {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+	return linearColor([3]float64{
+		4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc,
+		-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc,
+		-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc,
+	})
+}
+
+// oklab converts c to the OKLab color space.
+func (c Color) oklab() (l, a, b float64) {
+	r, g, bl := linearChannel(c.R), linearChannel(c.G), linearChannel(c.B)
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	return 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc,
+		1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc,
+		0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+}
+
+func predefinedColor(channels []ComponentValue) Color {
+	space := strings.ToLower(channels[0].Text)
+	rgb := [3]float64{channel(channels[1], 1, 1), channel(channels[2], 1, 1), channel(channels[3], 1, 1)}
+	switch space {
+	case "srgb-linear":
+		return linearColor(rgb)
+	case "display-p3":
+		for i := range rgb {
+			rgb[i] = linearChannel(rgb[i])
+		}
+		return xyzD65Color(mul3(displayP3ToXYZ, rgb))
+	case "xyz", "xyz-d65":
+		return xyzD65Color(rgb)
+	case "xyz-d50":
+		return xyzD65Color(mul3(d50ToD65, rgb))
+	}
+	return Color{R: rgb[0], G: rgb[1], B: rgb[2]}
+}
+
+var (
+	d50ToD65 = [3][3]float64{
+		{0.9554734527042182, -0.023098536874261423, 0.0632593086610217},
+		{-0.028369706963208136, 1.0099954580058226, 0.021041398966943008},
+		{0.012314001688319899, -0.020507696433477912, 1.3303659366080753},
+	}
+	xyzToLinearSRGB = [3][3]float64{
+		{3.2409699419045226, -1.537383177570094, -0.4986107602930034},
+		{-0.9692436362808796, 1.8759675015077202, 0.04155505740717559},
+		{0.05563007969699366, -0.20397695888897652, 1.0569715142428786},
+	}
+	displayP3ToXYZ = [3][3]float64{
+		{0.4865709486482162, 0.26566769316909306, 0.1982172852343625},
+		{0.2289745640697488, 0.6917385218365064, 0.079286914093745},
+		{0, 0.04511338185890264, 1.043944368900976},
+	}
+)
+
+func mul3(m [3][3]float64, v [3]float64) [3]float64 {
+	return [3]float64{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
+
+func xyzD65Color(xyz [3]float64) Color {
+	return linearColor(mul3(xyzToLinearSRGB, xyz))
+}
+
+// linearColor applies the sRGB transfer function to linear light values.
+func linearColor(rgb [3]float64) Color {
+	gamma := func(c float64) float64 {
+		if math.Abs(c) <= 0.0031308 {
+			return 12.92 * c
+		}
+		return math.Copysign(1.055*math.Pow(math.Abs(c), 1/2.4)-0.055, c)
+	}
+	return Color{R: gamma(rgb[0]), G: gamma(rgb[1]), B: gamma(rgb[2])}
+}
+
+func linearChannel(c float64) float64 {
+	if math.Abs(c) <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Copysign(math.Pow((math.Abs(c)+0.055)/1.055, 2.4), c)
+}
+
+// alphaValue converts an <alpha-value>, clamped to the range [0,1].
+func alphaValue(values []ComponentValue) interface{} {
+	alpha := numberValue(values).(float64)
+	if values[0].Type == PercentageValue {
+		alpha /= 100
+	}
+	return clamp(alpha, 0, 1)
+}
+
+func clamp(f, min, max float64) float64 {
+	return math.Max(min, math.Min(max, f))
+}
+
+// namedColors maps the CSS named colors to their RGB values.
+var namedColors = map[string]uint32{
+	"aliceblue": 0xf0f8ff, "antiquewhite": 0xfaebd7, "aqua": 0x00ffff, "aquamarine": 0x7fffd4,
+	"azure": 0xf0ffff, "beige": 0xf5f5dc, "bisque": 0xffe4c4, "black": 0x000000,
+	"blanchedalmond": 0xffebcd, "blue": 0x0000ff, "blueviolet": 0x8a2be2, "brown": 0xa52a2a,
+	"burlywood": 0xdeb887, "cadetblue": 0x5f9ea0, "chartreuse": 0x7fff00, "chocolate": 0xd2691e,
+	"coral": 0xff7f50, "cornflowerblue": 0x6495ed, "cornsilk": 0xfff8dc, "crimson": 0xdc143c,
+	"cyan": 0x00ffff, "darkblue": 0x00008b, "darkcyan": 0x008b8b, "darkgoldenrod": 0xb8860b,
+	"darkgray": 0xa9a9a9, "darkgreen": 0x006400, "darkgrey": 0xa9a9a9, "darkkhaki": 0xbdb76b,
+	"darkmagenta": 0x8b008b, "darkolivegreen": 0x556b2f, "darkorange": 0xff8c00, "darkorchid": 0x9932cc,
+	"darkred": 0x8b0000, "darksalmon": 0xe9967a, "darkseagreen": 0x8fbc8f, "darkslateblue": 0x483d8b,
+	"darkslategray": 0x2f4f4f, "darkslategrey": 0x2f4f4f, "darkturquoise": 0x00ced1, "darkviolet": 0x9400d3,
+	"deeppink": 0xff1493, "deepskyblue": 0x00bfff, "dimgray": 0x696969, "dimgrey": 0x696969,
+	"dodgerblue": 0x1e90ff, "firebrick": 0xb22222, "floralwhite": 0xfffaf0, "forestgreen": 0x228b22,
+	"fuchsia": 0xff00ff, "gainsboro": 0xdcdcdc, "ghostwhite": 0xf8f8ff, "gold": 0xffd700,
+	"goldenrod": 0xdaa520, "gray": 0x808080, "green": 0x008000, "greenyellow": 0xadff2f,
+	"grey": 0x808080, "honeydew": 0xf0fff0, "hotpink": 0xff69b4, "indianred": 0xcd5c5c,
+	"indigo": 0x4b0082, "ivory": 0xfffff0, "khaki": 0xf0e68c, "lavender": 0xe6e6fa,
+	"lavenderblush": 0xfff0f5, "lawngreen": 0x7cfc00, "lemonchiffon": 0xfffacd, "lightblue": 0xadd8e6,
+	"lightcoral": 0xf08080, "lightcyan": 0xe0ffff, "lightgoldenrodyellow": 0xfafad2, "lightgray": 0xd3d3d3,
+	"lightgreen": 0x90ee90, "lightgrey": 0xd3d3d3, "lightpink": 0xffb6c1, "lightsalmon": 0xffa07a,
+	"lightseagreen": 0x20b2aa, "lightskyblue": 0x87cefa, "lightslategray": 0x778899, "lightslategrey": 0x778899,
+	"lightsteelblue": 0xb0c4de, "lightyellow": 0xffffe0, "lime": 0x00ff00, "limegreen": 0x32cd32,
+	"linen": 0xfaf0e6, "magenta": 0xff00ff, "maroon": 0x800000, "mediumaquamarine": 0x66cdaa,
+	"mediumblue": 0x0000cd, "mediumorchid": 0xba55d3, "mediumpurple": 0x9370db, "mediumseagreen": 0x3cb371,
+	"mediumslateblue": 0x7b68ee, "mediumspringgreen": 0x00fa9a, "mediumturquoise": 0x48d1cc, "mediumvioletred": 0xc71585,
+	"midnightblue": 0x191970, "mintcream": 0xf5fffa, "mistyrose": 0xffe4e1, "moccasin": 0xffe4b5,
+	"navajowhite": 0xffdead, "navy": 0x000080, "oldlace": 0xfdf5e6, "olive": 0x808000,
+	"olivedrab": 0x6b8e23, "orange": 0xffa500, "orangered": 0xff4500, "orchid": 0xda70d6,
+	"palegoldenrod": 0xeee8aa, "palegreen": 0x98fb98, "paleturquoise": 0xafeeee, "palevioletred": 0xdb7093,
+	"papayawhip": 0xffefd5, "peachpuff": 0xffdab9, "peru": 0xcd853f, "pink": 0xffc0cb,
+	"plum": 0xdda0dd, "powderblue": 0xb0e0e6, "purple": 0x800080, "rebeccapurple": 0x663399,
+	"red": 0xff0000, "rosybrown": 0xbc8f8f, "royalblue": 0x4169e1, "saddlebrown": 0x8b4513,
+	"salmon": 0xfa8072, "sandybrown": 0xf4a460, "seagreen": 0x2e8b57, "seashell": 0xfff5ee,
+	"sienna": 0xa0522d, "silver": 0xc0c0c0, "skyblue": 0x87ceeb, "slateblue": 0x6a5acd,
+	"slategray": 0x708090, "slategrey": 0x708090, "snow": 0xfffafa, "springgreen": 0x00ff7f,
+	"steelblue": 0x4682b4, "tan": 0xd2b48c, "teal": 0x008080, "thistle": 0xd8bfd8,
+	"tomato": 0xff6347, "turquoise": 0x40e0d0, "violet": 0xee82ee, "wheat": 0xf5deb3,
+	"white": 0xffffff, "whitesmoke": 0xf5f5f5, "yellow": 0xffff00, "yellowgreen": 0x9acd32,
+}
@@ -0,0 +1,57 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"red", "#ff0000"},
+		{"RebeccaPurple", "#663399"},
+		{"#abc", "#aabbcc"},
+		{"#ff000080", "rgba(255, 0, 0, 0.502)"},
+		{"transparent", "rgba(0, 0, 0, 0)"},
+		{"currentColor", "currentcolor"},
+		{"rgb(255, 128, 0)", "#ff8000"},
+		{"rgb(100% 0% 0% / 50%)", "rgba(255, 0, 0, 0.5)"},
+		{"rgba(0, 0, 255, .25)", "rgba(0, 0, 255, 0.25)"},
+		{"hsl(120deg 100% 50%)", "#00ff00"},
+		{"hsl(0.5turn, 100%, 25%)", "#008080"},
+		{"hwb(0 0% 0%)", "#ff0000"},
+		{"hwb(0 60% 60%)", "#808080"},
+		{"lab(54.29% 80.82 69.9)", "#ff0000"},
+		{"lch(100 0 0)", "#ffffff"},
+		{"oklab(62.8% 0.22488 0.12585)", "#ff0000"},
+		{"oklch(0.452 0.313 264.05)", "#0000ff"},
+		{"color(srgb 1 0.5 0)", "#ff8000"},
+		{"color(display-p3 1 0 0)", "#ff0000"},
+		{"rgb(none 255 none)", "#00ff00"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			c, err := ParseColor(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, c.String())
+		})
+	}
+
+	for _, value := range []string{"bla", "#ff", "rgb(1 2)", "red blue", ""} {
+		_, err := ParseColor(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestColorOklab(t *testing.T) {
+	l, a, b := Color{R: 1, A: 1}.oklab()
+	assert.InDelta(t, 0.628, l, 0.001)
+	assert.InDelta(t, 0.2249, a, 0.001)
+	assert.InDelta(t, 0.1258, b, 0.001)
+}
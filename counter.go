@@ -0,0 +1,911 @@
+package css
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CounterSystem is the algorithm a counter style uses to represent numbers.
+type CounterSystem int
+
+const (
+	CounterSymbolic CounterSystem = iota
+	CounterCyclic
+	CounterNumeric
+	CounterAlphabetic
+	CounterAdditive
+	CounterFixed
+)
+
+var counterSystems = []string{"symbolic", "cyclic", "numeric", "alphabetic", "additive", "fixed"}
+
+func (s CounterSystem) String() string {
+	return enumString(counterSystems, int(s))
+}
+
+// AdditiveSymbol is a symbol of an additive counter style and its weight.
+type AdditiveSymbol struct {
+	Weight int
+	Symbol string
+}
+
+// CounterStyle is a counter style defined by a @counter-style rule, a
+// predefined style like decimal, or an anonymous style from symbols().
+type CounterStyle struct {
+	Name   string
+	System CounterSystem
+	// First is the value of the first symbol of a fixed system.
+	First           int
+	Symbols         []string
+	AdditiveSymbols []AdditiveSymbol
+	NegativePrefix  string
+	NegativeSuffix  string
+	Prefix          string
+	Suffix          string
+	// Ranges are the inclusive ranges of values the style represents, or
+	// nil for the default range of the system. math.MinInt and math.MaxInt
+	// stand for infinite.
+	Ranges    [][2]int
+	Pad       int
+	PadSymbol string
+	Fallback  string
+
+	// algorithm replaces the system of the predefined styles CSS defines
+	// with a special algorithm, like cjk-ideographic. Styles extending them
+	// keep it.
+	algorithm func(n int) (string, bool)
+}
+
+func defaultCounterStyle(name string) CounterStyle {
+	return CounterStyle{Name: name, NegativePrefix: "-", Suffix: ". ", Fallback: "decimal"}
+}
+
+// maxCounterLength bounds the number of symbols of a representation, so
+// that huge values of symbolic and additive styles use the fallback style.
+// Padding beyond it is dropped.
+const maxCounterLength = 60
+
+// Represent returns the representation of n in the counter style, without
+// its prefix and suffix. It returns false if n is out of the range of the
+// style, the system can't represent it or the representation would be
+// longer than maxCounterLength symbols.
+func (s *CounterStyle) Represent(n int) (string, bool) {
+	if !s.inRange(n) {
+		return "", false
+	}
+	negative := n < 0 && s.usesNegative()
+	if negative {
+		n = -n
+	}
+	r, ok := s.represent(n)
+	if !ok {
+		return "", false
+	}
+	pad := s.Pad - utf8.RuneCountInString(r)
+	if negative {
+		pad -= utf8.RuneCountInString(s.NegativePrefix + s.NegativeSuffix)
+	}
+	if pad > maxCounterLength {
+		pad = maxCounterLength
+	}
+	if pad > 0 {
+		r = strings.Repeat(s.PadSymbol, pad) + r
+	}
+	if negative {
+		r = s.NegativePrefix + r + s.NegativeSuffix
+	}
+	return r, true
+}
+
+func (s *CounterStyle) represent(n int) (string, bool) {
+	if s.algorithm != nil {
+		return s.algorithm(n)
+	}
+	symbols := s.Symbols
+	switch {
+	case s.System == CounterAdditive:
+	case len(symbols) == 0, len(symbols) == 1 && (s.System == CounterNumeric || s.System == CounterAlphabetic):
+		// only possible for symbols() functions
+		return "", false
+	}
+	switch s.System {
+	case CounterCyclic:
+		i := (n - 1) % len(symbols)
+		if i < 0 {
+			i += len(symbols)
+		}
+		return symbols[i], true
+	case CounterFixed:
+		i := n - s.First
+		if i < 0 || i >= len(symbols) {
+			return "", false
+		}
+		return symbols[i], true
+	case CounterSymbolic:
+		if n < 1 {
+			return "", false
+		}
+		if (n-1)/len(symbols) >= maxCounterLength {
+			return "", false
+		}
+		return strings.Repeat(symbols[(n-1)%len(symbols)], (n-1)/len(symbols)+1), true
+	case CounterAlphabetic:
+		if n < 1 {
+			return "", false
+		}
+		r := ""
+		for n > 0 {
+			n--
+			r = symbols[n%len(symbols)] + r
+			n /= len(symbols)
+		}
+		return r, true
+	case CounterNumeric:
+		if n == 0 {
+			return symbols[0], true
+		}
+		r := ""
+		for n > 0 {
+			r = symbols[n%len(symbols)] + r
+			n /= len(symbols)
+		}
+		return r, true
+	case CounterAdditive:
+		var sb strings.Builder
+		length := 0
+		for _, symbol := range s.AdditiveSymbols {
+			if symbol.Weight == 0 {
+				if n == 0 && sb.Len() == 0 {
+					return symbol.Symbol, true
+				}
+				continue
+			}
+			count := n / symbol.Weight
+			if count > maxCounterLength-length {
+				return "", false
+			}
+			length += count
+			sb.WriteString(strings.Repeat(symbol.Symbol, count))
+			n -= count * symbol.Weight
+		}
+		return sb.String(), n == 0 && sb.Len() > 0
+	}
+	return "", false
+}
+
+func (s *CounterStyle) inRange(n int) bool {
+	if s.Ranges == nil {
+		switch s.System {
+		case CounterAlphabetic, CounterSymbolic:
+			return n >= 1
+		case CounterAdditive:
+			return n >= 0
+		}
+		return true
+	}
+	for _, r := range s.Ranges {
+		if n >= r[0] && n <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CounterStyle) usesNegative() bool {
+	switch s.System {
+	case CounterSymbolic, CounterAlphabetic, CounterNumeric, CounterAdditive:
+		return true
+	}
+	return false
+}
+
+// symbolsFunction returns the symbols() function of an anonymous style.
+func (s *CounterStyle) symbolsFunction() string {
+	parts := []string{s.System.String()}
+	for _, symbol := range s.Symbols {
+		parts = append(parts, strconv.Quote(symbol))
+	}
+	return "symbols(" + strings.Join(parts, " ") + ")"
+}
+
+// symbolsCounterStyle returns the anonymous counter style of a symbols()
+// function.
+func symbolsCounterStyle(v ComponentValue) *CounterStyle {
+	s := defaultCounterStyle("")
+	s.Suffix = " "
+	for _, arg := range v.Args {
+		switch arg.Type {
+		case IdentValue:
+			s.System = CounterSystem(enumIndex(counterSystems, arg))
+		case StringValue:
+			s.Symbols = append(s.Symbols, arg.Text)
+		}
+	}
+	return &s
+}
+
+// CounterStyles are counter styles by name, as defined by the @counter-style
+// rules of a stylesheet.
+type CounterStyles map[string]*CounterStyle
+
+// Lookup returns the counter style name, or the predefined style of that
+// name like decimal or lower-roman. It returns nil for unknown names.
+func (styles CounterStyles) Lookup(name string) *CounterStyle {
+	if s, ok := styles[name]; ok {
+		return s
+	}
+	return predefinedCounterStyles[strings.ToLower(name)]
+}
+
+// maxFallbacks bounds the chain of fallback styles tried for a value.
+const maxFallbacks = 8
+
+// Marker returns the marker text of list item n for the list-style-type t:
+// the representation of n with the prefix and suffix of the counter style,
+// or the string of t. Values a style can't represent use its fallback
+// style, and unknown styles are treated as decimal.
+func (styles CounterStyles) Marker(t ListStyleType, n int) string {
+	var s *CounterStyle
+	switch {
+	case t.Symbols != nil:
+		s = t.Symbols
+	case t.Name == "none":
+		return ""
+	case t.Name == "":
+		return t.Text
+	default:
+		s = styles.Lookup(t.Name)
+	}
+
+	for i := 0; s != nil && i < maxFallbacks; i++ {
+		if r, ok := s.Represent(n); ok {
+			return s.Prefix + r + s.Suffix
+		}
+		s = styles.Lookup(s.Fallback)
+	}
+	s = predefinedCounterStyles["decimal"]
+	r, _ := s.Represent(n)
+	return s.Prefix + r + s.Suffix
+}
+
+// UnmarshalCounterStyles returns the counter styles defined by the
+// @counter-style rules of a stylesheet. Styles may extend each other and
+// the predefined styles.
+func UnmarshalCounterStyles(b []byte) (CounterStyles, error) {
+	h := &counterStyleHandler{}
+	if err := parseBytes(context.Background(), b, h); err != nil {
+		return nil, err
+	}
+	for _, rule := range h.rules {
+		switch strings.ToLower(rule.name) {
+		case "none", "decimal", "disc", "square", "circle", "disclosure-open", "disclosure-closed":
+			return nil, fmt.Errorf("@counter-style can't define %s", rule.name)
+		}
+		if rule.name == "" || !isIdentifier(rule.name) || !isCustomIdent(ComponentValue{Type: IdentValue, Text: rule.name}) {
+			return nil, fmt.Errorf("invalid @counter-style name %q", rule.name)
+		}
+	}
+	return defineCounterStyles(h.rules, predefinedCounterStyles)
+}
+
+type counterStyleRule struct {
+	name        string
+	descriptors map[string]string
+}
+
+// counterStyleHandler collects the descriptors of top level @counter-style
+// rules.
+type counterStyleHandler struct {
+	NopHandler
+
+	rules []counterStyleRule
+	// descriptors of the @counter-style rule being read, if any
+	descriptors map[string]string
+	depth       int
+}
+
+func (h *counterStyleHandler) StartRule(selectors []string) error {
+	h.depth++
+	return nil
+}
+
+func (h *counterStyleHandler) EndRule() error {
+	h.depth--
+	return nil
+}
+
+func (h *counterStyleHandler) StartAtRule(name, prelude string) error {
+	h.depth++
+	if h.depth == 1 && name == "counter-style" {
+		h.descriptors = make(map[string]string)
+		h.rules = append(h.rules, counterStyleRule{strings.TrimSpace(prelude), h.descriptors})
+	}
+	return nil
+}
+
+func (h *counterStyleHandler) Declaration(name, value string, important bool) error {
+	if h.depth == 1 && h.descriptors != nil {
+		h.descriptors[strings.ToLower(name)] = value
+	}
+	return nil
+}
+
+func (h *counterStyleHandler) EndAtRule(name string) error {
+	h.depth--
+	if h.depth == 0 {
+		h.descriptors = nil
+	}
+	return nil
+}
+
+// defineCounterStyles builds the counter styles of rules. The styles they
+// extend are looked up in the rules first and then by lower case name in
+// base.
+func defineCounterStyles(rules []counterStyleRule, base CounterStyles) (CounterStyles, error) {
+	byName := make(map[string]map[string]string, len(rules))
+	for _, rule := range rules {
+		byName[rule.name] = rule.descriptors
+	}
+
+	styles := make(CounterStyles, len(byName))
+	defining := make(map[string]bool)
+	var define func(name string) (*CounterStyle, error)
+	define = func(name string) (*CounterStyle, error) {
+		if s, ok := styles[name]; ok {
+			return s, nil
+		}
+		descriptors := byName[name]
+		var parent *CounterStyle
+		if system, _ := ParseValue(descriptors["system"]); len(system) == 2 && system[0].Is("extends") {
+			parentName := system[1].Text
+			defining[name] = true
+			switch {
+			case byName[parentName] != nil && !defining[parentName]:
+				var err error
+				if parent, err = define(parentName); err != nil {
+					return nil, err
+				}
+			case byName[parentName] == nil:
+				parent = base[strings.ToLower(parentName)]
+			}
+			if parent == nil {
+				// unknown styles and cycles extend decimal
+				parent = base["decimal"]
+			}
+			delete(defining, name)
+		}
+		s, err := parseCounterStyle(name, descriptors, parent)
+		if err != nil {
+			return nil, err
+		}
+		styles[name] = s
+		return s, nil
+	}
+
+	for _, rule := range rules {
+		if _, err := define(rule.name); err != nil {
+			return nil, err
+		}
+	}
+	return styles, nil
+}
+
+// parseCounterStyle builds a counter style from the descriptors of a
+// @counter-style rule, starting from parent if it extends another style.
+func parseCounterStyle(name string, descriptors map[string]string, parent *CounterStyle) (*CounterStyle, error) {
+	s := defaultCounterStyle(name)
+	if parent != nil {
+		s = *parent
+		s.Name = name
+	}
+	for descriptor, value := range descriptors {
+		values, err := ParseValue(value)
+		if err == nil && len(values) == 0 {
+			err = fmt.Errorf("missing value")
+		}
+		if err == nil {
+			err = s.setDescriptor(descriptor, values, parent != nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q in @counter-style %s: %v", descriptor, value, name, err)
+		}
+	}
+	if parent != nil {
+		return &s, nil
+	}
+
+	switch {
+	case s.System == CounterAdditive && len(s.AdditiveSymbols) == 0:
+		return nil, fmt.Errorf("@counter-style %s needs additive-symbols", name)
+	case (s.System == CounterNumeric || s.System == CounterAlphabetic) && len(s.Symbols) < 2:
+		return nil, fmt.Errorf("@counter-style %s needs at least two symbols", name)
+	case s.System != CounterAdditive && len(s.Symbols) == 0:
+		return nil, fmt.Errorf("@counter-style %s needs symbols", name)
+	}
+	return &s, nil
+}
+
+func (s *CounterStyle) setDescriptor(descriptor string, values []ComponentValue, extends bool) error {
+	var err error
+	switch descriptor {
+	case "system":
+		if !extends {
+			err = s.setSystem(values)
+		}
+	case "symbols":
+		if extends {
+			return fmt.Errorf("extended styles can't set symbols")
+		}
+		s.Symbols, err = counterSymbols(values)
+	case "additive-symbols":
+		if extends {
+			return fmt.Errorf("extended styles can't set symbols")
+		}
+		s.AdditiveSymbols, err = additiveSymbols(values)
+	case "negative":
+		var symbols []string
+		if symbols, err = counterSymbols(values); err == nil && len(symbols) > 2 {
+			err = fmt.Errorf("expected one or two symbols")
+		}
+		if err == nil {
+			s.NegativePrefix, s.NegativeSuffix = symbols[0], ""
+			if len(symbols) == 2 {
+				s.NegativeSuffix = symbols[1]
+			}
+		}
+	case "prefix", "suffix":
+		var symbol string
+		if symbol, err = counterSymbol(values); err == nil {
+			if descriptor == "prefix" {
+				s.Prefix = symbol
+			} else {
+				s.Suffix = symbol
+			}
+		}
+	case "range":
+		s.Ranges, err = counterRanges(values)
+	case "pad":
+		if len(values) != 2 {
+			return fmt.Errorf("expected an integer and a symbol")
+		}
+		if values[0].Type != NumberValue {
+			values = []ComponentValue{values[1], values[0]}
+		}
+		if !values[0].Integer || values[0].Number < 0 {
+			return fmt.Errorf("expected a non-negative integer")
+		}
+		s.Pad = int(values[0].Number)
+		s.PadSymbol, err = counterSymbol(values[1:])
+	case "fallback":
+		if len(values) != 1 || values[0].Type != IdentValue {
+			return fmt.Errorf("expected a counter style name")
+		}
+		s.Fallback = values[0].Text
+	}
+	return err
+}
+
+func (s *CounterStyle) setSystem(values []ComponentValue) error {
+	i := enumIndex(counterSystems, values[0])
+	switch {
+	case i < 0 || values[0].Type != IdentValue:
+		return fmt.Errorf("unknown system")
+	case CounterSystem(i) == CounterFixed && len(values) == 2:
+		if values[1].Type != NumberValue || !values[1].Integer {
+			return fmt.Errorf("expected an integer")
+		}
+		s.First = int(values[1].Number)
+	case CounterSystem(i) == CounterFixed && len(values) == 1:
+		s.First = 1
+	case len(values) != 1:
+		return fmt.Errorf("unexpected %s", values[1])
+	}
+	s.System = CounterSystem(i)
+	return nil
+}
+
+func counterSymbol(values []ComponentValue) (string, error) {
+	symbols, err := counterSymbols(values)
+	if err == nil && len(symbols) != 1 {
+		err = fmt.Errorf("expected one symbol")
+	}
+	if err != nil {
+		return "", err
+	}
+	return symbols[0], nil
+}
+
+func counterSymbols(values []ComponentValue) ([]string, error) {
+	symbols := make([]string, len(values))
+	for i, v := range values {
+		if v.Type != StringValue && v.Type != IdentValue {
+			return nil, fmt.Errorf("symbols must be strings or identifiers, got %s", v)
+		}
+		symbols[i] = v.Text
+	}
+	return symbols, nil
+}
+
+func additiveSymbols(values []ComponentValue) ([]AdditiveSymbol, error) {
+	var symbols []AdditiveSymbol
+	for _, group := range splitCommas(values) {
+		if len(group) != 2 {
+			return nil, fmt.Errorf("expected a weight and a symbol")
+		}
+		if group[0].Type != NumberValue {
+			group = []ComponentValue{group[1], group[0]}
+		}
+		if !group[0].Integer || group[0].Number < 0 {
+			return nil, fmt.Errorf("expected a non-negative integer weight")
+		}
+		symbol, err := counterSymbol(group[1:])
+		if err != nil {
+			return nil, err
+		}
+		weight := int(group[0].Number)
+		if len(symbols) > 0 && weight >= symbols[len(symbols)-1].Weight {
+			return nil, fmt.Errorf("weights must be in descending order")
+		}
+		symbols = append(symbols, AdditiveSymbol{weight, symbol})
+	}
+	return symbols, nil
+}
+
+func counterRanges(values []ComponentValue) ([][2]int, error) {
+	if len(values) == 1 && values[0].Is("auto") {
+		return nil, nil
+	}
+	var ranges [][2]int
+	for _, group := range splitCommas(values) {
+		if len(group) != 2 {
+			return nil, fmt.Errorf("expected two bounds")
+		}
+		var r [2]int
+		for i, v := range group {
+			switch {
+			case v.Is("infinite") && i == 0:
+				r[i] = math.MinInt
+			case v.Is("infinite"):
+				r[i] = math.MaxInt
+			case v.Type == NumberValue && v.Integer:
+				r[i] = int(v.Number)
+			default:
+				return nil, fmt.Errorf("expected an integer or infinite, got %s", v)
+			}
+		}
+		if r[0] > r[1] {
+			return nil, fmt.Errorf("lower bound is greater than upper bound")
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// predefinedCounterStyleRules define the counter styles every stylesheet can
+// use, from CSS Counter Styles Level 3.
+const predefinedCounterStyleRules = `
+@counter-style decimal { system: numeric; symbols: "0" "1" "2" "3" "4" "5" "6" "7" "8" "9"; }
+@counter-style decimal-leading-zero { system: extends decimal; pad: 2 "0"; }
+@counter-style arabic-indic {
+	system: numeric;
+	symbols: "\660" "\661" "\662" "\663" "\664" "\665" "\666" "\667" "\668" "\669";
+}
+@counter-style upper-armenian {
+	system: additive;
+	range: 1 9999;
+	additive-symbols: 9000 "\554", 8000 "\553", 7000 "\552", 6000 "\551", 5000 "\550", 4000 "\54F", 3000 "\54E", 2000 "\54D", 1000 "\54C", 900 "\54B", 800 "\54A", 700 "\549", 600 "\548", 500 "\547", 400 "\546", 300 "\545", 200 "\544", 100 "\543", 90 "\542", 80 "\541", 70 "\540", 60 "\53F", 50 "\53E", 40 "\53D", 30 "\53C", 20 "\53B", 10 "\53A", 9 "\539", 8 "\538", 7 "\537", 6 "\536", 5 "\535", 4 "\534", 3 "\533", 2 "\532", 1 "\531";
+}
+@counter-style lower-armenian {
+	system: additive;
+	range: 1 9999;
+	additive-symbols: 9000 "\584", 8000 "\583", 7000 "\582", 6000 "\581", 5000 "\580", 4000 "\57F", 3000 "\57E", 2000 "\57D", 1000 "\57C", 900 "\57B", 800 "\57A", 700 "\579", 600 "\578", 500 "\577", 400 "\576", 300 "\575", 200 "\574", 100 "\573", 90 "\572", 80 "\571", 70 "\570", 60 "\56F", 50 "\56E", 40 "\56D", 30 "\56C", 20 "\56B", 10 "\56A", 9 "\569", 8 "\568", 7 "\567", 6 "\566", 5 "\565", 4 "\564", 3 "\563", 2 "\562", 1 "\561";
+}
+@counter-style armenian { system: extends upper-armenian; }
+@counter-style bengali {
+	system: numeric;
+	symbols: "\9E6" "\9E7" "\9E8" "\9E9" "\9EA" "\9EB" "\9EC" "\9ED" "\9EE" "\9EF";
+}
+@counter-style cambodian {
+	system: numeric;
+	symbols: "\17E0" "\17E1" "\17E2" "\17E3" "\17E4" "\17E5" "\17E6" "\17E7" "\17E8" "\17E9";
+}
+@counter-style khmer { system: extends cambodian; }
+@counter-style cjk-decimal {
+	system: numeric;
+	range: 0 infinite;
+	symbols: "\3007" "\4E00" "\4E8C" "\4E09" "\56DB" "\4E94" "\516D" "\4E03" "\516B" "\4E5D";
+	suffix: "\3001";
+}
+@counter-style devanagari {
+	system: numeric;
+	symbols: "\966" "\967" "\968" "\969" "\96A" "\96B" "\96C" "\96D" "\96E" "\96F";
+}
+@counter-style georgian {
+	system: additive;
+	range: 1 19999;
+	additive-symbols: 10000 "\10F5", 9000 "\10F0", 8000 "\10EF", 7000 "\10F4", 6000 "\10EE", 5000 "\10ED", 4000 "\10EC", 3000 "\10EB", 2000 "\10EA", 1000 "\10E9", 900 "\10E8", 800 "\10E7", 700 "\10E6", 600 "\10E5", 500 "\10E4", 400 "\10F3", 300 "\10E2", 200 "\10E1", 100 "\10E0", 90 "\10DF", 80 "\10DE", 70 "\10DD", 60 "\10F2", 50 "\10DC", 40 "\10DB", 30 "\10DA", 20 "\10D9", 10 "\10D8", 9 "\10D7", 8 "\10F1", 7 "\10D6", 6 "\10D5", 5 "\10D4", 4 "\10D3", 3 "\10D2", 2 "\10D1", 1 "\10D0";
+}
+@counter-style gujarati {
+	system: numeric;
+	symbols: "\AE6" "\AE7" "\AE8" "\AE9" "\AEA" "\AEB" "\AEC" "\AED" "\AEE" "\AEF";
+}
+@counter-style gurmukhi {
+	system: numeric;
+	symbols: "\A66" "\A67" "\A68" "\A69" "\A6A" "\A6B" "\A6C" "\A6D" "\A6E" "\A6F";
+}
+@counter-style hebrew {
+	system: additive;
+	range: 1 10999;
+	additive-symbols: 10000 "\5D9\5D5\5F3", 9000 "\5D8\5F3", 8000 "\5D7\5F3", 7000 "\5D6\5F3", 6000 "\5D5\5F3", 5000 "\5D4\5F3", 4000 "\5D3\5F3", 3000 "\5D2\5F3", 2000 "\5D1\5F3", 1000 "\5D0\5F3", 400 "\5EA", 300 "\5E9", 200 "\5E8", 100 "\5E7", 90 "\5E6", 80 "\5E4", 70 "\5E2", 60 "\5E1", 50 "\5E0", 40 "\5DE", 30 "\5DC", 20 "\5DB", 19 "\5D9\5D8", 18 "\5D9\5D7", 17 "\5D9\5D6", 16 "\5D8\5D6", 15 "\5D8\5D5", 10 "\5D9", 9 "\5D8", 8 "\5D7", 7 "\5D6", 6 "\5D5", 5 "\5D4", 4 "\5D3", 3 "\5D2", 2 "\5D1", 1 "\5D0";
+}
+@counter-style kannada {
+	system: numeric;
+	symbols: "\CE6" "\CE7" "\CE8" "\CE9" "\CEA" "\CEB" "\CEC" "\CED" "\CEE" "\CEF";
+}
+@counter-style lao { system: numeric; symbols: "\ED0" "\ED1" "\ED2" "\ED3" "\ED4" "\ED5" "\ED6" "\ED7" "\ED8" "\ED9"; }
+@counter-style malayalam {
+	system: numeric;
+	symbols: "\D66" "\D67" "\D68" "\D69" "\D6A" "\D6B" "\D6C" "\D6D" "\D6E" "\D6F";
+}
+@counter-style mongolian {
+	system: numeric;
+	symbols: "\1810" "\1811" "\1812" "\1813" "\1814" "\1815" "\1816" "\1817" "\1818" "\1819";
+}
+@counter-style myanmar {
+	system: numeric;
+	symbols: "\1040" "\1041" "\1042" "\1043" "\1044" "\1045" "\1046" "\1047" "\1048" "\1049";
+}
+@counter-style oriya {
+	system: numeric;
+	symbols: "\B66" "\B67" "\B68" "\B69" "\B6A" "\B6B" "\B6C" "\B6D" "\B6E" "\B6F";
+}
+@counter-style persian {
+	system: numeric;
+	symbols: "\6F0" "\6F1" "\6F2" "\6F3" "\6F4" "\6F5" "\6F6" "\6F7" "\6F8" "\6F9";
+}
+@counter-style lower-roman {
+	system: additive;
+	range: 1 3999;
+	additive-symbols: 1000 m, 900 cm, 500 d, 400 cd, 100 c, 90 xc, 50 l, 40 xl, 10 x, 9 ix, 5 v, 4 iv, 1 i;
+}
+@counter-style upper-roman {
+	system: additive;
+	range: 1 3999;
+	additive-symbols: 1000 M, 900 CM, 500 D, 400 CD, 100 C, 90 XC, 50 L, 40 XL, 10 X, 9 IX, 5 V, 4 IV, 1 I;
+}
+@counter-style tamil {
+	system: numeric;
+	symbols: "\BE6" "\BE7" "\BE8" "\BE9" "\BEA" "\BEB" "\BEC" "\BED" "\BEE" "\BEF";
+}
+@counter-style telugu {
+	system: numeric;
+	symbols: "\C66" "\C67" "\C68" "\C69" "\C6A" "\C6B" "\C6C" "\C6D" "\C6E" "\C6F";
+}
+@counter-style thai { system: numeric; symbols: "\E50" "\E51" "\E52" "\E53" "\E54" "\E55" "\E56" "\E57" "\E58" "\E59"; }
+@counter-style tibetan {
+	system: numeric;
+	symbols: "\F20" "\F21" "\F22" "\F23" "\F24" "\F25" "\F26" "\F27" "\F28" "\F29";
+}
+@counter-style lower-alpha { system: alphabetic; symbols: a b c d e f g h i j k l m n o p q r s t u v w x y z; }
+@counter-style lower-latin { system: extends lower-alpha; }
+@counter-style upper-alpha { system: alphabetic; symbols: A B C D E F G H I J K L M N O P Q R S T U V W X Y Z; }
+@counter-style upper-latin { system: extends upper-alpha; }
+@counter-style lower-greek {
+	system: alphabetic;
+	symbols: "\3B1" "\3B2" "\3B3" "\3B4" "\3B5" "\3B6" "\3B7" "\3B8" "\3B9" "\3BA" "\3BB" "\3BC" "\3BD" "\3BE" "\3BF" "\3C0" "\3C1" "\3C3" "\3C4" "\3C5" "\3C6" "\3C7" "\3C8" "\3C9";
+}
+@counter-style hiragana {
+	system: alphabetic;
+	symbols: "\3042" "\3044" "\3046" "\3048" "\304A" "\304B" "\304D" "\304F" "\3051" "\3053" "\3055" "\3057" "\3059" "\305B" "\305D" "\305F" "\3061" "\3064" "\3066" "\3068" "\306A" "\306B" "\306C" "\306D" "\306E" "\306F" "\3072" "\3075" "\3078" "\307B" "\307E" "\307F" "\3080" "\3081" "\3082" "\3084" "\3086" "\3088" "\3089" "\308A" "\308B" "\308C" "\308D" "\308F" "\3090" "\3091" "\3092" "\3093";
+	suffix: "\3001";
+}
+@counter-style hiragana-iroha {
+	system: alphabetic;
+	symbols: "\3044" "\308D" "\306F" "\306B" "\307B" "\3078" "\3068" "\3061" "\308A" "\306C" "\308B" "\3092" "\308F" "\304B" "\3088" "\305F" "\308C" "\305D" "\3064" "\306D" "\306A" "\3089" "\3080" "\3046" "\3090" "\306E" "\304A" "\304F" "\3084" "\307E" "\3051" "\3075" "\3053" "\3048" "\3066" "\3042" "\3055" "\304D" "\3086" "\3081" "\307F" "\3057" "\3091" "\3072" "\3082" "\305B" "\3059";
+	suffix: "\3001";
+}
+@counter-style katakana {
+	system: alphabetic;
+	symbols: "\30A2" "\30A4" "\30A6" "\30A8" "\30AA" "\30AB" "\30AD" "\30AF" "\30B1" "\30B3" "\30B5" "\30B7" "\30B9" "\30BB" "\30BD" "\30BF" "\30C1" "\30C4" "\30C6" "\30C8" "\30CA" "\30CB" "\30CC" "\30CD" "\30CE" "\30CF" "\30D2" "\30D5" "\30D8" "\30DB" "\30DE" "\30DF" "\30E0" "\30E1" "\30E2" "\30E4" "\30E6" "\30E8" "\30E9" "\30EA" "\30EB" "\30EC" "\30ED" "\30EF" "\30F0" "\30F1" "\30F2" "\30F3";
+	suffix: "\3001";
+}
+@counter-style katakana-iroha {
+	system: alphabetic;
+	symbols: "\30A4" "\30ED" "\30CF" "\30CB" "\30DB" "\30D8" "\30C8" "\30C1" "\30EA" "\30CC" "\30EB" "\30F2" "\30EF" "\30AB" "\30E8" "\30BF" "\30EC" "\30BD" "\30C4" "\30CD" "\30CA" "\30E9" "\30E0" "\30A6" "\30F0" "\30CE" "\30AA" "\30AF" "\30E4" "\30DE" "\30B1" "\30D5" "\30B3" "\30A8" "\30C6" "\30A2" "\30B5" "\30AD" "\30E6" "\30E1" "\30DF" "\30B7" "\30F1" "\30D2" "\30E2" "\30BB" "\30B9";
+	suffix: "\3001";
+}
+@counter-style disc { system: cyclic; symbols: "\2022"; suffix: " "; }
+@counter-style circle { system: cyclic; symbols: "\25E6"; suffix: " "; }
+@counter-style square { system: cyclic; symbols: "\25AA"; suffix: " "; }
+@counter-style disclosure-open { system: cyclic; symbols: "\25BE"; suffix: " "; }
+@counter-style disclosure-closed { system: cyclic; symbols: "\25B8"; suffix: " "; }
+@counter-style cjk-earthly-branch {
+	system: fixed;
+	symbols: "\5B50" "\4E11" "\5BC5" "\536F" "\8FB0" "\5DF3" "\5348" "\672A" "\7533" "\9149" "\620C" "\4EA5";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style cjk-heavenly-stem {
+	system: fixed;
+	symbols: "\7532" "\4E59" "\4E19" "\4E01" "\620A" "\5DF1" "\5E9A" "\8F9B" "\58EC" "\7678";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style japanese-informal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\5343", 8000 "\516B\5343", 7000 "\4E03\5343", 6000 "\516D\5343", 5000 "\4E94\5343", 4000 "\56DB\5343", 3000 "\4E09\5343", 2000 "\4E8C\5343", 1000 "\5343", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4E94\767E", 400 "\56DB\767E", 300 "\4E09\767E", 200 "\4E8C\767E", 100 "\767E", 90 "\4E5D\5341", 80 "\516B\5341", 70 "\4E03\5341", 60 "\516D\5341", 50 "\4E94\5341", 40 "\56DB\5341", 30 "\4E09\5341", 20 "\4E8C\5341", 10 "\5341", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4E94", 4 "\56DB", 3 "\4E09", 2 "\4E8C", 1 "\4E00", 0 "\3007";
+	negative: "\30DE\30A4\30CA\30B9";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style japanese-formal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\9621", 8000 "\516B\9621", 7000 "\4E03\9621", 6000 "\516D\9621", 5000 "\4F0D\9621", 4000 "\56DB\9621", 3000 "\53C2\9621", 2000 "\5F10\9621", 1000 "\58F1\9621", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4F0D\767E", 400 "\56DB\767E", 300 "\53C2\767E", 200 "\5F10\767E", 100 "\58F1\767E", 90 "\4E5D\62FE", 80 "\516B\62FE", 70 "\4E03\62FE", 60 "\516D\62FE", 50 "\4F0D\62FE", 40 "\56DB\62FE", 30 "\53C2\62FE", 20 "\5F10\62FE", 10 "\58F1\62FE", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4F0D", 4 "\56DB", 3 "\53C2", 2 "\5F10", 1 "\58F1", 0 "\96F6";
+	negative: "\30DE\30A4\30CA\30B9";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style korean-hangul-formal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\AD6C\CC9C", 8000 "\D314\CC9C", 7000 "\CE60\CC9C", 6000 "\C721\CC9C", 5000 "\C624\CC9C", 4000 "\C0AC\CC9C", 3000 "\C0BC\CC9C", 2000 "\C774\CC9C", 1000 "\C77C\CC9C", 900 "\AD6C\BC31", 800 "\D314\BC31", 700 "\CE60\BC31", 600 "\C721\BC31", 500 "\C624\BC31", 400 "\C0AC\BC31", 300 "\C0BC\BC31", 200 "\C774\BC31", 100 "\C77C\BC31", 90 "\AD6C\C2ED", 80 "\D314\C2ED", 70 "\CE60\C2ED", 60 "\C721\C2ED", 50 "\C624\C2ED", 40 "\C0AC\C2ED", 30 "\C0BC\C2ED", 20 "\C774\C2ED", 10 "\C77C\C2ED", 9 "\AD6C", 8 "\D314", 7 "\CE60", 6 "\C721", 5 "\C624", 4 "\C0AC", 3 "\C0BC", 2 "\C774", 1 "\C77C", 0 "\C601";
+	negative: "\B9C8\C774\B108\C2A4  ";
+	suffix: ", ";
+}
+@counter-style korean-hanja-informal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\5343", 8000 "\516B\5343", 7000 "\4E03\5343", 6000 "\516D\5343", 5000 "\4E94\5343", 4000 "\56DB\5343", 3000 "\4E09\5343", 2000 "\4E8C\5343", 1000 "\5343", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4E94\767E", 400 "\56DB\767E", 300 "\4E09\767E", 200 "\4E8C\767E", 100 "\767E", 90 "\4E5D\5341", 80 "\516B\5341", 70 "\4E03\5341", 60 "\516D\5341", 50 "\4E94\5341", 40 "\56DB\5341", 30 "\4E09\5341", 20 "\4E8C\5341", 10 "\5341", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4E94", 4 "\56DB", 3 "\4E09", 2 "\4E8C", 1 "\4E00", 0 "\96F6";
+	negative: "\B9C8\C774\B108\C2A4  ";
+	suffix: ", ";
+}
+@counter-style korean-hanja-formal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\4EDF", 8000 "\516B\4EDF", 7000 "\4E03\4EDF", 6000 "\516D\4EDF", 5000 "\4E94\4EDF", 4000 "\56DB\4EDF", 3000 "\53C3\4EDF", 2000 "\8CB3\4EDF", 1000 "\58F9\4EDF", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4E94\767E", 400 "\56DB\767E", 300 "\53C3\767E", 200 "\8CB3\767E", 100 "\58F9\767E", 90 "\4E5D\62FE", 80 "\516B\62FE", 70 "\4E03\62FE", 60 "\516D\62FE", 50 "\4E94\62FE", 40 "\56DB\62FE", 30 "\53C3\62FE", 20 "\8CB3\62FE", 10 "\58F9\62FE", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4E94", 4 "\56DB", 3 "\53C3", 2 "\8CB3", 1 "\58F9", 0 "\96F6";
+	negative: "\B9C8\C774\B108\C2A4  ";
+	suffix: ", ";
+}
+@counter-style simp-chinese-informal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\5343", 8000 "\516B\5343", 7000 "\4E03\5343", 6000 "\516D\5343", 5000 "\4E94\5343", 4000 "\56DB\5343", 3000 "\4E09\5343", 2000 "\4E8C\5343", 1000 "\5343", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4E94\767E", 400 "\56DB\767E", 300 "\4E09\767E", 200 "\4E8C\767E", 100 "\767E", 90 "\4E5D\5341", 80 "\516B\5341", 70 "\4E03\5341", 60 "\516D\5341", 50 "\4E94\5341", 40 "\56DB\5341", 30 "\4E09\5341", 20 "\4E8C\5341", 10 "\5341", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4E94", 4 "\56DB", 3 "\4E09", 2 "\4E8C", 1 "\4E00", 0 "\96F6";
+	negative: "\8D1F";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style simp-chinese-formal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\7396\4EDF", 8000 "\634C\4EDF", 7000 "\67D2\4EDF", 6000 "\9646\4EDF", 5000 "\4F0D\4EDF", 4000 "\8086\4EDF", 3000 "\53C1\4EDF", 2000 "\8D30\4EDF", 1000 "\58F9\4EDF", 900 "\7396\4F70", 800 "\634C\4F70", 700 "\67D2\4F70", 600 "\9646\4F70", 500 "\4F0D\4F70", 400 "\8086\4F70", 300 "\53C1\4F70", 200 "\8D30\4F70", 100 "\58F9\4F70", 90 "\7396\62FE", 80 "\634C\62FE", 70 "\67D2\62FE", 60 "\9646\62FE", 50 "\4F0D\62FE", 40 "\8086\62FE", 30 "\53C1\62FE", 20 "\8D30\62FE", 10 "\58F9\62FE", 9 "\7396", 8 "\634C", 7 "\67D2", 6 "\9646", 5 "\4F0D", 4 "\8086", 3 "\53C1", 2 "\8D30", 1 "\58F9", 0 "\96F6";
+	negative: "\8D1F";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style trad-chinese-informal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\4E5D\5343", 8000 "\516B\5343", 7000 "\4E03\5343", 6000 "\516D\5343", 5000 "\4E94\5343", 4000 "\56DB\5343", 3000 "\4E09\5343", 2000 "\4E8C\5343", 1000 "\5343", 900 "\4E5D\767E", 800 "\516B\767E", 700 "\4E03\767E", 600 "\516D\767E", 500 "\4E94\767E", 400 "\56DB\767E", 300 "\4E09\767E", 200 "\4E8C\767E", 100 "\767E", 90 "\4E5D\5341", 80 "\516B\5341", 70 "\4E03\5341", 60 "\516D\5341", 50 "\4E94\5341", 40 "\56DB\5341", 30 "\4E09\5341", 20 "\4E8C\5341", 10 "\5341", 9 "\4E5D", 8 "\516B", 7 "\4E03", 6 "\516D", 5 "\4E94", 4 "\56DB", 3 "\4E09", 2 "\4E8C", 1 "\4E00", 0 "\96F6";
+	negative: "\8CA0";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style trad-chinese-formal {
+	system: additive;
+	range: -9999 9999;
+	additive-symbols: 9000 "\7396\4EDF", 8000 "\634C\4EDF", 7000 "\67D2\4EDF", 6000 "\9678\4EDF", 5000 "\4F0D\4EDF", 4000 "\8086\4EDF", 3000 "\53C3\4EDF", 2000 "\8CB3\4EDF", 1000 "\58F9\4EDF", 900 "\7396\4F70", 800 "\634C\4F70", 700 "\67D2\4F70", 600 "\9678\4F70", 500 "\4F0D\4F70", 400 "\8086\4F70", 300 "\53C3\4F70", 200 "\8CB3\4F70", 100 "\58F9\4F70", 90 "\7396\62FE", 80 "\634C\62FE", 70 "\67D2\62FE", 60 "\9678\62FE", 50 "\4F0D\62FE", 40 "\8086\62FE", 30 "\53C3\62FE", 20 "\8CB3\62FE", 10 "\58F9\62FE", 9 "\7396", 8 "\634C", 7 "\67D2", 6 "\9678", 5 "\4F0D", 4 "\8086", 3 "\53C3", 2 "\8CB3", 1 "\58F9", 0 "\96F6";
+	negative: "\8CA0";
+	suffix: "\3001";
+	fallback: cjk-decimal;
+}
+@counter-style cjk-ideographic { system: extends trad-chinese-informal; }
+@counter-style ethiopic-numeric { system: extends decimal; range: 1 infinite; suffix: "/ "; }
+`
+
+var predefinedCounterStyles = mustCounterStyles(predefinedCounterStyleRules)
+
+func init() {
+	// the Chinese styles only approximate their algorithm with the
+	// additive system
+	informal := "\u96F6\u4E00\u4E8C\u4E09\u56DB\u4E94\u516D\u4E03\u516B\u4E5D"
+	for name, a := range map[string]func(int) (string, bool){
+		"simp-chinese-informal": chineseCounter(informal, "\u5341\u767E\u5343", true),
+		"simp-chinese-formal":   chineseCounter("\u96F6\u58F9\u8D30\u53C1\u8086\u4F0D\u9646\u67D2\u634C\u7396", "\u62FE\u4F70\u4EDF", false),
+		"trad-chinese-informal": chineseCounter(informal, "\u5341\u767E\u5343", true),
+		"trad-chinese-formal":   chineseCounter("\u96F6\u58F9\u8CB3\u53C3\u8086\u4F0D\u9678\u67D2\u634C\u7396", "\u62FE\u4F70\u4EDF", false),
+		"cjk-ideographic":       chineseCounter(informal, "\u5341\u767E\u5343", true),
+		"ethiopic-numeric":      ethiopicNumeric,
+	} {
+		predefinedCounterStyles[name].algorithm = a
+	}
+}
+
+// chineseCounter returns the algorithm of a Chinese counter style with the
+// digits 0 to 9 and the markers of tens, hundreds and thousands. Runs of
+// zero digits are written once, except at the end, and informal styles
+// write 10 to 19 without the leading one.
+func chineseCounter(digits, markers string, informal bool) func(int) (string, bool) {
+	d, m := []rune(digits), []rune(markers)
+	return func(n int) (string, bool) {
+		if n > 9999 {
+			return "", false
+		}
+		if n == 0 {
+			return string(d[0]), true
+		}
+		var r []rune
+		zero := false
+		for i, unit := 3, 1000; i >= 0; i, unit = i-1, unit/10 {
+			digit := n / unit % 10
+			switch {
+			case digit == 0:
+				zero = len(r) > 0
+				continue
+			case zero:
+				r = append(r, d[0])
+				zero = false
+			}
+			if !(informal && i == 1 && digit == 1 && n < 20) {
+				r = append(r, d[digit])
+			}
+			if i > 0 {
+				r = append(r, m[i-1])
+			}
+		}
+		return string(r), true
+	}
+}
+
+// ethiopicNumeric is the algorithm of the ethiopic-numeric style, which
+// writes groups of two digits followed by alternating hundred and ten
+// thousand markers.
+func ethiopicNumeric(n int) (string, bool) {
+	if n < 1 {
+		return "", false
+	}
+	if n == 1 {
+		return "\u1369", true
+	}
+	var groups []int
+	for ; n > 0; n /= 100 {
+		groups = append(groups, n%100)
+	}
+	r := ""
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		if g == 1 && (i%2 == 1 || i == len(groups)-1) {
+			g = 0
+		}
+		if g/10 > 0 {
+			r += string(rune(0x1371 + g/10))
+		}
+		if g%10 > 0 {
+			r += string(rune(0x1368 + g%10))
+		}
+		switch {
+		case i%2 == 1 && groups[i] != 0:
+			r += "\u137B"
+		case i%2 == 0 && i > 0:
+			r += "\u137C"
+		}
+	}
+	return r, true
+}
+
+func mustCounterStyles(css string) CounterStyles {
+	h := &counterStyleHandler{}
+	if err := parseBytes(context.Background(), []byte(css), h); err != nil {
+		panic(err)
+	}
+	styles, err := defineCounterStyles(h.rules, nil)
+	if err != nil {
+		panic(err)
+	}
+	return styles
+}
@@ -0,0 +1,148 @@
+package css
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredefinedMarkers(t *testing.T) {
+	cases := []struct {
+		style    string
+		n        int
+		expected string
+	}{
+		{"decimal", 7, "7. "},
+		{"decimal", -12, "-12. "},
+		{"decimal-leading-zero", 3, "03. "},
+		{"decimal-leading-zero", -3, "-3. "},
+		{"lower-roman", 1994, "mcmxciv. "},
+		{"upper-roman", 49, "XLIX. "},
+		{"upper-roman", 4000, "4000. "},
+		{"lower-alpha", 1, "a. "},
+		{"upper-alpha", 28, "AB. "},
+		{"upper-latin", 702, "ZZ. "},
+		{"lower-alpha", 0, "0. "},
+		{"lower-greek", 25, "αα. "},
+		{"disc", 3, "• "},
+		{"square", 1, "▪ "},
+		{"cjk-decimal", 2024, "二〇二四、"},
+		{"hebrew", 15, "טו. "},
+		{"hebrew", 1234, "א׳רלד. "},
+		{"arabic-indic", 42, "٤٢. "},
+		{"khmer", 10, "១០. "},
+		{"thai", -5, "-๕. "},
+		{"armenian", 1984, "ՌՋՁԴ. "},
+		{"lower-armenian", 10000, "10000. "},
+		{"georgian", 19, "ით. "},
+		{"hiragana", 49, "ああ、"},
+		{"katakana-iroha", 1, "イ、"},
+		{"cjk-earthly-branch", 12, "亥、"},
+		{"cjk-heavenly-stem", 11, "一一、"},
+		{"japanese-informal", 1111, "千百十一、"},
+		{"japanese-informal", -5, "マイナス五、"},
+		{"japanese-formal", 1111, "壱阡壱百壱拾壱、"},
+		{"korean-hangul-formal", 21, "이십일, "},
+		{"korean-hanja-informal", 10, "十, "},
+		{"cjk-ideographic", 15, "十五、"},
+		{"cjk-ideographic", 101, "一百零一、"},
+		{"cjk-ideographic", 1100, "一千一百、"},
+		{"cjk-ideographic", 10000, "一〇〇〇〇、"},
+		{"simp-chinese-informal", -3, "负三、"},
+		{"simp-chinese-formal", 12, "壹拾贰、"},
+		{"trad-chinese-formal", 2020, "貳仟零貳拾、"},
+		{"ethiopic-numeric", 100, "፻/ "},
+		{"ethiopic-numeric", 780100000092, "፸፰፻፩፼፼፺፪/ "},
+		{"ethiopic-numeric", 0, "0. "},
+		{"Decimal", 1, "1. "},
+		{"unknown", 4, "4. "},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.style, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CounterStyles(nil).Marker(ListStyleType{Name: tt.style}, tt.n))
+		})
+	}
+
+	assert.Equal(t, "", CounterStyles(nil).Marker(ListStyleType{Name: "none"}, 1))
+	assert.Equal(t, "→ ", CounterStyles(nil).Marker(ListStyleType{Text: "→ "}, 1))
+}
+
+func TestCounterStyles(t *testing.T) {
+	styles, err := UnmarshalCounterStyles([]byte(`
+		@counter-style thumbs { system: cyclic; symbols: "👍" "👎"; suffix: " "; }
+		@counter-style circled { system: fixed 0; symbols: ⓪ ① ②; suffix: ""; fallback: paren; }
+		@counter-style paren { system: extends decimal; prefix: "("; suffix: ") "; }
+		@counter-style binary { system: numeric; symbols: "0" "1"; negative: "(" ")"; pad: 4 "0"; }
+		@counter-style stars { system: symbolic; symbols: "*" "†"; range: 1 5, 10 infinite; }
+		@counter-style dice { system: additive; additive-symbols: 6 ⚅, 5 ⚄, 4 ⚃, 3 ⚂, 2 ⚁, 1 ⚀, 0 "-"; suffix: ""; }
+		@counter-style loop-a { system: extends loop-b; }
+		@counter-style loop-b { system: extends loop-a; }
+		@counter-style chinese { system: extends cjk-ideographic; suffix: ") "; }
+		@counter-style padded { system: extends decimal; pad: 1000000000 "0"; }
+		.list { list-style-type: thumbs; }
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		style    string
+		n        int
+		expected string
+	}{
+		{"thumbs", 3, "👍 "},
+		{"thumbs", 0, "👎 "},
+		{"circled", 2, "②"},
+		{"circled", 3, "(3) "},
+		{"binary", 5, "0101. "},
+		{"binary", -5, "(101). "},
+		{"stars", 3, "**. "},
+		{"stars", 4, "††. "},
+		{"stars", 7, "7. "},
+		{"dice", 0, "-"},
+		{"dice", 9, "⚅⚂"},
+		{"loop-a", 2, "2. "},
+		{"lower-roman", 3, "iii. "},
+		{"chinese", 11, "十一) "},
+		{"padded", 7, strings.Repeat("0", 60) + "7. "},
+		// representations longer than 60 symbols use the fallback style
+		{"stars", 120, "††††††††††††††††††††††††††††††††††††††††††††††††††††††††††††. "},
+		{"stars", 121, "121. "},
+		{"dice", 360, strings.Repeat("⚅", 60)},
+		{"dice", 361, "361. "},
+		{"dice", math.MaxInt, "9223372036854775807. "},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.style, func(t *testing.T) {
+			assert.Equal(t, tt.expected, styles.Marker(ListStyleType{Name: tt.style}, tt.n))
+		})
+	}
+
+	assert.Equal(t, [][2]int{{1, 5}, {10, math.MaxInt}}, styles["stars"].Ranges)
+	assert.Equal(t, CounterFixed, styles["circled"].System)
+	assert.Equal(t, CounterNumeric, styles["paren"].System)
+	assert.Len(t, styles, 10)
+}
+
+func TestCounterStylesError(t *testing.T) {
+	for _, css := range []string{
+		`@counter-style decimal { system: cyclic; symbols: x; }`,
+		`@counter-style none { system: cyclic; symbols: x; }`,
+		`@counter-style a { system: numeric; symbols: x; }`,
+		`@counter-style a { system: additive; symbols: x y; }`,
+		`@counter-style a { system: cyclic; }`,
+		`@counter-style a { system: sequence; symbols: x; }`,
+		`@counter-style a { system: extends decimal; symbols: x; }`,
+		`@counter-style a { system: additive; additive-symbols: 1 i, 5 v; }`,
+		`@counter-style a { system: cyclic; symbols: x; range: 5 1; }`,
+		`@counter-style a { system: cyclic; symbols: url(x.png); }`,
+		`@counter-style a { system: cyclic; symbols: x; pad: -1 "0"; }`,
+	} {
+		_, err := UnmarshalCounterStyles([]byte(css))
+		assert.Error(t, err, css)
+	}
+}
@@ -0,0 +1,86 @@
+package css
+
+import "strings"
+
+// TextDecorationLines is the set of lines drawn by text-decoration-line.
+type TextDecorationLines uint8
+
+const (
+	DecorationUnderline TextDecorationLines = 1 << iota
+	DecorationOverline
+	DecorationLineThrough
+	DecorationBlink
+)
+
+var decorationLines = []string{"underline", "overline", "line-through", "blink"}
+
+// Has reports whether all lines of l are set.
+func (lines TextDecorationLines) Has(l TextDecorationLines) bool {
+	return lines&l == l
+}
+
+func (lines TextDecorationLines) String() string {
+	var names []string
+	for i, name := range decorationLines {
+		if lines.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, " ")
+}
+
+// TextDecoration is the typed value of the text-decoration shorthand.
+type TextDecoration struct {
+	Lines     TextDecorationLines
+	Style     string
+	Color     Color
+	Thickness Length
+}
+
+func initialTextDecoration() TextDecoration {
+	return TextDecoration{
+		Style:     "solid",
+		Color:     Color{CurrentColor: true},
+		Thickness: Length{Keyword: "auto"},
+	}
+}
+
+func textDecoration(values []ComponentValue) interface{} {
+	d := initialTextDecoration()
+	for i, v := range values {
+		switch v.Production {
+		case "text-decoration-line":
+			d.Lines |= textDecorationLine(values[i : i+1]).(TextDecorationLines)
+		case "text-decoration-style":
+			d.Style = strings.ToLower(v.Text)
+		case "text-decoration-color":
+			d.Color = colorOf(v)
+		case "text-decoration-thickness":
+			d.Thickness = lengthOf(v)
+		}
+	}
+	return d
+}
+
+func textDecorationLine(values []ComponentValue) interface{} {
+	var lines TextDecorationLines
+	for _, v := range values {
+		if i := enumIndex(decorationLines, v); i >= 0 {
+			lines |= 1 << i
+		}
+	}
+	return lines
+}
+
+func expandTextDecoration(values []ComponentValue) map[string]string {
+	d := textDecoration(values).(TextDecoration)
+	return map[string]string{
+		"text-decoration-line":      d.Lines.String(),
+		"text-decoration-style":     d.Style,
+		"text-decoration-color":     d.Color.String(),
+		"text-decoration-thickness": d.Thickness.String(),
+	}
+}
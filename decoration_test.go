@@ -0,0 +1,73 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextDecoration(t *testing.T) {
+	red := Color{R: 1, A: 1}
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"text-decoration", "underline", TextDecoration{Lines: DecorationUnderline, Style: "solid", Color: Color{CurrentColor: true}, Thickness: Length{Keyword: "auto"}}},
+		{"text-decoration", "overline underline wavy red", TextDecoration{Lines: DecorationUnderline | DecorationOverline, Style: "wavy", Color: red, Thickness: Length{Keyword: "auto"}}},
+		{"text-decoration", "line-through 2px", TextDecoration{Lines: DecorationLineThrough, Style: "solid", Color: Color{CurrentColor: true}, Thickness: Length{Value: 2, Unit: "px"}}},
+		{"text-decoration", "none dotted", TextDecoration{Style: "dotted", Color: Color{CurrentColor: true}, Thickness: Length{Keyword: "auto"}}},
+		{"text-decoration-line", "blink underline", DecorationBlink | DecorationUnderline},
+		{"text-decoration-line", "none", TextDecorationLines(0)},
+		{"text-decoration-style", "Double", "double"},
+		{"text-decoration-color", "#f00", red},
+		{"text-decoration-thickness", "from-font", Length{Keyword: "from-font"}},
+		{"text-underline-offset", "0.1em", Length{Value: 0.1, Unit: "em"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"text-decoration":       "underline wavy overline",
+		"text-decoration-line":  "none underline",
+		"text-decoration-style": "groove",
+		"text-underline-offset": "thick",
+		"text-decoration: none": "",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	lines := DecorationOverline | DecorationLineThrough
+	assert.True(t, lines.Has(DecorationOverline))
+	assert.False(t, lines.Has(DecorationOverline|DecorationUnderline))
+	assert.Equal(t, "overline line-through", lines.String())
+}
+
+func TestExpandTextDecoration(t *testing.T) {
+	longhands, err := ExpandShorthand("text-decoration", "underline dashed blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"text-decoration-line":      "underline",
+		"text-decoration-style":     "dashed",
+		"text-decoration-color":     "#0000ff",
+		"text-decoration-thickness": "auto",
+	}, longhands)
+
+	longhands, err = ExpandShorthand("text-decoration", "none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "none", longhands["text-decoration-line"])
+	assert.Equal(t, "currentcolor", longhands["text-decoration-color"])
+}
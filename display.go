@@ -0,0 +1,260 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Display is the typed value of the display property, normalized to its
+// outer and inner display types.
+type Display struct {
+	// Outside is "block", "inline" or "run-in".
+	Outside string
+	// Inside is "flow", "flow-root", "table", "flex", "grid" or "ruby".
+	Inside   string
+	ListItem bool
+	// Internal is a layout-internal type like "table-cell". Outside and
+	// Inside are empty for internal types.
+	Internal string
+	// Box is "none" or "contents" for elements that generate no box of
+	// their own, with the other fields empty.
+	Box string
+}
+
+// legacyDisplays are the one keyword displays with an inline outer type.
+var legacyDisplays = map[string]string{
+	"inline-block": "flow-root",
+	"inline-table": "table",
+	"inline-flex":  "flex",
+	"inline-grid":  "grid",
+}
+
+// IsNone reports whether the element and its descendants generate no boxes.
+func (d Display) IsNone() bool {
+	return d.Box == "none"
+}
+
+// IsBlockLevel reports whether the element generates a block-level box.
+func (d Display) IsBlockLevel() bool {
+	return d.Outside == "block"
+}
+
+// IsInlineLevel reports whether the element generates an inline-level box.
+func (d Display) IsInlineLevel() bool {
+	return d.Outside == "inline"
+}
+
+// String returns the shortest serialization of d, like "inline-block" for
+// inline flow-root.
+func (d Display) String() string {
+	switch {
+	case d.Box != "":
+		return d.Box
+	case d.Internal != "":
+		return d.Internal
+	case d.ListItem:
+		parts := []string{}
+		if d.Outside != "block" {
+			parts = append(parts, d.Outside)
+		}
+		if d.Inside != "flow" {
+			parts = append(parts, d.Inside)
+		}
+		return strings.Join(append(parts, "list-item"), " ")
+	case d.Outside == "inline" && d.Inside == "flow":
+		return "inline"
+	case d.Outside == "inline":
+		for legacy, inside := range legacyDisplays {
+			if inside == d.Inside {
+				return legacy
+			}
+		}
+		if d.Inside == "ruby" {
+			return "ruby"
+		}
+	case d.Outside == "block" && d.Inside != "ruby":
+		if d.Inside == "flow" {
+			return "block"
+		}
+		return d.Inside
+	case d.Inside == "flow":
+		return d.Outside
+	}
+	return d.Outside + " " + d.Inside
+}
+
+func display(values []ComponentValue) interface{} {
+	first := strings.ToLower(values[0].Text)
+	switch values[0].Production {
+	case "display-box":
+		return Display{Box: first}
+	case "display-internal":
+		return Display{Internal: first}
+	case "display-legacy":
+		return Display{Outside: "inline", Inside: legacyDisplays[first]}
+	}
+
+	var d Display
+	for _, v := range values {
+		keyword := strings.ToLower(v.Text)
+		switch {
+		case keyword == "list-item":
+			d.ListItem = true
+		case isDisplayOutside(keyword):
+			d.Outside = keyword
+		default:
+			d.Inside = keyword
+		}
+	}
+	if d.Inside == "" {
+		d.Inside = "flow"
+	}
+	if d.Outside == "" {
+		d.Outside = "block"
+		if d.Inside == "ruby" {
+			d.Outside = "inline"
+		}
+	}
+	return d
+}
+
+func isDisplayOutside(keyword string) bool {
+	return keyword == "block" || keyword == "inline" || keyword == "run-in"
+}
+
+// Visibility is the typed value of the visibility property.
+type Visibility int
+
+const (
+	VisibilityVisible Visibility = iota
+	VisibilityHidden
+	VisibilityCollapse
+)
+
+var visibilities = []string{"visible", "hidden", "collapse"}
+
+func (v Visibility) String() string {
+	return enumString(visibilities, int(v))
+}
+
+// Overflow is the typed value of the overflow shorthand.
+type Overflow struct {
+	X, Y string
+}
+
+func (o Overflow) String() string {
+	if o.X == o.Y {
+		return o.X
+	}
+	return o.X + " " + o.Y
+}
+
+// Computed returns the computed overflow: if one axis scrolls, visible
+// computes to auto and clip to hidden on the other axis.
+func (o Overflow) Computed() Overflow {
+	scrolls := func(v string) bool {
+		return v == "hidden" || v == "scroll" || v == "auto"
+	}
+	if scrolls(o.X) || scrolls(o.Y) {
+		for _, v := range []*string{&o.X, &o.Y} {
+			switch *v {
+			case "visible":
+				*v = "auto"
+			case "clip":
+				*v = "hidden"
+			}
+		}
+	}
+	return o
+}
+
+// IsScrollContainer reports whether the box is a scroll container.
+func (o Overflow) IsScrollContainer() bool {
+	c := o.Computed()
+	return c.X != "visible" && c.X != "clip"
+}
+
+func visibility(values []ComponentValue) interface{} {
+	return Visibility(enumIndex(visibilities, values[0]))
+}
+
+func overflow(values []ComponentValue) interface{} {
+	o := Overflow{X: strings.ToLower(values[0].Text)}
+	o.Y = o.X
+	if len(values) == 2 {
+		o.Y = strings.ToLower(values[1].Text)
+	}
+	return o
+}
+
+func expandOverflow(values []ComponentValue) map[string]string {
+	o := overflow(values).(Overflow)
+	return map[string]string{"overflow-x": o.X, "overflow-y": o.Y}
+}
+
+// CursorImage is an image of the cursor property with its optional hotspot.
+type CursorImage struct {
+	URL        string
+	HasHotspot bool
+	X, Y       float64
+}
+
+// Cursor is the typed value of the cursor property: the images to try in
+// order, and the keyword to use if none of them can be loaded.
+type Cursor struct {
+	Images  []CursorImage
+	Keyword string
+}
+
+func (c Cursor) String() string {
+	var parts []string
+	for _, image := range c.Images {
+		s := "url(" + strconv.Quote(image.URL) + ")"
+		if image.HasHotspot {
+			s += " " + formatNumber(image.X) + " " + formatNumber(image.Y)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(append(parts, c.Keyword), ", ")
+}
+
+func cursor(values []ComponentValue) interface{} {
+	var c Cursor
+	groups := splitCommas(values)
+	for _, group := range groups[:len(groups)-1] {
+		image := CursorImage{URL: group[0].Text}
+		if len(group) == 3 {
+			image.HasHotspot, image.X, image.Y = true, group[1].Number, group[2].Number
+		}
+		c.Images = append(c.Images, image)
+	}
+	c.Keyword = strings.ToLower(groups[len(groups)-1][0].Text)
+	return c
+}
+
+// ClipRect is the typed value of the deprecated clip property. Its offsets
+// are lengths or auto.
+type ClipRect struct {
+	Auto                     bool
+	Top, Right, Bottom, Left Length
+}
+
+func (r ClipRect) String() string {
+	if r.Auto {
+		return "auto"
+	}
+	return "rect(" + r.Top.String() + ", " + r.Right.String() + ", " + r.Bottom.String() + ", " + r.Left.String() + ")"
+}
+
+func clip(values []ComponentValue) interface{} {
+	if values[0].Type != FunctionValue {
+		return ClipRect{Auto: true}
+	}
+	var offsets []Length
+	for _, v := range values[0].Args {
+		if !v.IsDelim(",") {
+			offsets = append(offsets, lengthOf(v))
+		}
+	}
+	return ClipRect{Top: offsets[0], Right: offsets[1], Bottom: offsets[2], Left: offsets[3]}
+}
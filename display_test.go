@@ -0,0 +1,103 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplay(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected Display
+		short    string
+	}{
+		{"block", Display{Outside: "block", Inside: "flow"}, "block"},
+		{"inline", Display{Outside: "inline", Inside: "flow"}, "inline"},
+		{"flow-root inline", Display{Outside: "inline", Inside: "flow-root"}, "inline-block"},
+		{"inline-block", Display{Outside: "inline", Inside: "flow-root"}, "inline-block"},
+		{"block flex", Display{Outside: "block", Inside: "flex"}, "flex"},
+		{"inline-grid", Display{Outside: "inline", Inside: "grid"}, "inline-grid"},
+		{"ruby", Display{Outside: "inline", Inside: "ruby"}, "ruby"},
+		{"run-in", Display{Outside: "run-in", Inside: "flow"}, "run-in"},
+		{"list-item", Display{Outside: "block", Inside: "flow", ListItem: true}, "list-item"},
+		{"list-item inline flow-root", Display{Outside: "inline", Inside: "flow-root", ListItem: true}, "inline flow-root list-item"},
+		{"table-cell", Display{Internal: "table-cell"}, "table-cell"},
+		{"contents", Display{Box: "contents"}, "contents"},
+		{"NONE", Display{Box: "none"}, "none"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			style, err := CSSStyle("display", map[string]string{"display": tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+			assert.Equal(t, tt.short, style.Value.(Display).String())
+		})
+	}
+
+	for _, value := range []string{"block inline", "flex grid", "list-item table", "inline-block flow", "none block"} {
+		_, err := CSSStyle("display", map[string]string{"display": value})
+		assert.Error(t, err, value)
+	}
+
+	assert.True(t, Display{Box: "none"}.IsNone())
+	assert.True(t, Display{Outside: "block", Inside: "grid"}.IsBlockLevel())
+	assert.True(t, Display{Outside: "inline", Inside: "flow-root"}.IsInlineLevel())
+}
+
+func TestVisualProperties(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"visibility", "collapse", VisibilityCollapse},
+		{"overflow", "hidden", Overflow{X: "hidden", Y: "hidden"}},
+		{"overflow", "clip visible", Overflow{X: "clip", Y: "visible"}},
+		{"overflow-y", "Scroll", "scroll"},
+		{"cursor", "pointer", Cursor{Keyword: "pointer"}},
+		{"cursor", "url(a.cur), url(b.png) 4 12, grab", Cursor{
+			Images:  []CursorImage{{URL: "a.cur"}, {URL: "b.png", HasHotspot: true, X: 4, Y: 12}},
+			Keyword: "grab",
+		}},
+		{"clip", "auto", ClipRect{Auto: true}},
+		{"clip", "rect(1px, auto, 3em, 0)", ClipRect{Top: Length{Value: 1, Unit: "px"}, Right: Length{Keyword: "auto"}, Bottom: Length{Value: 3, Unit: "em"}}},
+		{"clip", "rect(1px auto 3em 0)", ClipRect{Top: Length{Value: 1, Unit: "px"}, Right: Length{Keyword: "auto"}, Bottom: Length{Value: 3, Unit: "em"}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"visibility": "none",
+		"overflow":   "hidden scroll auto",
+		"cursor":     "url(a.cur)",
+		"clip":       "rect(1px, 2px, 3px)",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	assert.Equal(t, Overflow{X: "hidden", Y: "auto"}, Overflow{X: "clip", Y: "auto"}.Computed())
+	assert.Equal(t, Overflow{X: "clip", Y: "visible"}, Overflow{X: "clip", Y: "visible"}.Computed())
+	assert.True(t, Overflow{X: "visible", Y: "scroll"}.IsScrollContainer())
+	assert.False(t, Overflow{X: "clip", Y: "clip"}.IsScrollContainer())
+	assert.Equal(t, `url("a.cur") 1 2, auto`, Cursor{Images: []CursorImage{{URL: "a.cur", HasHotspot: true, X: 1, Y: 2}}, Keyword: "auto"}.String())
+	assert.Equal(t, "rect(0, auto, 0, 0)", ClipRect{Right: Length{Keyword: "auto"}}.String())
+
+	longhands, err := ExpandShorthand("overflow", "hidden clip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"overflow-x": "hidden", "overflow-y": "clip"}, longhands)
+}
@@ -0,0 +1,307 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EasingFunction is a typed <easing-function>. Keywords are converted to
+// the function they stand for, but keep their name for serialization.
+type EasingFunction struct {
+	// Name is the keyword like "ease-in", or the function name
+	// "cubic-bezier", "steps" or "linear". linear() has Stops, the linear
+	// keyword doesn't.
+	Name string
+	// X1, Y1, X2 and Y2 are the control points of cubic Bézier curves.
+	X1, Y1, X2, Y2 float64
+	// Steps is the number of intervals of step functions.
+	Steps int
+	// Position is the <step-position> of step functions, with start and
+	// end normalized to jump-start and jump-end.
+	Position string
+	// Stops are the control points of linear(), with the missing inputs
+	// filled in.
+	Stops []LinearStop
+}
+
+// LinearStop is a control point of linear() that maps the Input progress
+// to the Output progress.
+type LinearStop struct {
+	Input, Output float64
+}
+
+// easingKeywords are the easing functions named by keywords.
+var easingKeywords = map[string]EasingFunction{
+	"linear":      {Name: "linear"},
+	"ease":        {Name: "ease", X1: 0.25, Y1: 0.1, X2: 0.25, Y2: 1},
+	"ease-in":     {Name: "ease-in", X1: 0.42, X2: 1, Y2: 1},
+	"ease-out":    {Name: "ease-out", X2: 0.58, Y2: 1},
+	"ease-in-out": {Name: "ease-in-out", X1: 0.42, X2: 0.58, Y2: 1},
+	"step-start":  {Name: "step-start", Steps: 1, Position: "jump-start"},
+	"step-end":    {Name: "step-end", Steps: 1, Position: "jump-end"},
+}
+
+// stepPositions normalize the <step-position> keywords.
+var stepPositions = map[string]string{"start": "jump-start", "end": "jump-end"}
+
+// ParseEasingFunction parses an <easing-function>, like "ease-in",
+// "cubic-bezier(0.1, 0.7, 1, 0.1)", "steps(4, jump-end)" or
+// "linear(0, 0.25 75%, 1)".
+func ParseEasingFunction(s string) (EasingFunction, error) {
+	values, err := ParseValue(s)
+	if err != nil {
+		return EasingFunction{}, err
+	}
+	if len(values) != 1 || !productionGrammars["easing-function"].MatchValues(values) {
+		return EasingFunction{}, fmt.Errorf("invalid easing function %q", s)
+	}
+	if err := validateEasing(values); err != nil {
+		return EasingFunction{}, err
+	}
+	return easingOf(values[0]), nil
+}
+
+// easingOf converts a value that matched <easing-function>.
+func easingOf(v ComponentValue) EasingFunction {
+	if v.Type == IdentValue {
+		return easingKeywords[strings.ToLower(v.Text)]
+	}
+	groups := splitCommas(v.Args)
+	switch v.Text {
+	case "cubic-bezier":
+		var n [4]float64
+		for i, group := range groups {
+			n[i] = numberValue(group).(float64)
+		}
+		return EasingFunction{Name: v.Text, X1: n[0], Y1: n[1], X2: n[2], Y2: n[3]}
+	case "steps":
+		f := EasingFunction{Name: v.Text, Steps: integerValue(groups[0]).(int), Position: "jump-end"}
+		if len(groups) == 2 {
+			f.Position = strings.ToLower(groups[1][0].Text)
+			if p, ok := stepPositions[f.Position]; ok {
+				f.Position = p
+			}
+		}
+		return f
+	}
+	return EasingFunction{Name: v.Text, Stops: linearStops(groups)}
+}
+
+// linearStops returns the control points of the arguments of linear(). A
+// stop with two input percentages is two control points, and missing
+// inputs are spread evenly between their neighbors.
+func linearStops(groups [][]ComponentValue) []LinearStop {
+	var stops []LinearStop
+	for _, group := range groups {
+		var output float64
+		var inputs []float64
+		for _, v := range group {
+			if v.Type == PercentageValue || mathType(v) == "percentage" {
+				inputs = append(inputs, scaleOf(v))
+			} else {
+				output = numberValue([]ComponentValue{v}).(float64)
+			}
+		}
+		if len(inputs) == 0 {
+			inputs = []float64{math.NaN()}
+		}
+		for _, input := range inputs {
+			stops = append(stops, LinearStop{Input: input, Output: output})
+		}
+	}
+
+	if math.IsNaN(stops[0].Input) {
+		stops[0].Input = 0
+	}
+	largest := stops[0].Input
+	for i := range stops {
+		switch {
+		case math.IsNaN(stops[i].Input):
+			continue
+		case stops[i].Input < largest:
+			stops[i].Input = largest
+		default:
+			largest = stops[i].Input
+		}
+	}
+	if last := &stops[len(stops)-1]; math.IsNaN(last.Input) {
+		last.Input = math.Max(1, largest)
+	}
+	for i := 0; i < len(stops); i++ {
+		if !math.IsNaN(stops[i].Input) {
+			continue
+		}
+		end := i
+		for math.IsNaN(stops[end].Input) {
+			end++
+		}
+		from, to := stops[i-1].Input, stops[end].Input
+		for j := i; j < end; j++ {
+			stops[j].Input = from + (to-from)*float64(j-i+1)/float64(end-i+1)
+		}
+	}
+	return stops
+}
+
+// validateEasing checks the arguments of the step and linear easing
+// functions in values.
+func validateEasing(values []ComponentValue) error {
+	for _, v := range values {
+		if v.Type != FunctionValue {
+			continue
+		}
+		switch groups := splitCommas(v.Args); v.Text {
+		case "steps":
+			if len(groups) == 2 && groups[1][0].Is("jump-none") && integerValue(groups[0]).(int) < 2 {
+				return fmt.Errorf("steps() with jump-none needs at least 2 steps")
+			}
+		case "linear":
+			if len(groups) < 2 {
+				return fmt.Errorf("linear() needs at least 2 stops")
+			}
+		}
+	}
+	return nil
+}
+
+// Eval returns the output progress of f for the input progress x. Inputs
+// outside of [0,1] are extrapolated.
+func (f EasingFunction) Eval(x float64) float64 {
+	switch {
+	case f.Steps > 0:
+		return f.step(x)
+	case len(f.Stops) > 0:
+		return f.linear(x)
+	case f.Name == "linear":
+		return x
+	}
+	return f.cubicBezier(x)
+}
+
+// step implements the step easing function, without the before flag
+// that only matters in the before phase of animations with a delay.
+func (f EasingFunction) step(x float64) float64 {
+	current := math.Floor(x * float64(f.Steps))
+	if f.Position == "jump-start" || f.Position == "jump-both" {
+		current++
+	}
+	if x >= 0 && current < 0 {
+		current = 0
+	}
+	jumps := float64(f.Steps)
+	switch f.Position {
+	case "jump-none":
+		jumps--
+	case "jump-both":
+		jumps++
+	}
+	if x <= 1 && current > jumps {
+		current = jumps
+	}
+	return current / jumps
+}
+
+// linear interpolates between the stops of linear(), and extrapolates
+// from the first or the last two stops.
+func (f EasingFunction) linear(x float64) float64 {
+	stops := f.Stops
+	i := 0
+	for i+1 < len(stops) && stops[i+1].Input <= x {
+		i++
+	}
+	if i == len(stops)-1 {
+		if x == stops[i].Input {
+			return stops[i].Output
+		}
+		i--
+	}
+	a, b := stops[i], stops[i+1]
+	if a.Input == b.Input {
+		if x < a.Input {
+			return a.Output
+		}
+		return b.Output
+	}
+	return a.Output + (b.Output-a.Output)*(x-a.Input)/(b.Input-a.Input)
+}
+
+// cubicBezier solves the curve for x, and extrapolates it along the
+// tangents at its ends.
+func (f EasingFunction) cubicBezier(x float64) float64 {
+	switch {
+	case x < 0:
+		var slope float64
+		if f.X1 > 0 {
+			slope = f.Y1 / f.X1
+		} else if f.Y1 == 0 && f.X2 > 0 {
+			slope = f.Y2 / f.X2
+		}
+		return slope * x
+	case x > 1:
+		var slope float64
+		if f.X2 < 1 {
+			slope = (f.Y2 - 1) / (f.X2 - 1)
+		} else if f.Y2 == 1 && f.X1 < 1 {
+			slope = (f.Y1 - 1) / (f.X1 - 1)
+		}
+		return 1 + slope*(x-1)
+	}
+
+	bezier := func(t, p1, p2 float64) float64 {
+		return 3*(1-t)*(1-t)*t*p1 + 3*(1-t)*t*t*p2 + t*t*t
+	}
+	derivative := func(t, p1, p2 float64) float64 {
+		return 3*(1-t)*(1-t)*p1 + 6*(1-t)*t*(p2-p1) + 3*t*t*(1-p2)
+	}
+	const epsilon = 1e-9
+	// Newton's method converges quickly for most curves, and bisection
+	// handles the flat parts where the derivative vanishes
+	t := x
+	for i := 0; i < 8; i++ {
+		d := bezier(t, f.X1, f.X2) - x
+		if math.Abs(d) < epsilon {
+			return bezier(t, f.Y1, f.Y2)
+		}
+		slope := derivative(t, f.X1, f.X2)
+		if math.Abs(slope) < 1e-6 {
+			break
+		}
+		if t -= d / slope; t < 0 || t > 1 {
+			break
+		}
+	}
+	lo, hi := 0.0, 1.0
+	t = x
+	for hi-lo > epsilon {
+		if bezier(t, f.X1, f.X2) < x {
+			lo = t
+		} else {
+			hi = t
+		}
+		t = (lo + hi) / 2
+	}
+	return bezier(t, f.Y1, f.Y2)
+}
+
+func (f EasingFunction) String() string {
+	switch f.Name {
+	case "cubic-bezier":
+		return fmt.Sprintf("cubic-bezier(%s, %s, %s, %s)", formatNumber(f.X1), formatNumber(f.Y1), formatNumber(f.X2), formatNumber(f.Y2))
+	case "steps":
+		if f.Position == "jump-end" {
+			return fmt.Sprintf("steps(%d)", f.Steps)
+		}
+		return fmt.Sprintf("steps(%d, %s)", f.Steps, f.Position)
+	case "linear":
+		if len(f.Stops) == 0 {
+			return f.Name
+		}
+		stops := make([]string, len(f.Stops))
+		for i, s := range f.Stops {
+			stops[i] = formatNumber(s.Output) + " " + formatNumber(s.Input*100) + "%"
+		}
+		return "linear(" + strings.Join(stops, ", ") + ")"
+	}
+	return f.Name
+}
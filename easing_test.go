@@ -0,0 +1,86 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEasingFunction(t *testing.T) {
+	cases := []struct {
+		value      string
+		expected   EasingFunction
+		serialized string
+	}{
+		{"linear", EasingFunction{Name: "linear"}, "linear"},
+		{"EASE", EasingFunction{Name: "ease", X1: 0.25, Y1: 0.1, X2: 0.25, Y2: 1}, "ease"},
+		{"cubic-bezier(0.1, -0.6, 0.2, 1.5)", EasingFunction{Name: "cubic-bezier", X1: 0.1, Y1: -0.6, X2: 0.2, Y2: 1.5}, "cubic-bezier(0.1, -0.6, 0.2, 1.5)"},
+		{"step-start", EasingFunction{Name: "step-start", Steps: 1, Position: "jump-start"}, "step-start"},
+		{"steps(4)", EasingFunction{Name: "steps", Steps: 4, Position: "jump-end"}, "steps(4)"},
+		{"steps(3, start)", EasingFunction{Name: "steps", Steps: 3, Position: "jump-start"}, "steps(3, jump-start)"},
+		{"linear(0, 0.25, 1)", EasingFunction{Name: "linear", Stops: []LinearStop{{0, 0}, {0.5, 0.25}, {1, 1}}}, "linear(0 0%, 0.25 50%, 1 100%)"},
+		{"linear(0, 0.5 25% 75%, 1)", EasingFunction{Name: "linear", Stops: []LinearStop{{0, 0}, {0.25, 0.5}, {0.75, 0.5}, {1, 1}}}, "linear(0 0%, 0.5 25%, 0.5 75%, 1 100%)"},
+		{"linear(0, 20% 0.5, 0.6, 10% 0.8, 1)", EasingFunction{Name: "linear", Stops: []LinearStop{{0, 0}, {0.2, 0.5}, {0.2, 0.6}, {0.2, 0.8}, {1, 1}}}, "linear(0 0%, 0.5 20%, 0.6 20%, 0.8 20%, 1 100%)"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			f, err := ParseEasingFunction(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, f)
+			assert.Equal(t, tt.serialized, f.String())
+		})
+	}
+
+	for _, value := range []string{
+		"",
+		"ease ease",
+		"cubic-bezier(1.1, 0, 1, 1)",
+		"cubic-bezier(0, 0, 1)",
+		"steps(0)",
+		"steps(1.5)",
+		"steps(1, jump-none)",
+		"steps(2, middle)",
+		"linear(0)",
+		"linear(0 10% 20% 30%, 1)",
+	} {
+		_, err := ParseEasingFunction(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestEasingFunctionEval(t *testing.T) {
+	cases := []struct {
+		value    string
+		inputs   []float64
+		expected []float64
+	}{
+		{"linear", []float64{-0.5, 0, 0.3, 1, 2}, []float64{-0.5, 0, 0.3, 1, 2}},
+		{"ease", []float64{0, 0.25, 0.5, 1}, []float64{0, 0.4085, 0.8024, 1}},
+		{"ease-in-out", []float64{0, 0.5, 1}, []float64{0, 0.5, 1}},
+		{"cubic-bezier(0.5, -1, 0.5, 2)", []float64{-1, 0.5, 2}, []float64{2, 0.5, -1}},
+		{"cubic-bezier(0, 0, 1, 1)", []float64{-1, 0.7, 2}, []float64{-1, 0.7, 2}},
+		{"steps(4)", []float64{0, 0.24, 0.25, 0.99, 1}, []float64{0, 0, 0.25, 0.75, 1}},
+		{"steps(4, jump-start)", []float64{0, 0.24, 0.75, 1}, []float64{0.25, 0.25, 1, 1}},
+		{"steps(3, jump-none)", []float64{0, 0.5, 0.7, 1}, []float64{0, 0.5, 1, 1}},
+		{"steps(3, jump-both)", []float64{0, 0.4, 1}, []float64{0.25, 0.5, 1}},
+		{"step-end", []float64{-0.5, 0.5, 1.5}, []float64{-1, 0, 1}},
+		{"linear(0, 0.25 75%, 1)", []float64{-1, 0.375, 0.75, 0.875, 2}, []float64{-1.0 / 3, 0.125, 0.25, 0.625, 4}},
+		{"linear(0, 0.5 50% 50%, 1)", []float64{0.49, 0.5, 0.51}, []float64{0.49, 0.5, 0.51}},
+		{"linear(0 0%, 0 50%, 1 50%, 1 100%)", []float64{0.25, 0.5, 0.75}, []float64{0, 1, 1}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			f, err := ParseEasingFunction(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outputs := make([]float64, len(tt.inputs))
+			for i, x := range tt.inputs {
+				outputs[i] = f.Eval(x)
+			}
+			assert.InDeltaSlice(t, tt.expected, outputs, 1e-4)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilterFunction is a filter function of the filter and backdrop-filter
+// properties, or a url() reference to an SVG filter, with the defaults of
+// omitted arguments filled in.
+type FilterFunction struct {
+	// Name is the function name like "blur", or "url" for references.
+	Name string
+	// Amount is the argument of brightness(), contrast(), grayscale(),
+	// invert(), opacity(), saturate() and sepia() as a number, and the
+	// angle of hue-rotate() in degrees.
+	Amount float64
+	// Radius is the blur radius of blur() and drop-shadow().
+	Radius Length
+	// OffsetX, OffsetY and Color are the shadow of drop-shadow().
+	OffsetX, OffsetY Length
+	Color            Color
+	// URL is the filter reference of url().
+	URL string
+}
+
+func (f FilterFunction) String() string {
+	switch f.Name {
+	case "url":
+		return "url(" + strconv.Quote(f.URL) + ")"
+	case "blur":
+		return "blur(" + f.Radius.String() + ")"
+	case "hue-rotate":
+		return "hue-rotate(" + formatNumber(f.Amount) + "deg)"
+	case "drop-shadow":
+		return fmt.Sprintf("drop-shadow(%s %s %s %s)", f.Color, f.OffsetX, f.OffsetY, f.Radius)
+	}
+	return f.Name + "(" + formatNumber(f.Amount) + ")"
+}
+
+// Filter is the typed value of the filter and backdrop-filter properties.
+// none is an empty list of functions.
+type Filter struct {
+	Functions []FilterFunction
+}
+
+func (f Filter) String() string {
+	if len(f.Functions) == 0 {
+		return "none"
+	}
+	functions := make([]string, len(f.Functions))
+	for i, function := range f.Functions {
+		functions[i] = function.String()
+	}
+	return strings.Join(functions, " ")
+}
+
+// LegacyFilter is the style value of the deprecated Internet Explorer
+// filter syntax, like "progid:DXImageTransform.Microsoft.Alpha(Opacity=80)"
+// or "alpha(opacity=80)". It is kept as specified and has no effect.
+type LegacyFilter string
+
+// isLegacyFilter reports whether value uses the Internet Explorer filter
+// syntax.
+func isLegacyFilter(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.HasPrefix(value, "progid:") ||
+		strings.HasPrefix(value, "alpha(") && strings.Contains(value, "opacity=")
+}
+
+// filterHandler accepts the legacy Internet Explorer syntax besides the
+// standard filter functions.
+func filterHandler(value string) (Style, error) {
+	if isLegacyFilter(value) {
+		return Style{Value: LegacyFilter(strings.TrimSpace(value))}, nil
+	}
+	return propertyGrammars["filter"].typedHandler(filter)(value)
+}
+
+// filterAmountMax are the functions whose amount is clamped to 1.
+var filterAmountMax = map[string]bool{"grayscale": true, "invert": true, "opacity": true, "sepia": true}
+
+func filter(values []ComponentValue) interface{} {
+	var f Filter
+	for _, v := range values {
+		switch {
+		case v.Type == URLValue:
+			f.Functions = append(f.Functions, FilterFunction{Name: "url", URL: v.Text})
+		case v.Type == FunctionValue:
+			f.Functions = append(f.Functions, filterFunctionOf(v))
+		}
+	}
+	return f
+}
+
+func filterFunctionOf(v ComponentValue) FilterFunction {
+	f := FilterFunction{Name: v.Text}
+	switch v.Text {
+	case "blur":
+		if len(v.Args) > 0 {
+			f.Radius = lengthOf(v.Args[0])
+		}
+	case "hue-rotate":
+		if len(v.Args) > 0 {
+			f.Amount = angleDegrees(v.Args[0])
+		}
+	case "drop-shadow":
+		f.Color = Color{CurrentColor: true}
+		var lengths []Length
+		for _, arg := range v.Args {
+			if arg.Type == DimensionValue || arg.Type == NumberValue || mathType(arg) != "" {
+				lengths = append(lengths, lengthOf(arg))
+			} else {
+				f.Color = colorOf(arg)
+			}
+		}
+		f.OffsetX, f.OffsetY = lengths[0], lengths[1]
+		if len(lengths) == 3 {
+			f.Radius = lengths[2]
+		}
+	default:
+		f.Amount = 1
+		if len(v.Args) > 0 {
+			arg := v.Args[0]
+			f.Amount = arg.Number
+			switch {
+			case arg.Type == PercentageValue:
+				f.Amount /= 100
+			case arg.Type == FunctionValue:
+				f.Amount, _ = evalMath(arg, nil)
+			}
+		}
+		if filterAmountMax[f.Name] && f.Amount > 1 {
+			f.Amount = 1
+		}
+	}
+	return f
+}
@@ -0,0 +1,98 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"filter", "none", Filter{}},
+		{"filter", "blur()", Filter{[]FilterFunction{{Name: "blur"}}}},
+		{"filter", "blur(2px) brightness(150%)", Filter{[]FilterFunction{
+			{Name: "blur", Radius: Length{Value: 2, Unit: "px"}},
+			{Name: "brightness", Amount: 1.5},
+		}}},
+		{"filter", "contrast() grayscale(2) invert(50%) opacity(120%)", Filter{[]FilterFunction{
+			{Name: "contrast", Amount: 1},
+			{Name: "grayscale", Amount: 1},
+			{Name: "invert", Amount: 0.5},
+			{Name: "opacity", Amount: 1},
+		}}},
+		{"filter", "hue-rotate(-0.25turn) saturate(calc(2 * 2)) sepia(0)", Filter{[]FilterFunction{
+			{Name: "hue-rotate", Amount: -90},
+			{Name: "saturate", Amount: 4},
+			{Name: "sepia"},
+		}}},
+		{"filter", "hue-rotate()", Filter{[]FilterFunction{{Name: "hue-rotate"}}}},
+		{"filter", "drop-shadow(2px -3px)", Filter{[]FilterFunction{
+			{Name: "drop-shadow", OffsetX: Length{Value: 2, Unit: "px"}, OffsetY: Length{Value: -3, Unit: "px"}, Color: Color{CurrentColor: true}},
+		}}},
+		{"filter", "drop-shadow(1px 1px 4px red) url(#glow)", Filter{[]FilterFunction{
+			{Name: "drop-shadow", OffsetX: Length{Value: 1, Unit: "px"}, OffsetY: Length{Value: 1, Unit: "px"}, Radius: Length{Value: 4, Unit: "px"}, Color: Color{R: 1, A: 1}},
+			{Name: "url", URL: "#glow"},
+		}}},
+		{"backdrop-filter", "blur(10px)", Filter{[]FilterFunction{{Name: "blur", Radius: Length{Value: 10, Unit: "px"}}}}},
+		{"filter", "progid:DXImageTransform.Microsoft.gradient(startColorstr='#80000000', endColorstr='#80000000')",
+			LegacyFilter("progid:DXImageTransform.Microsoft.gradient(startColorstr='#80000000', endColorstr='#80000000')")},
+		{"filter", " Alpha(Opacity=80)", LegacyFilter("Alpha(Opacity=80)")},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for _, value := range []string{
+		"blur(-1px)",
+		"blur(10%)",
+		"brightness(-1)",
+		"hue-rotate(10px)",
+		"drop-shadow(1px)",
+		"drop-shadow(1px 1px -2px)",
+		"none blur(1px)",
+		"shadow(1px)",
+	} {
+		_, err := CSSStyle("filter", map[string]string{"filter": value})
+		assert.Error(t, err, value)
+	}
+	_, err := CSSStyle("backdrop-filter", map[string]string{"backdrop-filter": "progid:DXImageTransform.Microsoft.Blur(PixelRadius=2)"})
+	assert.Error(t, err)
+
+	f := Filter{[]FilterFunction{
+		{Name: "blur", Radius: Length{Value: 1, Unit: "px"}},
+		{Name: "hue-rotate", Amount: 90},
+		{Name: "drop-shadow", OffsetX: Length{Value: 1, Unit: "px"}, OffsetY: Length{}, Color: Color{CurrentColor: true}},
+		{Name: "url", URL: "a.svg#f"},
+	}}
+	assert.Equal(t, `blur(1px) hue-rotate(90deg) drop-shadow(currentcolor 1px 0 0) url("a.svg#f")`, f.String())
+	assert.Equal(t, "none", Filter{}.String())
+}
+
+func TestUnmarshalLegacyFilter(t *testing.T) {
+	css, err := Unmarshal([]byte(`
+.a { filter: progid:DXImageTransform.Microsoft.Alpha(Opacity=80); color: red }
+.b { filter: progid:DXImageTransform.Microsoft.Alpha(Opacity=80) progid:DXImageTransform.Microsoft.Blur(PixelRadius=2) }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"filter": "progid:DXImageTransform.Microsoft.Alpha(Opacity=80)",
+		"color":  "red",
+	}, css[".a"])
+	assert.Equal(t, "progid:DXImageTransform.Microsoft.Alpha(Opacity=80) progid:DXImageTransform.Microsoft.Blur(PixelRadius=2)", css[".b"]["filter"])
+
+	style, err := CSSStyle("filter", css[".a"])
+	assert.NoError(t, err)
+	assert.Equal(t, LegacyFilter("progid:DXImageTransform.Microsoft.Alpha(Opacity=80)"), style.Value)
+}
@@ -0,0 +1,761 @@
+package css
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Flex is the typed value of the flex shorthand. Components omitted from
+// the shorthand are 1 for Grow and Shrink and 0 for Basis; none is 0 0 auto.
+type Flex struct {
+	Grow, Shrink float64
+	Basis        Length
+}
+
+func (f Flex) String() string {
+	return formatNumber(f.Grow) + " " + formatNumber(f.Shrink) + " " + f.Basis.String()
+}
+
+// FlexFlow is the typed value of the flex-flow shorthand.
+type FlexFlow struct {
+	Direction, Wrap string
+}
+
+func (f FlexFlow) String() string {
+	return f.Direction + " " + f.Wrap
+}
+
+// Alignment is the typed value of justify-content, align-items, align-self
+// and align-content.
+type Alignment struct {
+	// Position is the alignment keyword, like "center", "flex-start",
+	// "space-between", "stretch" or "baseline".
+	Position string
+	// Overflow is "safe", "unsafe" or empty.
+	Overflow string
+	// Last is set for last baseline alignment.
+	Last bool
+}
+
+func (a Alignment) String() string {
+	switch {
+	case a.Last:
+		return "last " + a.Position
+	case a.Overflow != "":
+		return a.Overflow + " " + a.Position
+	}
+	return a.Position
+}
+
+func numberValue(values []ComponentValue) interface{} {
+	if values[0].Type == FunctionValue {
+		n, _ := evalMath(values[0], nil)
+		return n
+	}
+	return values[0].Number
+}
+
+func flex(values []ComponentValue) interface{} {
+	if values[0].Is("none") {
+		return Flex{Basis: Length{Keyword: "auto"}}
+	}
+	f := Flex{Grow: 1, Shrink: 1}
+	grow := true
+	for _, v := range values {
+		switch {
+		case v.Production == "flex-basis":
+			f.Basis = lengthOf(v)
+		case grow:
+			f.Grow, grow = numberValue([]ComponentValue{v}).(float64), false
+		default:
+			f.Shrink = numberValue([]ComponentValue{v}).(float64)
+		}
+	}
+	return f
+}
+
+func expandFlex(values []ComponentValue) map[string]string {
+	f := flex(values).(Flex)
+	return map[string]string{
+		"flex-grow":   formatNumber(f.Grow),
+		"flex-shrink": formatNumber(f.Shrink),
+		"flex-basis":  f.Basis.String(),
+	}
+}
+
+func flexFlow(values []ComponentValue) interface{} {
+	f := FlexFlow{Direction: "row", Wrap: "nowrap"}
+	for _, v := range values {
+		if v.Production == "flex-wrap" {
+			f.Wrap = strings.ToLower(v.Text)
+		} else {
+			f.Direction = strings.ToLower(v.Text)
+		}
+	}
+	return f
+}
+
+func expandFlexFlow(values []ComponentValue) map[string]string {
+	f := flexFlow(values).(FlexFlow)
+	return map[string]string{"flex-direction": f.Direction, "flex-wrap": f.Wrap}
+}
+
+func alignment(values []ComponentValue) interface{} {
+	var a Alignment
+	for _, v := range values {
+		switch keyword := strings.ToLower(v.Text); keyword {
+		case "safe", "unsafe":
+			a.Overflow = keyword
+		case "first":
+		case "legacy":
+			if a.Position == "" {
+				a.Position = keyword
+			}
+		case "last":
+			a.Last = true
+		default:
+			a.Position = keyword
+		}
+	}
+	return a
+}
+
+// gap converts the gap shorthand to the row and column gaps.
+func gap(values []ComponentValue) interface{} {
+	gaps := [2]Length{lengthOf(values[0]), lengthOf(values[0])}
+	if len(values) == 2 {
+		gaps[1] = lengthOf(values[1])
+	}
+	return gaps
+}
+
+func expandGap(values []ComponentValue) map[string]string {
+	gaps := gap(values).([2]Length)
+	return map[string]string{"row-gap": gaps[0].String(), "column-gap": gaps[1].String()}
+}
+
+// FlexItem is a child of a flex container.
+type FlexItem struct {
+	// Style is the computed style of the item.
+	Style map[string]string
+	// MinContent and MaxContent are the intrinsic sizes of the content box
+	// of the item, like the width of its longest word and of its text on
+	// a single line.
+	MinContent, MaxContent Size
+}
+
+// flexItem is a flex item during layout. Sizes are border box sizes along
+// the main or cross axis.
+type flexItem struct {
+	*layoutBox
+	axes             flexAxes
+	index            int
+	order            int
+	grow, shrink     float64
+	base, hypo       float64
+	minMain, maxMain resolvedLength
+	target           float64
+	violation        float64
+	frozen           bool
+	cross            float64
+	crossSize        resolvedLength
+	minCross         resolvedLength
+	maxCross         resolvedLength
+	align            Alignment
+	mainPos          float64
+	crossPos         float64
+}
+
+// flexLine is a line of items of a flex container.
+type flexLine struct {
+	items []*flexItem
+	cross float64
+	pos   float64
+}
+
+// flexAxes are the indexes of the margins, paddings and borders of the
+// start and end sides of the main and cross axes, in flex-relative
+// direction.
+type flexAxes struct {
+	row                  bool
+	mainStart, mainEnd   int
+	crossStart, crossEnd int
+}
+
+func newFlexAxes(direction, wrap string) flexAxes {
+	a := flexAxes{row: strings.HasPrefix(direction, "row")}
+	if a.row {
+		a.mainStart, a.mainEnd, a.crossStart, a.crossEnd = 3, 1, 0, 2
+	} else {
+		a.mainStart, a.mainEnd, a.crossStart, a.crossEnd = 0, 2, 3, 1
+	}
+	if strings.HasSuffix(direction, "-reverse") {
+		a.mainStart, a.mainEnd = a.mainEnd, a.mainStart
+	}
+	if wrap == "wrap-reverse" {
+		a.crossStart, a.crossEnd = a.crossEnd, a.crossStart
+	}
+	return a
+}
+
+// split returns the main and cross components of s.
+func (a flexAxes) split(s Size) (main, cross float64) {
+	if a.row {
+		return s.Width, s.Height
+	}
+	return s.Height, s.Width
+}
+
+// join returns the size with the main and cross components.
+func (a flexAxes) join(main, cross float64) Size {
+	if a.row {
+		return Size{main, cross}
+	}
+	return Size{cross, main}
+}
+
+// names returns the property for the main or cross axis, like min-width
+// for "min-" along a row.
+func (a flexAxes) names(prefix string, main bool) string {
+	if a.row == main {
+		return prefix + "width"
+	}
+	return prefix + "height"
+}
+
+// FlexLayout lays out the children of a flex container with the given
+// computed style. container is the size of its content box, with an
+// Indefinite height or width if it depends on the items. The items are
+// laid out in order of their order property, but their boxes are returned
+// in the order of items. Items with display: none or absolute positioning
+// don't take part in the layout and get an empty box. Baseline alignment
+// falls back to flex-start.
+func FlexLayout(style map[string]string, container Size, items []FlexItem) (Layout, error) {
+	c, err := newLayoutBox(style, container.Width)
+	if err != nil {
+		return Layout{}, err
+	}
+	direction, err := keywordOr(c, "flex-direction", "row")
+	if err != nil {
+		return Layout{}, err
+	}
+	wrap, err := keywordOr(c, "flex-wrap", "nowrap")
+	if err != nil {
+		return Layout{}, err
+	}
+	axes := newFlexAxes(direction, wrap)
+	reverse := strings.HasSuffix(direction, "-reverse")
+	containerMain, containerCross := axes.split(container)
+
+	rowGap, err := c.length("row-gap", container.Height)
+	if err != nil {
+		return Layout{}, err
+	}
+	columnGap, err := c.length("column-gap", container.Width)
+	if err != nil {
+		return Layout{}, err
+	}
+	mainGap, crossGap := columnGap.px, rowGap.px
+	if !axes.row {
+		mainGap, crossGap = crossGap, mainGap
+	}
+
+	layout := Layout{Boxes: make([]Rect, len(items))}
+	var flexItems []*flexItem
+	for i, item := range items {
+		f, err := newFlexItem(item, i, axes, container, containerMain)
+		if err != nil {
+			return Layout{}, err
+		}
+		if f != nil {
+			flexItems = append(flexItems, f)
+		}
+	}
+	sort.SliceStable(flexItems, func(i, j int) bool {
+		return flexItems[i].order < flexItems[j].order
+	})
+
+	lines := flexLines(flexItems, wrap != "nowrap" && containerMain != Indefinite, containerMain, mainGap)
+	if containerMain == Indefinite {
+		containerMain = 0
+		for _, line := range lines {
+			if used := line.hypotheticalMain(mainGap); used > containerMain {
+				containerMain = used
+			}
+		}
+	}
+	for _, line := range lines {
+		line.resolveFlexibleLengths(containerMain, mainGap)
+	}
+
+	// cross sizes of the lines
+	singleLine := wrap == "nowrap"
+	for _, line := range lines {
+		for _, f := range line.items {
+			if outer := f.cross + f.crossMargins(); outer > line.cross {
+				line.cross = outer
+			}
+		}
+	}
+	if singleLine && containerCross != Indefinite && len(lines) == 1 {
+		lines[0].cross = containerCross
+	}
+	if containerCross == Indefinite {
+		containerCross = gaps(crossGap, len(lines))
+		for _, line := range lines {
+			containerCross += line.cross
+		}
+	}
+
+	alignContent, err := alignmentOr(c, "align-content", "normal")
+	if err != nil {
+		return Layout{}, err
+	}
+	alignItems, err := alignmentOr(c, "align-items", "normal")
+	if err != nil {
+		return Layout{}, err
+	}
+	justifyContent, err := alignmentOr(c, "justify-content", "normal")
+	if err != nil {
+		return Layout{}, err
+	}
+	free := containerCross - gaps(crossGap, len(lines))
+	for _, line := range lines {
+		free -= line.cross
+	}
+	if position := alignContent.Position; !singleLine && free > 0 && (position == "normal" || position == "stretch") {
+		for _, line := range lines {
+			line.cross += free / float64(len(lines))
+		}
+		free = 0
+	}
+	offset, between := distribute(alignContent, free, len(lines), wrap == "wrap-reverse")
+	for _, line := range lines {
+		line.pos = offset
+		offset += line.cross + crossGap + between
+	}
+
+	for _, line := range lines {
+		line.alignCross(axes, alignItems, wrap == "wrap-reverse")
+		line.alignMain(axes, justifyContent, containerMain, mainGap, reverse)
+	}
+
+	for _, line := range lines {
+		for _, f := range line.items {
+			main, cross := f.mainPos, line.pos+f.crossPos
+			if reverse {
+				main = containerMain - main - f.target
+			}
+			if wrap == "wrap-reverse" {
+				cross = containerCross - cross - f.cross
+			}
+			size := axes.join(f.target, f.cross)
+			if axes.row {
+				layout.Boxes[f.index] = Rect{main, cross, size.Width, size.Height}
+			} else {
+				layout.Boxes[f.index] = Rect{cross, main, size.Width, size.Height}
+			}
+		}
+	}
+	layout.Size = axes.join(containerMain, containerCross)
+	return layout, nil
+}
+
+// keywordOr returns the keyword value of the property name of b, or
+// initial if b doesn't set it.
+func keywordOr(b *layoutBox, name, initial string) (string, error) {
+	v, err := b.value(name)
+	if s, ok := v.(string); ok {
+		return s, err
+	}
+	return initial, err
+}
+
+func alignmentOr(b *layoutBox, name, initial string) (Alignment, error) {
+	v, err := b.value(name)
+	if a, ok := v.(Alignment); ok {
+		return a, err
+	}
+	return Alignment{Position: initial}, err
+}
+
+// newFlexItem resolves the style of item and computes its flex base size
+// and hypothetical main size. It returns nil if the item doesn't take part
+// in the layout.
+func newFlexItem(item FlexItem, index int, axes flexAxes, container Size, containerMain float64) (*flexItem, error) {
+	b, err := newLayoutBox(item.Style, container.Width)
+	if err != nil {
+		return nil, err
+	}
+	if d, err := b.value("display"); err != nil {
+		return nil, err
+	} else if d, ok := d.(Display); ok && d.IsNone() {
+		return nil, nil
+	}
+	if p, err := b.value("position"); err != nil {
+		return nil, err
+	} else if p, ok := p.(PositionScheme); ok && p.IsAbsolute() {
+		return nil, nil
+	}
+
+	f := &flexItem{layoutBox: b, axes: axes, index: index, shrink: 1}
+	if order, err := b.value("order"); err != nil {
+		return nil, err
+	} else if order, ok := order.(int); ok {
+		f.order = order
+	}
+	if grow, err := b.value("flex-grow"); err != nil {
+		return nil, err
+	} else if grow, ok := grow.(float64); ok {
+		f.grow = grow
+	}
+	if shrink, err := b.value("flex-shrink"); err != nil {
+		return nil, err
+	} else if shrink, ok := shrink.(float64); ok {
+		f.shrink = shrink
+	}
+	if f.align, err = alignmentOr(b, "align-self", "auto"); err != nil {
+		return nil, err
+	}
+
+	mainEdges := b.edges(axes.mainStart, axes.mainEnd)
+	crossEdges := b.edges(axes.crossStart, axes.crossEnd)
+	_, containerCross := axes.split(container)
+	minContentMain, _ := axes.split(item.MinContent)
+	maxContentMain, maxContentCross := axes.split(item.MaxContent)
+
+	mainSize, err := b.size(axes.names("", true), containerMain, mainEdges)
+	if err != nil {
+		return nil, err
+	}
+	if f.minMain, err = b.size(axes.names("min-", true), containerMain, mainEdges); err != nil {
+		return nil, err
+	}
+	if f.maxMain, err = b.size(axes.names("max-", true), containerMain, mainEdges); err != nil {
+		return nil, err
+	}
+	basis, err := b.size("flex-basis", containerMain, mainEdges)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case basis.definite():
+		f.base = basis.px
+	case basis.keyword == "auto" && mainSize.definite():
+		f.base = mainSize.px
+	case basis.keyword == "min-content":
+		f.base = minContentMain + mainEdges
+	default:
+		f.base = maxContentMain + mainEdges
+	}
+
+	// the automatic minimum size of items that aren't scroll containers
+	if f.minMain.keyword == "auto" {
+		f.minMain = resolvedLength{px: mainEdges}
+		overflow, err := keywordOr(b, "overflow-"+map[bool]string{true: "x", false: "y"}[axes.row], "visible")
+		if err != nil {
+			return nil, err
+		}
+		if overflow == "visible" || overflow == "clip" {
+			min := minContentMain + mainEdges
+			if mainSize.definite() && mainSize.px < min {
+				min = mainSize.px
+			}
+			if f.maxMain.definite() && f.maxMain.px < min {
+				min = f.maxMain.px
+			}
+			f.minMain.px = min
+		}
+	}
+	if !f.minMain.definite() {
+		f.minMain = resolvedLength{px: mainEdges}
+	}
+	f.hypo = clampSize(f.base, f.minMain, f.maxMain)
+
+	if f.crossSize, err = b.size(axes.names("", false), containerCross, crossEdges); err != nil {
+		return nil, err
+	}
+	if f.minCross, err = b.size(axes.names("min-", false), containerCross, crossEdges); err != nil {
+		return nil, err
+	}
+	if f.maxCross, err = b.size(axes.names("max-", false), containerCross, crossEdges); err != nil {
+		return nil, err
+	}
+	if !f.minCross.definite() {
+		f.minCross = resolvedLength{px: crossEdges}
+	}
+	f.cross = maxContentCross + crossEdges
+	if f.crossSize.definite() {
+		f.cross = f.crossSize.px
+	}
+	f.cross = clampSize(f.cross, f.minCross, f.maxCross)
+	return f, nil
+}
+
+// flexLines collects the items into lines. If wrap is set, a line breaks
+// before an item that doesn't fit into the main size.
+func flexLines(items []*flexItem, wrap bool, main, gap float64) []*flexLine {
+	var lines []*flexLine
+	var line *flexLine
+	used := 0.0
+	for _, f := range items {
+		outer := f.hypo + f.mainMargins()
+		if line == nil || wrap && used+gap+outer > main {
+			line = &flexLine{}
+			lines = append(lines, line)
+			used = -gap
+		}
+		used += gap + outer
+		line.items = append(line.items, f)
+	}
+	return lines
+}
+
+func (f *flexItem) mainMargins() float64 {
+	return f.margins(f.axes.mainStart, f.axes.mainEnd)
+}
+
+func (f *flexItem) crossMargins() float64 {
+	return f.margins(f.axes.crossStart, f.axes.crossEnd)
+}
+
+// hypotheticalMain returns the outer hypothetical main size of the line.
+func (l *flexLine) hypotheticalMain(gap float64) float64 {
+	used := gaps(gap, len(l.items))
+	for _, f := range l.items {
+		used += f.hypo + f.mainMargins()
+	}
+	return used
+}
+
+// resolveFlexibleLengths grows or shrinks the items of the line to fill
+// the main size, freezing items at their min or max sizes.
+func (l *flexLine) resolveFlexibleLengths(main, gap float64) {
+	grow := l.hypotheticalMain(gap) < main
+	for _, f := range l.items {
+		f.target, f.frozen = f.hypo, false
+		if grow && (f.grow == 0 || f.base > f.hypo) || !grow && (f.shrink == 0 || f.base < f.hypo) {
+			f.frozen = true
+		}
+	}
+	initialFree := l.freeSpace(main, gap)
+	// every pass freezes at least one item
+	for pass := 0; pass < len(l.items); pass++ {
+		free := l.freeSpace(main, gap)
+		factors, scaled := 0.0, 0.0
+		for _, f := range l.items {
+			if !f.frozen {
+				factors += f.factor(grow)
+				scaled += f.shrink * f.innerBase()
+			}
+		}
+		if factors == 0 && scaled == 0 {
+			break
+		}
+		if factors < 1 && absFloat(initialFree*factors) < absFloat(free) {
+			free = initialFree * factors
+		}
+
+		violation := 0.0
+		for _, f := range l.items {
+			if f.frozen {
+				continue
+			}
+			f.target = f.base
+			switch {
+			case grow && factors > 0:
+				f.target += free * f.grow / factors
+			case !grow && scaled > 0:
+				f.target += free * f.shrink * f.innerBase() / scaled
+			}
+			clamped := clampSize(f.target, f.minMain, f.maxMain)
+			violation += clamped - f.target
+			f.violation, f.target = clamped-f.target, clamped
+		}
+		// sizes that overflowed can't be distributed, so they freeze all
+		// items like a line without violations
+		done := violation == 0 || !finite(violation) || !finite(free)
+		for _, f := range l.items {
+			if !f.frozen && (done || violation > 0 && f.violation > 0 || violation < 0 && f.violation < 0) {
+				f.frozen = true
+			}
+		}
+		if done {
+			break
+		}
+	}
+	for _, f := range l.items {
+		if !f.frozen {
+			f.target = clampSize(f.target, f.minMain, f.maxMain)
+		}
+	}
+}
+
+func finite(n float64) bool {
+	return !math.IsInf(n, 0) && !math.IsNaN(n)
+}
+
+// freeSpace returns the main size left by the targets of frozen items and
+// the flex base sizes of the others.
+func (l *flexLine) freeSpace(main, gap float64) float64 {
+	free := main - gaps(gap, len(l.items))
+	for _, f := range l.items {
+		free -= f.mainMargins()
+		if f.frozen {
+			free -= f.target
+		} else {
+			free -= f.base
+		}
+	}
+	return free
+}
+
+// factor returns the flex grow or shrink factor of the item.
+func (f *flexItem) factor(grow bool) float64 {
+	if grow {
+		return f.grow
+	}
+	return f.shrink
+}
+
+// innerBase returns the flex base size of the content box, which scales
+// the shrink factor.
+func (f *flexItem) innerBase() float64 {
+	return f.base - f.edges(f.axes.mainStart, f.axes.mainEnd)
+}
+
+// alignCross stretches the items of the line and positions them along the
+// cross axis, relative to the line.
+func (l *flexLine) alignCross(axes flexAxes, alignItems Alignment, wrapReverse bool) {
+	for _, f := range l.items {
+		align := f.align
+		if align.Position == "auto" {
+			align = alignItems
+		}
+		autoStart, autoEnd := f.autoMargin[axes.crossStart], f.autoMargin[axes.crossEnd]
+		if (align.Position == "normal" || align.Position == "stretch") && !f.crossSize.definite() && !autoStart && !autoEnd {
+			f.cross = clampSize(l.cross-f.crossMargins(), f.minCross, f.maxCross)
+		}
+		free := l.cross - f.cross - f.crossMargins()
+		f.crossPos = f.margin[axes.crossStart]
+		switch {
+		case free > 0 && autoStart && autoEnd:
+			f.crossPos += free / 2
+		case free > 0 && autoStart:
+			f.crossPos += free
+		case free > 0 && autoEnd:
+		default:
+			offset, _ := distribute(selfAlignment(align), free, 1, wrapReverse)
+			f.crossPos += offset
+		}
+	}
+}
+
+// selfAlignment maps the self-alignment keywords of align-self to the
+// equivalent content alignment.
+func selfAlignment(a Alignment) Alignment {
+	switch a.Position {
+	case "self-start":
+		a.Position = "start"
+	case "self-end":
+		a.Position = "end"
+	case "normal", "stretch", "baseline":
+		a.Position = "flex-start"
+	}
+	return a
+}
+
+// alignMain resolves auto margins and justify-content and positions the
+// items of the line along the main axis.
+func (l *flexLine) alignMain(axes flexAxes, justify Alignment, main, gap float64, reverse bool) {
+	free := main - gaps(gap, len(l.items))
+	autoMargins := 0
+	for _, f := range l.items {
+		free -= f.target + f.mainMargins()
+		for _, side := range []int{axes.mainStart, axes.mainEnd} {
+			if f.autoMargin[side] {
+				autoMargins++
+			}
+		}
+	}
+	offset, between, auto := 0.0, 0.0, 0.0
+	if free > 0 && autoMargins > 0 {
+		auto = free / float64(autoMargins)
+	} else {
+		if !axes.row && (justify.Position == "left" || justify.Position == "right") {
+			justify.Position = "start"
+		}
+		offset, between = distribute(justify, free, len(l.items), reverse)
+	}
+	for _, f := range l.items {
+		if f.autoMargin[axes.mainStart] {
+			offset += auto
+		}
+		f.mainPos = offset + f.margin[axes.mainStart]
+		offset = f.mainPos + f.target + f.margin[axes.mainEnd] + gap + between
+		if f.autoMargin[axes.mainEnd] {
+			offset += auto
+		}
+	}
+}
+
+// distribute returns the offset of the first of n boxes and the extra
+// space between boxes for the alignment a, given the free space along the
+// axis. reverse is set if the flex-start side of the axis is the end side
+// of the writing mode.
+func distribute(a Alignment, free float64, n int, reverse bool) (offset, between float64) {
+	position := a.Position
+	switch position {
+	case "start", "left":
+		position = "flex-start"
+		if reverse {
+			position = "flex-end"
+		}
+	case "end", "right":
+		position = "flex-end"
+		if reverse {
+			position = "flex-start"
+		}
+	}
+	if free < 0 {
+		switch {
+		case a.Overflow == "safe":
+			return 0, 0
+		case position == "space-between":
+			position = "flex-start"
+		case position == "space-around", position == "space-evenly":
+			position = "center"
+		}
+	}
+	switch position {
+	case "flex-end":
+		return free, 0
+	case "center":
+		return free / 2, 0
+	case "space-between":
+		if n > 1 {
+			return 0, free / float64(n-1)
+		}
+	case "space-around":
+		return free / float64(n) / 2, free / float64(n)
+	case "space-evenly":
+		return free / float64(n+1), free / float64(n+1)
+	}
+	return 0, 0
+}
+
+// gaps returns the size of the gaps between n boxes.
+func gaps(gap float64, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	return gap * float64(n-1)
+}
+
+func absFloat(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
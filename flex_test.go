@@ -0,0 +1,273 @@
+package css
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlexProperties(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"flex", "none", Flex{Basis: Length{Keyword: "auto"}}},
+		{"flex", "auto", Flex{Grow: 1, Shrink: 1, Basis: Length{Keyword: "auto"}}},
+		{"flex", "2", Flex{Grow: 2, Shrink: 1}},
+		{"flex", "2 0", Flex{Grow: 2}},
+		{"flex", "1 30%", Flex{Grow: 1, Shrink: 1, Basis: percent(30)}},
+		{"flex", "content 0 3", Flex{Shrink: 3, Basis: Length{Keyword: "content"}}},
+		{"flex-grow", "1.5", 1.5},
+		{"flex-shrink", "calc(2 * 2)", 4.0},
+		{"flex-basis", "max-content", Length{Keyword: "max-content"}},
+		{"flex-direction", "Column-Reverse", "column-reverse"},
+		{"flex-wrap", "wrap", "wrap"},
+		{"flex-flow", "wrap", FlexFlow{Direction: "row", Wrap: "wrap"}},
+		{"flex-flow", "wrap-reverse column", FlexFlow{Direction: "column", Wrap: "wrap-reverse"}},
+		{"justify-content", "space-between", Alignment{Position: "space-between"}},
+		{"justify-content", "safe right", Alignment{Position: "right", Overflow: "safe"}},
+		{"align-items", "last baseline", Alignment{Position: "baseline", Last: true}},
+		{"align-items", "first baseline", Alignment{Position: "baseline"}},
+		{"align-self", "unsafe self-end", Alignment{Position: "self-end", Overflow: "unsafe"}},
+		{"align-content", "space-evenly", Alignment{Position: "space-evenly"}},
+		{"gap", "10px", [2]Length{{Value: 10, Unit: "px"}, {Value: 10, Unit: "px"}}},
+		{"gap", "normal 5%", [2]Length{{Keyword: "normal"}, percent(5)}},
+		{"row-gap", "1em", Length{Value: 1, Unit: "em"}},
+		{"order", "-1", -1},
+		{"min-width", "min-content", Length{Keyword: "min-content"}},
+		{"max-height", "none", Length{Keyword: "none"}},
+		{"box-sizing", "border-box", "border-box"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"flex":            "1 2 3",
+		"flex-grow":       "-1",
+		"flex-basis":      "none",
+		"flex-flow":       "row column",
+		"justify-content": "baseline",
+		"align-items":     "space-between",
+		"align-self":      "left",
+		"align-content":   "safe stretch",
+		"gap":             "-1px",
+		"order":           "1.5",
+		"max-width":       "auto",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	assert.Equal(t, "1 1 0", Flex{Grow: 1, Shrink: 1}.String())
+	assert.Equal(t, "last baseline", Alignment{Position: "baseline", Last: true}.String())
+	assert.Equal(t, "safe center", Alignment{Position: "center", Overflow: "safe"}.String())
+}
+
+func TestExpandFlex(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{"flex", "1", map[string]string{"flex-grow": "1", "flex-shrink": "1", "flex-basis": "0"}},
+		{"flex", "none", map[string]string{"flex-grow": "0", "flex-shrink": "0", "flex-basis": "auto"}},
+		{"flex", "3 10px", map[string]string{"flex-grow": "3", "flex-shrink": "1", "flex-basis": "10px"}},
+		{"flex", "inherit", map[string]string{"flex-grow": "inherit", "flex-shrink": "inherit", "flex-basis": "inherit"}},
+		{"flex-flow", "column", map[string]string{"flex-direction": "column", "flex-wrap": "nowrap"}},
+		{"gap", "1px 2px", map[string]string{"row-gap": "1px", "column-gap": "2px"}},
+		{"gap", "normal", map[string]string{"row-gap": "normal", "column-gap": "normal"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			longhands, err := ExpandShorthand(tt.name, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, longhands)
+		})
+	}
+}
+
+func TestFlexLayout(t *testing.T) {
+	item := func(style map[string]string, width, height float64) FlexItem {
+		return FlexItem{Style: style, MaxContent: Size{width, height}}
+	}
+	cases := []struct {
+		name      string
+		style     map[string]string
+		container Size
+		items     []FlexItem
+		expected  Layout
+	}{
+		{
+			"grow with gaps and margins",
+			map[string]string{"gap": "10px"},
+			Size{300, Indefinite},
+			[]FlexItem{
+				item(map[string]string{"flex": "1"}, 50, 20),
+				item(map[string]string{"flex": "2", "margin": "5px"}, 50, 30),
+				item(map[string]string{"width": "40px", "align-self": "center"}, 50, 10),
+			},
+			Layout{Size{300, 40}, []Rect{{0, 0, 100.0 - 70.0/3, 40}, {91.0 + 2.0/3, 5, 153.0 + 1.0/3, 30}, {260, 15, 40, 10}}},
+		},
+		{
+			"shrink by flex base size",
+			nil,
+			Size{120, 10},
+			[]FlexItem{
+				item(map[string]string{"width": "100px"}, 0, 0),
+				item(map[string]string{"width": "50px"}, 0, 0),
+			},
+			Layout{Size{120, 10}, []Rect{{0, 0, 80, 10}, {80, 0, 40, 10}}},
+		},
+		{
+			"min size violation",
+			map[string]string{"align-items": "flex-start"},
+			Size{100, 10},
+			[]FlexItem{
+				item(map[string]string{"width": "100px"}, 0, 5),
+				item(map[string]string{"width": "100px", "min-width": "80px"}, 0, 5),
+			},
+			Layout{Size{100, 10}, []Rect{{0, 0, 20, 5}, {20, 0, 80, 5}}},
+		},
+		{
+			"automatic minimum size",
+			nil,
+			Size{100, 10},
+			[]FlexItem{
+				{Style: map[string]string{"width": "100px"}, MinContent: Size{70, 0}},
+				item(map[string]string{"width": "100px", "overflow": "hidden"}, 0, 0),
+			},
+			Layout{Size{100, 10}, []Rect{{0, 0, 70, 10}, {70, 0, 30, 10}}},
+		},
+		{
+			"wrap and stretch lines",
+			map[string]string{"flex-wrap": "wrap", "gap": "10px"},
+			Size{100, 100},
+			[]FlexItem{
+				item(map[string]string{"width": "40px"}, 0, 20),
+				item(map[string]string{"width": "40px"}, 0, 20),
+				item(map[string]string{"width": "40px"}, 0, 20),
+			},
+			Layout{Size{100, 100}, []Rect{{0, 0, 40, 45}, {50, 0, 40, 45}, {0, 55, 40, 45}}},
+		},
+		{
+			"align-content center",
+			map[string]string{"flex-flow": "row wrap", "gap": "10px", "align-content": "center"},
+			Size{100, 100},
+			[]FlexItem{
+				item(map[string]string{"width": "40px"}, 0, 20),
+				item(map[string]string{"width": "40px"}, 0, 20),
+				item(map[string]string{"width": "40px"}, 0, 20),
+			},
+			Layout{Size{100, 100}, []Rect{{0, 25, 40, 20}, {50, 25, 40, 20}, {0, 55, 40, 20}}},
+		},
+		{
+			"wrap-reverse",
+			map[string]string{"flex-wrap": "wrap-reverse"},
+			Size{100, 50},
+			[]FlexItem{
+				item(map[string]string{"width": "60px"}, 0, 10),
+				item(map[string]string{"width": "60px"}, 0, 10),
+			},
+			Layout{Size{100, 50}, []Rect{{0, 25, 60, 25}, {0, 0, 60, 25}}},
+		},
+		{
+			"space-between in row-reverse",
+			map[string]string{"flex-direction": "row-reverse", "justify-content": "space-between"},
+			Size{100, 10},
+			[]FlexItem{
+				item(map[string]string{"width": "20px"}, 0, 0),
+				item(map[string]string{"width": "20px"}, 0, 0),
+				item(map[string]string{"width": "20px"}, 0, 0),
+			},
+			Layout{Size{100, 10}, []Rect{{80, 0, 20, 10}, {40, 0, 20, 10}, {0, 0, 20, 10}}},
+		},
+		{
+			"start in row-reverse",
+			map[string]string{"flex-direction": "row-reverse", "justify-content": "start"},
+			Size{100, 10},
+			[]FlexItem{item(map[string]string{"width": "20px"}, 0, 0)},
+			Layout{Size{100, 10}, []Rect{{0, 0, 20, 10}}},
+		},
+		{
+			"column with indefinite height",
+			map[string]string{"flex-direction": "column", "align-items": "center"},
+			Size{100, Indefinite},
+			[]FlexItem{
+				item(nil, 30, 20),
+				item(nil, 50, 10),
+			},
+			Layout{Size{100, 30}, []Rect{{35, 0, 30, 20}, {25, 20, 50, 10}}},
+		},
+		{
+			"order and auto margins",
+			nil,
+			Size{100, 10},
+			[]FlexItem{
+				item(map[string]string{"width": "20px", "order": "1"}, 0, 0),
+				item(map[string]string{"width": "20px", "margin-left": "auto", "margin-top": "auto"}, 0, 4),
+			},
+			Layout{Size{100, 10}, []Rect{{80, 0, 20, 10}, {60, 6, 20, 4}}},
+		},
+		{
+			"box sizing and hidden items",
+			nil,
+			Size{200, 20},
+			[]FlexItem{
+				item(map[string]string{"width": "50px", "padding": "5px", "border": "1px solid"}, 0, 0),
+				item(map[string]string{"display": "none"}, 10, 10),
+				item(map[string]string{"width": "50px", "padding": "5px", "box-sizing": "border-box"}, 0, 0),
+				item(map[string]string{"position": "absolute"}, 10, 10),
+			},
+			Layout{Size{200, 20}, []Rect{{0, 0, 62, 20}, {}, {62, 0, 50, 20}, {}}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := FlexLayout(tt.style, tt.container, tt.items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected.Size, layout.Size)
+			assert.Equal(t, len(tt.expected.Boxes), len(layout.Boxes))
+			for i, box := range tt.expected.Boxes {
+				assert.InDeltaSlice(t, []float64{box.X, box.Y, box.Width, box.Height},
+					[]float64{layout.Boxes[i].X, layout.Boxes[i].Y, layout.Boxes[i].Width, layout.Boxes[i].Height}, 1e-9, "box %d", i)
+			}
+		})
+	}
+
+	_, err := FlexLayout(map[string]string{"flex-direction": "diagonal"}, Size{100, 100}, nil)
+	assert.Error(t, err)
+	_, err = FlexLayout(nil, Size{100, 100}, []FlexItem{{Style: map[string]string{"flex": "-1"}}})
+	assert.Error(t, err)
+}
+
+func TestFlexLayoutOverflow(t *testing.T) {
+	for _, style := range []map[string]string{
+		{"width": "1e309px"},
+		{"width": "calc(1px / 0)"},
+		{"flex-basis": "calc(1e308px * 10)"},
+		{"flex-grow": "1e308"},
+		{"padding": "1e308px"},
+	} {
+		start := time.Now()
+		// the layout may fail, but must not loop forever
+		_, _ = FlexLayout(nil, Size{100, 100}, []FlexItem{{Style: style}, {Style: map[string]string{"flex": "1"}}})
+		_, _ = FlexLayout(nil, Size{100, 100}, []FlexItem{{Style: style}, {Style: map[string]string{"width": "200px"}}})
+		assert.Less(t, time.Since(start), time.Second, style)
+	}
+}
@@ -0,0 +1,263 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Font is the typed value of the font shorthand.
+type Font struct {
+	Style FontStyle
+	// Variant is "normal" or "small-caps".
+	Variant    string
+	Weight     FontWeight
+	Stretch    Length
+	Size       Length
+	LineHeight Length
+	Family     []FontFamily
+	// System is the system font keyword, like "caption", if the value is
+	// one. The other fields hold the font it stands for.
+	System string
+}
+
+// FontFamily is a family name or, if Generic is set, a generic family
+// like serif.
+type FontFamily struct {
+	Name    string
+	Generic bool
+}
+
+func (f FontFamily) String() string {
+	if f.Generic {
+		return f.Name
+	}
+	for _, word := range strings.Split(f.Name, " ") {
+		v := ComponentValue{Type: IdentValue, Text: word}
+		if word == "" || !isIdentifier(word) || !isCustomIdent(v) || productionGrammars["generic-family"].MatchValues([]ComponentValue{v}) {
+			return strconv.Quote(f.Name)
+		}
+	}
+	return f.Name
+}
+
+// isIdentifier reports whether s can be written as an identifier without
+// escapes.
+func isIdentifier(s string) bool {
+	t := valueTokenizer{s: s}
+	return t.startsIdent() && t.name() == s && t.pos == len(s)
+}
+
+// FontStyle is the typed value of font-style. Angle is the slant of
+// oblique fonts in degrees.
+type FontStyle struct {
+	Style string
+	Angle float64
+}
+
+func (s FontStyle) String() string {
+	if s.Style == "oblique" && s.Angle != defaultObliqueAngle {
+		return "oblique " + formatNumber(s.Angle) + "deg"
+	}
+	return s.Style
+}
+
+const defaultObliqueAngle = 14
+
+// FontWeight is the typed value of font-weight. Weight is between 1 and
+// 1000, normal is 400 and bold is 700. Keyword is set instead for the
+// relative weights bolder and lighter.
+type FontWeight struct {
+	Weight  float64
+	Keyword string
+}
+
+// Resolve returns the weight, where relative weights are resolved against
+// the weight inherited from the parent.
+func (w FontWeight) Resolve(parent float64) float64 {
+	switch w.Keyword {
+	case "bolder":
+		switch {
+		case parent < 350:
+			return 400
+		case parent < 550:
+			return 700
+		case parent < 900:
+			return 900
+		}
+		return parent
+	case "lighter":
+		switch {
+		case parent < 100:
+			return parent
+		case parent < 550:
+			return 100
+		case parent < 750:
+			return 400
+		}
+		return 700
+	}
+	return w.Weight
+}
+
+func (w FontWeight) String() string {
+	if w.Keyword != "" {
+		return w.Keyword
+	}
+	return formatNumber(w.Weight)
+}
+
+// FontFeature is a feature tag of font-feature-settings, like "liga", and
+// its value, 1 for on and 0 for off.
+type FontFeature struct {
+	Tag   string
+	Value int
+}
+
+// fontStretches are the percentages of the font-stretch keywords.
+var fontStretches = map[string]float64{
+	"ultra-condensed": 50, "extra-condensed": 62.5, "condensed": 75, "semi-condensed": 87.5, "normal": 100,
+	"semi-expanded": 112.5, "expanded": 125, "extra-expanded": 150, "ultra-expanded": 200,
+}
+
+func initialFont() Font {
+	return Font{
+		Style:      FontStyle{Style: "normal"},
+		Variant:    "normal",
+		Weight:     FontWeight{Weight: 400},
+		Stretch:    percent(100),
+		Size:       Length{Keyword: "medium"},
+		LineHeight: Length{Keyword: "normal"},
+	}
+}
+
+func font(values []ComponentValue) interface{} {
+	f := initialFont()
+	if values[0].Production == "system-font" {
+		// system fonts depend on the platform, use a typical UI font
+		f.System = strings.ToLower(values[0].Text)
+		f.Size = Length{Value: 13, Unit: "px"}
+		f.Family = []FontFamily{{Name: "system-ui", Generic: true}}
+		if f.System == "small-caption" {
+			f.Variant = "small-caps"
+		}
+		return f
+	}
+
+	var family []ComponentValue
+	for i, v := range values {
+		switch v.Production {
+		case "font-style":
+			if v.Type != IdentValue {
+				continue // the angle of oblique
+			}
+			end := i + 1
+			if end < len(values) && values[end].Production == "font-style" {
+				end++
+			}
+			f.Style = fontStyle(values[i:end]).(FontStyle)
+		case "font-variant-css2":
+			f.Variant = strings.ToLower(v.Text)
+		case "font-weight":
+			f.Weight = fontWeight(values[i : i+1]).(FontWeight)
+		case "font-width-css3":
+			f.Stretch = fontStretch(values[i : i+1]).(Length)
+		case "font-size":
+			f.Size = lengthOf(v)
+		case "line-height":
+			f.LineHeight = lengthOf(v)
+		case "font-family":
+			family = append(family, v)
+		}
+	}
+	f.Family = fontFamilies(family).([]FontFamily)
+	return f
+}
+
+func fontFamilies(values []ComponentValue) interface{} {
+	var families []FontFamily
+	for _, group := range splitCommas(values) {
+		if len(group) == 1 && group[0].Type == StringValue {
+			families = append(families, FontFamily{Name: group[0].Text})
+			continue
+		}
+		var words []string
+		for _, v := range group {
+			words = append(words, v.Text)
+		}
+		generic := len(group) == 1 && productionGrammars["generic-family"].MatchValues(group)
+		name := strings.Join(words, " ")
+		if generic {
+			name = strings.ToLower(name)
+		}
+		families = append(families, FontFamily{Name: name, Generic: generic})
+	}
+	return families
+}
+
+func fontStyle(values []ComponentValue) interface{} {
+	s := FontStyle{Style: strings.ToLower(values[0].Text)}
+	if s.Style == "oblique" {
+		s.Angle = defaultObliqueAngle
+		if len(values) > 1 {
+			s.Angle = angleDegrees(values[1])
+		}
+	}
+	return s
+}
+
+func fontWeight(values []ComponentValue) interface{} {
+	v := values[0]
+	switch {
+	case v.Type == NumberValue:
+		return FontWeight{Weight: v.Number}
+	case v.Is("bold"):
+		return FontWeight{Weight: 700}
+	case v.Is("bolder") || v.Is("lighter"):
+		return FontWeight{Keyword: strings.ToLower(v.Text)}
+	}
+	return FontWeight{Weight: 400}
+}
+
+func fontStretch(values []ComponentValue) interface{} {
+	if values[0].Type == IdentValue {
+		return percent(fontStretches[strings.ToLower(values[0].Text)])
+	}
+	return lengthOf(values[0])
+}
+
+func fontFeatureSettings(values []ComponentValue) interface{} {
+	var features []FontFeature
+	if values[0].Is("normal") {
+		return features
+	}
+	for _, group := range splitCommas(values) {
+		feature := FontFeature{Tag: group[0].Text, Value: 1}
+		if len(group) > 1 {
+			switch {
+			case group[1].Is("off"):
+				feature.Value = 0
+			case group[1].Type == NumberValue:
+				feature.Value = int(group[1].Number)
+			}
+		}
+		features = append(features, feature)
+	}
+	return features
+}
+
+func expandFont(values []ComponentValue) map[string]string {
+	f := font(values).(Font)
+	families := make([]string, len(f.Family))
+	for i, family := range f.Family {
+		families[i] = family.String()
+	}
+	return map[string]string{
+		"font-style":   f.Style.String(),
+		"font-variant": f.Variant,
+		"font-weight":  f.Weight.String(),
+		"font-stretch": f.Stretch.String(),
+		"font-size":    f.Size.String(),
+		"line-height":  f.LineHeight.String(),
+		"font-family":  strings.Join(families, ", "),
+	}
+}
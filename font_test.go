@@ -0,0 +1,115 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFontStyles(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"font-family", `"Helvetica Neue", Times  New Roman, SERIF, "serif"`, []FontFamily{
+			{Name: "Helvetica Neue"},
+			{Name: "Times New Roman"},
+			{Name: "serif", Generic: true},
+			{Name: "serif"},
+		}},
+		{"font-size", "x-large", Length{Keyword: "x-large"}},
+		{"font-size", "smaller", Length{Keyword: "smaller"}},
+		{"font-size", "1.2em", Length{Value: 1.2, Unit: "em"}},
+		{"font-size", "80%", percent(80)},
+		{"font-weight", "bold", FontWeight{Weight: 700}},
+		{"font-weight", "normal", FontWeight{Weight: 400}},
+		{"font-weight", "1", FontWeight{Weight: 1}},
+		{"font-weight", "lighter", FontWeight{Keyword: "lighter"}},
+		{"font-style", "italic", FontStyle{Style: "italic"}},
+		{"font-style", "oblique", FontStyle{Style: "oblique", Angle: 14}},
+		{"font-style", "oblique -10deg", FontStyle{Style: "oblique", Angle: -10}},
+		{"font-stretch", "semi-condensed", percent(87.5)},
+		{"font-stretch", "150%", percent(150)},
+		{"font-variant", "Small-Caps", "small-caps"},
+		{"font-feature-settings", `"liga" off, "ss01", "cv02" 3`, []FontFeature{{"liga", 0}, {"ss01", 1}, {"cv02", 3}}},
+		{"font-feature-settings", "normal", []FontFeature(nil)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"font-weight":           "0",
+		"font-size":             "-1px",
+		"font-style":            "oblique 100deg",
+		"font-stretch":          "wide",
+		"font-family":           "serif,",
+		"font-feature-settings": "liga",
+		"font":                  "bold italic serif",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestFont(t *testing.T) {
+	style, err := CSSStyle("font", map[string]string{"font": "oblique 20deg small-caps 600 condensed 12px/1.5 'Fira Sans', sans-serif"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Font{
+		Style:      FontStyle{Style: "oblique", Angle: 20},
+		Variant:    "small-caps",
+		Weight:     FontWeight{Weight: 600},
+		Stretch:    percent(75),
+		Size:       Length{Value: 12, Unit: "px"},
+		LineHeight: Length{Value: 1.5},
+		Family:     []FontFamily{{Name: "Fira Sans"}, {Name: "sans-serif", Generic: true}},
+	}, style.Value)
+
+	style, err = CSSStyle("font", map[string]string{"font": "small-caption"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := style.Value.(Font)
+	assert.Equal(t, "small-caption", f.System)
+	assert.Equal(t, "small-caps", f.Variant)
+}
+
+func TestExpandFont(t *testing.T) {
+	longhands, err := ExpandShorthand("font", `italic bold 16px "Times New Roman", "serif", monospace`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"font-style":   "italic",
+		"font-variant": "normal",
+		"font-weight":  "700",
+		"font-stretch": "100%",
+		"font-size":    "16px",
+		"line-height":  "normal",
+		"font-family":  `Times New Roman, "serif", monospace`,
+	}, longhands)
+
+	longhands, err = ExpandShorthand("font", "caption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "system-ui", longhands["font-family"])
+}
+
+func TestFontWeightResolve(t *testing.T) {
+	assert.Equal(t, 700.0, FontWeight{Keyword: "bolder"}.Resolve(400))
+	assert.Equal(t, 900.0, FontWeight{Keyword: "bolder"}.Resolve(700))
+	assert.Equal(t, 100.0, FontWeight{Keyword: "lighter"}.Resolve(400))
+	assert.Equal(t, 700.0, FontWeight{Keyword: "lighter"}.Resolve(900))
+	assert.Equal(t, 300.0, FontWeight{Weight: 300}.Resolve(900))
+}
@@ -0,0 +1,795 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Grammar is a compiled value definition, written in the CSS Value
+// Definition Syntax, e.g. "<line-width> || <line-style> || <color>".
+//
+// Besides keywords and the "," and "/" literals, a definition can refer to
+// basic data types like <length> or <color>, to named productions like
+// <line-style> and to the syntax of other properties like <'margin-top'>.
+// Numeric types accept a range, as in <number [0,∞]>. Components are
+// combined by juxtaposition, "&&" (all, in any order), "||" (one or more,
+// in any order) and "|" (exactly one), grouped with brackets and repeated
+// with "?", "*", "+", "#" (comma separated), "{m}", "{m,}" and "{m,n}".
+// A "!" after a group requires it to match at least one value.
+type Grammar struct {
+	def  string
+	root *grammarNode
+}
+
+type grammarKind int
+
+const (
+	keywordNode grammarKind = iota
+	literalNode
+	typeNode
+	functionNode
+	blockNode
+	sequenceNode
+	allNode
+	anyNode
+	alternativeNode
+	repeatNode
+	requiredNode
+)
+
+type grammarNode struct {
+	kind     grammarKind
+	name     string
+	children []*grammarNode
+
+	// Range of numeric types.
+	hasRange bool
+	min, max float64
+
+	// Bounds of repeatNode, max is -1 if there is no upper bound.
+	minCount, maxCount int
+	comma              bool
+}
+
+// CompileGrammar parses a value definition.
+func CompileGrammar(def string) (*Grammar, error) {
+	root, err := parseGrammar(def)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReferences(root, productionGrammars, propertyGrammars); err != nil {
+		return nil, fmt.Errorf("grammar %q: %w", def, err)
+	}
+	return &Grammar{def: def, root: root}, nil
+}
+
+// MustCompileGrammar is like CompileGrammar but panics if the definition
+// is invalid.
+func MustCompileGrammar(def string) *Grammar {
+	g, err := CompileGrammar(def)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+func (g *Grammar) String() string {
+	return g.def
+}
+
+// Match parses value and matches it against the grammar. It returns the
+// component values, with the productions they matched filled in.
+func (g *Grammar) Match(value string) ([]ComponentValue, error) {
+	values, err := ParseValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if !g.MatchValues(values) {
+		return nil, fmt.Errorf("invalid value %q, expected %s", value, g.def)
+	}
+	return values, nil
+}
+
+// MatchValues reports whether values match the grammar, and fills in the
+// productions the values matched. Lists of more than maxMatchValues values
+// never match.
+func (g *Grammar) MatchValues(values []ComponentValue) bool {
+	return newMatcher(values).matches(g.root)
+}
+
+// Handler returns a StyleHandler that validates values against the grammar.
+// The style value is the matched []ComponentValue. CSS-wide keywords like
+// "inherit" are always accepted, their style value is a CSSWideKeyword.
+func (g *Grammar) Handler() StyleHandler {
+	return g.typedHandler(func(values []ComponentValue) interface{} {
+		return values
+	})
+}
+
+// typedHandler is like Handler, but the style value is the result of
+// convert, which is called with the matched values.
+func (g *Grammar) typedHandler(convert func([]ComponentValue) interface{}) StyleHandler {
+	return func(value string) (Style, error) {
+		values, err := ParseValue(value)
+		if err != nil {
+			return Style{}, err
+		}
+		if isCSSWideKeyword(values) {
+			return Style{Value: CSSWideKeyword(strings.ToLower(values[0].Text))}, nil
+		}
+		if !g.MatchValues(values) {
+			return Style{}, fmt.Errorf("invalid value %q, expected %s", value, g.def)
+		}
+		return Style{Value: convert(values), unit: valuesUnit(values)}, nil
+	}
+}
+
+// CSSWideKeyword is the style value of the keywords that every property
+// accepts: initial, inherit, unset, revert and revert-layer.
+type CSSWideKeyword string
+
+func isCSSWideKeyword(values []ComponentValue) bool {
+	if len(values) != 1 || values[0].Type != IdentValue {
+		return false
+	}
+	switch strings.ToLower(values[0].Text) {
+	case "initial", "inherit", "unset", "revert", "revert-layer":
+		return true
+	}
+	return false
+}
+
+// valuesUnit returns the unit of a value consisting of a single length,
+// percentage or "auto".
+func valuesUnit(values []ComponentValue) UnitType {
+	if len(values) != 1 {
+		return UnitNone
+	}
+	switch v := values[0]; {
+	case v.Is("auto"):
+		return UnitAuto
+	case v.Type == PercentageValue:
+		return UnitPercent
+	case v.Type == DimensionValue:
+		switch v.Unit {
+		case "px":
+			return UnitPixels
+		case "em":
+			return UnitEm
+		case "rem":
+			return UnitRem
+		case "pt":
+			return UnitPt
+		}
+	}
+	return UnitNone
+}
+
+// maxMatchValues bounds the length of the lists of component values that
+// are matched, since the ends of a match can take quadratic time and memory
+// to find.
+const maxMatchValues = 4096
+
+// matcher matches a list of component values against grammar nodes. It
+// memoizes the positions at which each node can end a match, in the order
+// backtracking would try them, so that matching takes polynomial time
+// even for long lists of values that don't match.
+type matcher struct {
+	values []ComponentValue
+	memo   map[matchState][]int
+	// seen marks the ends already in the list union is building with the
+	// current stamp.
+	seen  []int
+	stamp int
+}
+
+// matchState is a node, a position and the state of the node: the next
+// child of a sequence, the bits of the children of "&&" and "||" that
+// already matched, or the repetitions of a multiplier so far. Part is the
+// kind of the state, it is zero for the ends of the node itself.
+type matchState struct {
+	n     *grammarNode
+	pos   int
+	part  grammarKind
+	state uint64
+}
+
+func newMatcher(values []ComponentValue) *matcher {
+	return &matcher{values: values, memo: make(map[matchState][]int), seen: make([]int, len(values)+1)}
+}
+
+// matches reports whether n matches all the values, and fills in the
+// productions they matched.
+func (m *matcher) matches(n *grammarNode) bool {
+	if len(m.values) > maxMatchValues || !containsInt(m.ends(n, 0), len(m.values)) {
+		return false
+	}
+	m.fill(n, 0, len(m.values))
+	return true
+}
+
+// ends returns the positions at which a match of n starting at pos can end.
+func (m *matcher) ends(n *grammarNode, pos int) []int {
+	key := matchState{n: n, pos: pos}
+	if ends, ok := m.memo[key]; ok {
+		return ends
+	}
+	var ends []int
+	values := m.values
+	switch n.kind {
+	case keywordNode:
+		if pos < len(values) && values[pos].Is(n.name) {
+			ends = []int{pos + 1}
+		}
+	case literalNode:
+		if pos < len(values) && values[pos].IsDelim(n.name) {
+			ends = []int{pos + 1}
+		}
+	case typeNode:
+		if isPrimitive, ok := primitiveTypes[n.name]; ok {
+			if pos < len(values) && isPrimitive(values[pos]) && n.inRange(values[pos]) {
+				ends = []int{pos + 1}
+			}
+		} else if ref, _ := n.reference(); ref != nil {
+			ends = m.ends(ref.root, pos)
+		}
+	case functionNode, blockNode:
+		valueType := FunctionValue
+		if n.kind == blockNode {
+			valueType = BlockValue
+		}
+		if pos < len(values) && values[pos].Type == valueType && values[pos].Text == n.name && n.matchArgs(values[pos].Args) {
+			ends = []int{pos + 1}
+		}
+	case sequenceNode:
+		ends = m.sequenceEnds(n, 0, pos)
+	case allNode, anyNode:
+		ends = m.unorderedEnds(n, pos, 0)
+	case alternativeNode:
+		parts := make([][]int, len(n.children))
+		for i, c := range n.children {
+			parts[i] = m.ends(c, pos)
+		}
+		ends = m.union(parts)
+	case repeatNode:
+		ends = m.repeatEnds(n, pos, 0)
+	case requiredNode:
+		for _, end := range m.ends(n.children[0], pos) {
+			if end > pos {
+				ends = append(ends, end)
+			}
+		}
+	}
+	m.memo[key] = ends
+	return ends
+}
+
+// matchArgs reports whether the arguments of a function or block match
+// its grammar, and fills in the productions they matched.
+func (n *grammarNode) matchArgs(args []ComponentValue) bool {
+	for i := range args {
+		args[i].Production = ""
+	}
+	if len(n.children) == 0 {
+		return len(args) == 0
+	}
+	return newMatcher(args).matches(n.children[0])
+}
+
+// reference returns the grammar a type refers to, and the name of its
+// production.
+func (n *grammarNode) reference() (*Grammar, string) {
+	if strings.HasPrefix(n.name, "'") {
+		name := strings.Trim(n.name, "'")
+		return propertyGrammars[name], name
+	}
+	return productionGrammars[n.name], n.name
+}
+
+func (n *grammarNode) inRange(v ComponentValue) bool {
+	if !n.hasRange {
+		return true
+	}
+	switch v.Type {
+	case NumberValue, PercentageValue, DimensionValue:
+		return v.Number >= n.min && v.Number <= n.max
+	}
+	return true
+}
+
+// sequenceEnds returns the ends of a match of the children of n from the
+// i-th one on.
+func (m *matcher) sequenceEnds(n *grammarNode, i, pos int) []int {
+	if i == len(n.children) {
+		return []int{pos}
+	}
+	key := matchState{n: n, pos: pos, part: sequenceNode, state: uint64(i)}
+	if ends, ok := m.memo[key]; ok {
+		return ends
+	}
+	var parts [][]int
+	for _, end := range m.ends(n.children[i], pos) {
+		parts = append(parts, m.sequenceEnds(n, i+1, end))
+	}
+	ends := m.union(parts)
+	m.memo[key] = ends
+	return ends
+}
+
+// unorderedEnds returns the ends of a match of the children of "&&" and
+// "||" in any order. Used has a bit set for every child that already
+// matched.
+func (m *matcher) unorderedEnds(n *grammarNode, pos int, used uint64) []int {
+	key := matchState{n: n, pos: pos, part: allNode, state: used}
+	if ends, ok := m.memo[key]; ok {
+		return ends
+	}
+	var parts [][]int
+	for i, c := range n.children {
+		if used&(1<<i) != 0 {
+			continue
+		}
+		for _, end := range m.ends(c, pos) {
+			if end > pos {
+				parts = append(parts, m.unorderedEnds(n, end, used|1<<i))
+			}
+		}
+	}
+	if m.unorderedDone(n, pos, used) {
+		parts = append(parts, []int{pos})
+	}
+	ends := m.union(parts)
+	m.memo[key] = ends
+	return ends
+}
+
+// unorderedDone reports whether a match of "&&" or "||" can end at pos
+// once the used children matched.
+func (m *matcher) unorderedDone(n *grammarNode, pos int, used uint64) bool {
+	if n.kind == anyNode {
+		return used != 0
+	}
+	for i, c := range n.children { // optional children of "&&" may match nothing
+		if used&(1<<i) == 0 && !m.matchesEmpty(c, pos) {
+			return false
+		}
+	}
+	return true
+}
+
+// repeatEnds returns the ends of a match of a multiplier that already
+// matched count times.
+func (m *matcher) repeatEnds(n *grammarNode, pos, count int) []int {
+	if n.maxCount < 0 && count > n.minCount && count > 1 {
+		// further repetitions are unbounded and no longer depend on count
+		count = n.minCount
+		if count < 1 {
+			count = 1
+		}
+	}
+	key := matchState{n: n, pos: pos, part: repeatNode, state: uint64(count)}
+	if ends, ok := m.memo[key]; ok {
+		return ends
+	}
+	var parts [][]int
+	if start := m.repeatStart(n, pos, count); start >= 0 {
+		for _, end := range m.ends(n.children[0], start) {
+			if end > start {
+				parts = append(parts, m.repeatEnds(n, end, count+1))
+			}
+		}
+	}
+	if count >= n.minCount || m.matchesEmpty(n.children[0], pos) {
+		parts = append(parts, []int{pos})
+	}
+	ends := m.union(parts)
+	m.memo[key] = ends
+	return ends
+}
+
+// repeatStart returns the position at which the next repetition of a
+// multiplier starts, or -1 if it can't repeat.
+func (m *matcher) repeatStart(n *grammarNode, pos, count int) int {
+	if n.maxCount >= 0 && count >= n.maxCount {
+		return -1
+	}
+	if count > 0 && n.comma {
+		if pos < len(m.values) && m.values[pos].IsDelim(",") {
+			return pos + 1
+		}
+		return -1
+	}
+	return pos
+}
+
+func (m *matcher) matchesEmpty(n *grammarNode, pos int) bool {
+	return containsInt(m.ends(n, pos), pos)
+}
+
+// fill fills in the productions of the values from pos to end, which n
+// matches, following the first match backtracking would find.
+func (m *matcher) fill(n *grammarNode, pos, end int) {
+	switch n.kind {
+	case typeNode:
+		ref, name := n.reference()
+		if _, ok := primitiveTypes[n.name]; ok || ref == nil {
+			return
+		}
+		m.fill(ref.root, pos, end)
+		// outer productions take precedence over the inner ones
+		for i := pos; i < end; i++ {
+			m.values[i].Production = name
+		}
+	case sequenceNode:
+		for i, c := range n.children {
+			for _, e := range m.ends(c, pos) {
+				if containsInt(m.sequenceEnds(n, i+1, e), end) {
+					m.fill(c, pos, e)
+					pos = e
+					break
+				}
+			}
+		}
+	case allNode, anyNode:
+		m.fillUnordered(n, pos, 0, end)
+	case alternativeNode:
+		for _, c := range n.children {
+			if containsInt(m.ends(c, pos), end) {
+				m.fill(c, pos, end)
+				return
+			}
+		}
+	case repeatNode:
+		m.fillRepeat(n, pos, 0, end)
+	case requiredNode:
+		m.fill(n.children[0], pos, end)
+	}
+}
+
+func (m *matcher) fillUnordered(n *grammarNode, pos int, used uint64, end int) {
+	for i, c := range n.children {
+		if used&(1<<i) != 0 {
+			continue
+		}
+		for _, e := range m.ends(c, pos) {
+			if e > pos && containsInt(m.unorderedEnds(n, e, used|1<<i), end) {
+				m.fill(c, pos, e)
+				m.fillUnordered(n, e, used|1<<i, end)
+				return
+			}
+		}
+	}
+}
+
+func (m *matcher) fillRepeat(n *grammarNode, pos, count int, end int) {
+	start := m.repeatStart(n, pos, count)
+	if start < 0 {
+		return
+	}
+	for _, e := range m.ends(n.children[0], start) {
+		if e > start && containsInt(m.repeatEnds(n, e, count+1), end) {
+			m.fill(n.children[0], start, e)
+			m.fillRepeat(n, e, count+1, end)
+			return
+		}
+	}
+}
+
+// union returns the ends of parts in order, without duplicates.
+func (m *matcher) union(parts [][]int) []int {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	m.stamp++
+	var ends []int
+	for _, part := range parts {
+		for _, end := range part {
+			if m.seen[end] != m.stamp {
+				m.seen[end] = m.stamp
+				ends = append(ends, end)
+			}
+		}
+	}
+	return ends
+}
+
+func containsInt(list []int, n int) bool {
+	for _, item := range list {
+		if item == n {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReferences returns an error if n refers to unknown types.
+func checkReferences(n *grammarNode, productions, properties map[string]*Grammar) error {
+	if n.kind == typeNode {
+		_, primitive := primitiveTypes[n.name]
+		_, production := productions[n.name]
+		_, property := properties[strings.Trim(n.name, "'")]
+		if !primitive && !production && !(strings.HasPrefix(n.name, "'") && property) {
+			return fmt.Errorf("unknown type <%s>", n.name)
+		}
+	}
+	for _, c := range n.children {
+		if err := checkReferences(c, productions, properties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileGrammars compiles a table of definitions. References are checked
+// once all tables are compiled.
+func compileGrammars(defs map[string]string) map[string]*Grammar {
+	grammars := make(map[string]*Grammar, len(defs))
+	for name, def := range defs {
+		root, err := parseGrammar(def)
+		if err != nil {
+			panic(fmt.Sprintf("css: syntax of %s: %v", name, err))
+		}
+		grammars[name] = &Grammar{def: def, root: root}
+	}
+	return grammars
+}
+
+// grammarParser is a recursive descent parser for value definitions.
+type grammarParser struct {
+	s   string
+	pos int
+}
+
+func parseGrammar(def string) (*grammarNode, error) {
+	p := &grammarParser{s: def}
+	n, err := p.alternatives()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos:], p.pos)
+	}
+	return n, nil
+}
+
+func (p *grammarParser) skipSpace() {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume skips over the operator op if it is next.
+func (p *grammarParser) consume(op string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.s[p.pos:], op) {
+		return false
+	}
+	if op == "|" && strings.HasPrefix(p.s[p.pos:], "||") {
+		return false
+	}
+	p.pos += len(op)
+	return true
+}
+
+func (p *grammarParser) alternatives() (*grammarNode, error) {
+	return p.combination(alternativeNode, "|", p.anyOf)
+}
+
+func (p *grammarParser) anyOf() (*grammarNode, error) {
+	return p.combination(anyNode, "||", p.allOf)
+}
+
+func (p *grammarParser) allOf() (*grammarNode, error) {
+	return p.combination(allNode, "&&", p.sequence)
+}
+
+func (p *grammarParser) combination(kind grammarKind, op string, operand func() (*grammarNode, error)) (*grammarNode, error) {
+	n, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*grammarNode{n}
+	for p.consume(op) {
+		if n, err = operand(); err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	if len(children) > 64 {
+		return nil, fmt.Errorf("too many components for %q", op)
+	}
+	return &grammarNode{kind: kind, children: children}, nil
+}
+
+func (p *grammarParser) sequence() (*grammarNode, error) {
+	var children []*grammarNode
+	for {
+		p.skipSpace()
+		if p.pos == len(p.s) || strings.IndexByte("|&])", p.s[p.pos]) >= 0 || strings.HasPrefix(p.s[p.pos:], "']'") {
+			break
+		}
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+
+	switch len(children) {
+	case 0:
+		return nil, fmt.Errorf("missing component at offset %d", p.pos)
+	case 1:
+		return children[0], nil
+	}
+	return &grammarNode{kind: sequenceNode, children: children}, nil
+}
+
+func (p *grammarParser) term() (*grammarNode, error) {
+	n, err := p.component()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.s) {
+		repeat := &grammarNode{kind: repeatNode, children: []*grammarNode{n}}
+		switch p.s[p.pos] {
+		case '?':
+			repeat.minCount, repeat.maxCount = 0, 1
+		case '*':
+			repeat.minCount, repeat.maxCount = 0, -1
+		case '+':
+			repeat.minCount, repeat.maxCount = 1, -1
+		case '#':
+			repeat.minCount, repeat.maxCount, repeat.comma = 1, -1, true
+			if p.pos+1 < len(p.s) && p.s[p.pos+1] == '{' {
+				p.pos++
+				if err := p.bounds(repeat); err != nil {
+					return nil, err
+				}
+				n = repeat
+				continue
+			}
+		case '{':
+			if err := p.bounds(repeat); err != nil {
+				return nil, err
+			}
+			n = repeat
+			continue
+		case '!':
+			repeat.kind = requiredNode
+		default:
+			return n, nil
+		}
+		p.pos++
+		n = repeat
+	}
+	return n, nil
+}
+
+// bounds parses "{m}", "{m,}" or "{m,n}".
+func (p *grammarParser) bounds(n *grammarNode) error {
+	end := strings.IndexByte(p.s[p.pos:], '}')
+	if end < 0 {
+		return fmt.Errorf("missing '}' at offset %d", p.pos)
+	}
+	spec := p.s[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	min, max, hasMax := spec, spec, true
+	if i := strings.IndexByte(spec, ','); i >= 0 {
+		min, max = spec[:i], strings.TrimSpace(spec[i+1:])
+		hasMax = max != ""
+	}
+	var err error
+	if n.minCount, err = strconv.Atoi(strings.TrimSpace(min)); err != nil {
+		return fmt.Errorf("invalid multiplier {%s}", spec)
+	}
+	n.maxCount = -1
+	if hasMax {
+		if n.maxCount, err = strconv.Atoi(max); err != nil || n.maxCount < n.minCount {
+			return fmt.Errorf("invalid multiplier {%s}", spec)
+		}
+	}
+	return nil
+}
+
+func (p *grammarParser) component() (*grammarNode, error) {
+	p.skipSpace()
+	switch c := p.s[p.pos]; {
+	case c == '[':
+		p.pos++
+		n, err := p.alternatives()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, fmt.Errorf("missing ']' at offset %d", p.pos)
+		}
+		return n, nil
+	case c == '<':
+		return p.dataType()
+	case c == ',' || c == '/':
+		p.pos++
+		return &grammarNode{kind: literalNode, name: string(c)}, nil
+	case strings.HasPrefix(p.s[p.pos:], "'['"):
+		p.pos += 3
+		return p.block(&grammarNode{kind: blockNode, name: "["}, "']'")
+	case c == '\'' && p.pos+2 < len(p.s) && p.s[p.pos+2] == '\'':
+		p.pos += 3
+		return &grammarNode{kind: literalNode, name: p.s[p.pos-2 : p.pos-1]}, nil
+	case isNameByte(c):
+		start := p.pos
+		for p.pos < len(p.s) && isNameByte(p.s[p.pos]) {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		if p.pos < len(p.s) && p.s[p.pos] == '(' {
+			// function names of values are lowercased, like translateX(
+			p.pos++
+			return p.block(&grammarNode{kind: functionNode, name: strings.ToLower(name)}, ")")
+		}
+		return &grammarNode{kind: keywordNode, name: name}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos], p.pos)
+}
+
+// block parses the content of a function or block up to end.
+func (p *grammarParser) block(n *grammarNode, end string) (*grammarNode, error) {
+	if !p.consume(end) {
+		inner, err := p.alternatives()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(end) {
+			return nil, fmt.Errorf("missing %s at offset %d", end, p.pos)
+		}
+		n.children = []*grammarNode{inner}
+	}
+	return n, nil
+}
+
+// dataType parses "<name>", "<'property'>" or "<name [min,max]>".
+func (p *grammarParser) dataType() (*grammarNode, error) {
+	end := strings.IndexByte(p.s[p.pos:], '>')
+	if end < 0 {
+		return nil, fmt.Errorf("missing '>' at offset %d", p.pos)
+	}
+	spec := strings.TrimSpace(p.s[p.pos+1 : p.pos+end])
+	p.pos += end + 1
+
+	n := &grammarNode{kind: typeNode, name: spec}
+	if i := strings.IndexByte(spec, '['); i >= 0 {
+		n.name = strings.TrimSpace(spec[:i])
+		bounds := strings.Split(strings.Trim(spec[i:], "[]"), ",")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid range in <%s>", spec)
+		}
+		var err error
+		if n.min, err = parseBound(bounds[0]); err != nil {
+			return nil, fmt.Errorf("invalid range in <%s>", spec)
+		}
+		if n.max, err = parseBound(bounds[1]); err != nil {
+			return nil, fmt.Errorf("invalid range in <%s>", spec)
+		}
+		n.hasRange = true
+	}
+	if n.name == "" {
+		return nil, fmt.Errorf("empty type at offset %d", p.pos)
+	}
+	return n, nil
+}
+
+func parseBound(s string) (float64, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "∞", "+∞", "inf":
+		return math.Inf(1), nil
+	case "-∞", "-inf":
+		return math.Inf(-1), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
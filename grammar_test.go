@@ -0,0 +1,147 @@
+package css
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrammarMatch(t *testing.T) {
+	cases := []struct {
+		grammar string
+		valid   []string
+		invalid []string
+	}{
+		{"<length> | auto", []string{"1px", "0", "auto", "AUTO", "calc(1px + 2em)"}, []string{"1", "none", "1px auto", ""}},
+		{"a b c", []string{"a b c"}, []string{"a c b", "a b"}},
+		{"a && b && c?", []string{"a b", "b a", "c b a", "a c b"}, []string{"a", "a b b", "c"}},
+		{"a || b || c", []string{"a", "c b", "b c a"}, []string{"a a", "d", ""}},
+		{"[ a | b ]? c", []string{"c", "a c", "b c"}, []string{"a", "a b c"}},
+		{"a* b", []string{"b", "a b", "a a a b"}, []string{"a"}},
+		{"a+", []string{"a", "a a"}, []string{""}},
+		{"a#", []string{"a", "a, a, a"}, []string{"a a", "a,", ", a"}},
+		{"a{2,3}", []string{"a a", "a a a"}, []string{"a", "a a a a"}},
+		{"a{2}", []string{"a a"}, []string{"a", "a a a"}},
+		{"a{2,}", []string{"a a", "a a a a"}, []string{"a"}},
+		{"<number>#{3}", []string{"1, 2, 3"}, []string{"1, 2", "1 2 3"}},
+		{"[ a? b? ]!", []string{"a", "b", "a b"}, []string{""}},
+		{"<length> [ / <length> ]?", []string{"1px", "1px / 2px"}, []string{"1px /"}},
+		{"<integer [1,∞]>", []string{"1", "20"}, []string{"0", "-1", "1.5"}},
+		{"<number [0,1]>", []string{"0", ".5", "1"}, []string{"1.1", "-0.1"}},
+		{"rect( <length>#{4} )", []string{"rect(1px, 2px, 3px, 4px)"}, []string{"rect(1px 2px 3px 4px)", "rect()", "rect"}},
+		{"'[' <custom-ident>* ']' <length>", []string{"[a b] 1px", "[] 1px"}, []string{"a 1px", "(a) 1px"}},
+		{"<color>", []string{"red", "#fff", "#ffffff80", "rgb(1 2 3 / 50%)", "rgba(1, 2, 3, .5)", "hsl(120deg 50% 50%)", "currentColor"}, []string{"#ff", "bla", "rgb(1 2)"}},
+		{"<'margin-top'>{1,4}", []string{"auto", "1px 2% auto"}, []string{"1px 2px 3px 4px 5px"}},
+		{"<image>", []string{"url(a.png)", "linear-gradient(to right, red, blue 50%)", "radial-gradient(circle at center, red, blue)"}, []string{"none", "linear-gradient()"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.grammar, func(t *testing.T) {
+			g, err := CompileGrammar(tt.grammar)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, value := range tt.valid {
+				_, err := g.Match(value)
+				assert.NoError(t, err, value)
+			}
+			for _, value := range tt.invalid {
+				_, err := g.Match(value)
+				assert.Error(t, err, value)
+			}
+		})
+	}
+}
+
+func TestGrammarProductions(t *testing.T) {
+	g := MustCompileGrammar("<line-width> || <line-style> || <color>")
+	values, err := g.Match("solid red thin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var productions []string
+	for _, v := range values {
+		productions = append(productions, v.Production)
+	}
+	assert.Equal(t, []string{"line-style", "color", "line-width"}, productions)
+
+	g = MustCompileGrammar("<'font-style'>? <'font-size'> <'font-family'>")
+	values, err = g.Match("italic 12px Times New Roman, serif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	productions = productions[:0]
+	for _, v := range values {
+		productions = append(productions, v.Production)
+	}
+	assert.Equal(t, []string{"font-style", "font-size", "font-family", "font-family", "font-family", "font-family", "font-family"}, productions)
+}
+
+func TestCompileGrammarError(t *testing.T) {
+	for _, def := range []string{"", "a |", "[ a", "<length", "<unknown>", "a{2,1}", "a{x}", "f( a", "<number [1]>", "a && && b"} {
+		_, err := CompileGrammar(def)
+		assert.Error(t, err, def)
+	}
+}
+
+func TestSyntaxTables(t *testing.T) {
+	for name, g := range productionGrammars {
+		assert.NoError(t, checkReferences(g.root, productionGrammars, propertyGrammars), name)
+	}
+	for name, g := range propertyGrammars {
+		assert.NoError(t, checkReferences(g.root, productionGrammars, propertyGrammars), name)
+		_, ok := DefaultRegistry.Metadata(name)
+		assert.True(t, ok, "missing metadata for %s", name)
+	}
+}
+
+func TestGrammarHandler(t *testing.T) {
+	handler := MustCompileGrammar("<length> | auto").Handler()
+	style, err := handler("12px")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitPixels, style.Unit())
+	assert.Equal(t, "[12px]", style.String())
+
+	style, err = handler("inherit")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitNone, style.Unit())
+
+	_, err = handler("12")
+	assert.EqualError(t, err, `invalid value "12", expected <length> | auto`)
+}
+
+func TestGrammarMatchInvalidLists(t *testing.T) {
+	// without memoization, backtracking takes exponential time on these
+	cases := map[string]string{
+		"background": strings.Repeat("left top padding-box, ", 40) + "red red",
+		"transition": strings.Repeat("1s, ", 40) + "width none",
+	}
+	for name, value := range cases {
+		start := time.Now()
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+		assert.Less(t, time.Since(start), time.Second, name)
+	}
+}
+
+func TestGrammarMatchLongLists(t *testing.T) {
+	// the ends of unbounded repetitions grow with the length of the list
+	cases := []struct {
+		name, value string
+		valid       bool
+	}{
+		{"font-family", strings.Repeat("a ", 2000) + "b", true},
+		{"font-family", strings.Repeat("a, ", 1000) + "b", true},
+		{"font", "12px " + strings.Repeat("a ", 2000) + "b", true},
+		{"font-family", strings.Repeat("a ", 8000) + "b", false},
+		{"font", "12px " + strings.Repeat("a ", 8000) + "b", false},
+	}
+	for _, tt := range cases {
+		start := time.Now()
+		_, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+		assert.Equal(t, tt.valid, err == nil, "%s of %d bytes", tt.name, len(tt.value))
+		assert.Less(t, time.Since(start), time.Second, "%s of %d bytes", tt.name, len(tt.value))
+	}
+}
@@ -0,0 +1,625 @@
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GridTrackSize is a <track-size>: the min and max track sizing functions
+// as specified. A single breadth like 1fr or auto sets both, and
+// fit-content() sets FitContent with its limit in Max and auto in Min.
+// Breadths are lengths, percentages, flexible lengths with the "fr" unit
+// or the keywords min-content, max-content and auto.
+type GridTrackSize struct {
+	Min, Max   Length
+	FitContent bool
+}
+
+// autoTrack is the initial value of grid-auto-rows and grid-auto-columns.
+var autoTrack = GridTrackSize{Min: Length{Keyword: "auto"}, Max: Length{Keyword: "auto"}}
+
+func (s GridTrackSize) String() string {
+	switch {
+	case s.FitContent:
+		return "fit-content(" + s.Max.String() + ")"
+	case s.Min == s.Max:
+		return s.Min.String()
+	}
+	return "minmax(" + s.Min.String() + ", " + s.Max.String() + ")"
+}
+
+// GridTrack is a track or a repeat() of a track list, with the names of
+// the line before it.
+type GridTrack struct {
+	Names []string
+	Size  GridTrackSize
+	// Repeat is set instead of Size for repeat().
+	Repeat *GridRepeat
+}
+
+func (t GridTrack) String() string {
+	s := t.Size.String()
+	if t.Repeat != nil {
+		s = t.Repeat.String()
+	}
+	if len(t.Names) > 0 {
+		return lineNamesString(t.Names) + " " + s
+	}
+	return s
+}
+
+// GridRepeat is a repeat() of a track list.
+type GridRepeat struct {
+	// Count is the number of repetitions, zero for automatic repetitions.
+	Count int
+	// Auto is "auto-fill" or "auto-fit" for automatic repetitions.
+	Auto     string
+	Tracks   []GridTrack
+	EndNames []string
+}
+
+func (r GridRepeat) String() string {
+	count := r.Auto
+	if count == "" {
+		count = strconv.Itoa(r.Count)
+	}
+	return "repeat(" + count + ", " + tracksString(r.Tracks, r.EndNames) + ")"
+}
+
+// GridTrackList is the typed value of grid-template-rows and
+// grid-template-columns.
+type GridTrackList struct {
+	None bool
+	// Subgrid is set for subgrid, with the names of its lines in
+	// SubgridNames. Automatic repetitions of names are expanded once.
+	Subgrid      bool
+	SubgridNames [][]string
+	Tracks       []GridTrack
+	// EndNames are the names of the line after the last track.
+	EndNames []string
+}
+
+func (l GridTrackList) String() string {
+	switch {
+	case l.None:
+		return "none"
+	case l.Subgrid:
+		parts := []string{"subgrid"}
+		for _, names := range l.SubgridNames {
+			parts = append(parts, lineNamesString(names))
+		}
+		return strings.Join(parts, " ")
+	}
+	return tracksString(l.Tracks, l.EndNames)
+}
+
+func tracksString(tracks []GridTrack, endNames []string) string {
+	parts := make([]string, 0, len(tracks)+1)
+	for _, t := range tracks {
+		parts = append(parts, t.String())
+	}
+	if len(endNames) > 0 {
+		parts = append(parts, lineNamesString(endNames))
+	}
+	return strings.Join(parts, " ")
+}
+
+func lineNamesString(names []string) string {
+	return "[" + strings.Join(names, " ") + "]"
+}
+
+func gridTrackSizeOf(v ComponentValue) GridTrackSize {
+	switch {
+	case v.Type == FunctionValue && strings.EqualFold(v.Text, "minmax"):
+		return GridTrackSize{Min: lengthOf(v.Args[0]), Max: lengthOf(v.Args[2])}
+	case v.Type == FunctionValue && strings.EqualFold(v.Text, "fit-content"):
+		return GridTrackSize{Min: Length{Keyword: "auto"}, Max: lengthOf(v.Args[0]), FitContent: true}
+	}
+	return GridTrackSize{Min: lengthOf(v), Max: lengthOf(v)}
+}
+
+func gridTrackSizes(values []ComponentValue) interface{} {
+	sizes := make([]GridTrackSize, len(values))
+	for i, v := range values {
+		sizes[i] = gridTrackSizeOf(v)
+	}
+	return sizes
+}
+
+func lineNamesOf(v ComponentValue) []string {
+	names := make([]string, 0, len(v.Args))
+	for _, arg := range v.Args {
+		names = append(names, arg.Text)
+	}
+	return names
+}
+
+// gridTracks converts a track list with line names and repeat() functions.
+func gridTracks(values []ComponentValue) ([]GridTrack, []string) {
+	var tracks []GridTrack
+	var names []string
+	for _, v := range values {
+		switch {
+		case v.Type == BlockValue:
+			names = append(names, lineNamesOf(v)...)
+		case v.Type == FunctionValue && strings.EqualFold(v.Text, "repeat"):
+			r := &GridRepeat{}
+			if v.Args[0].Type == IdentValue {
+				r.Auto = strings.ToLower(v.Args[0].Text)
+			} else {
+				r.Count = int(v.Args[0].Number)
+			}
+			r.Tracks, r.EndNames = gridTracks(v.Args[2:])
+			tracks = append(tracks, GridTrack{Names: names, Repeat: r})
+			names = nil
+		default:
+			tracks = append(tracks, GridTrack{Names: names, Size: gridTrackSizeOf(v)})
+			names = nil
+		}
+	}
+	return tracks, names
+}
+
+func gridTrackList(values []ComponentValue) interface{} {
+	switch {
+	case values[0].Is("none"):
+		return GridTrackList{None: true}
+	case values[0].Is("subgrid"):
+		l := GridTrackList{Subgrid: true}
+		for _, v := range values[1:] {
+			if v.Type == BlockValue {
+				l.SubgridNames = append(l.SubgridNames, lineNamesOf(v))
+				continue
+			}
+			count := 1
+			if v.Args[0].Type == NumberValue {
+				count = int(v.Args[0].Number)
+			}
+			for i := 0; i < count; i++ {
+				for _, names := range v.Args[2:] {
+					l.SubgridNames = append(l.SubgridNames, lineNamesOf(names))
+				}
+			}
+		}
+		return l
+	}
+	var l GridTrackList
+	l.Tracks, l.EndNames = gridTracks(values)
+	return l
+}
+
+// GridRegion is a rectangle of grid cells between grid lines, numbered
+// from 1. The end lines are exclusive.
+type GridRegion struct {
+	RowStart, RowEnd       int
+	ColumnStart, ColumnEnd int
+}
+
+// GridTemplateAreas is the typed value of grid-template-areas: the cell
+// names of each row, with "." for cells that belong to no area. none has
+// no rows.
+type GridTemplateAreas struct {
+	Rows [][]string
+}
+
+func (a GridTemplateAreas) String() string {
+	if len(a.Rows) == 0 {
+		return "none"
+	}
+	rows := make([]string, len(a.Rows))
+	for i, row := range a.Rows {
+		rows[i] = strconv.Quote(strings.Join(row, " "))
+	}
+	return strings.Join(rows, " ")
+}
+
+// Areas returns the named areas by name.
+func (a GridTemplateAreas) Areas() map[string]GridRegion {
+	areas := make(map[string]GridRegion)
+	for i, row := range a.Rows {
+		for j, name := range row {
+			if name == "." {
+				continue
+			}
+			r, ok := areas[name]
+			if !ok {
+				r = GridRegion{RowStart: i + 1, RowEnd: i + 2, ColumnStart: j + 1, ColumnEnd: j + 2}
+			}
+			if i+2 > r.RowEnd {
+				r.RowEnd = i + 2
+			}
+			if j+1 < r.ColumnStart {
+				r.ColumnStart = j + 1
+			}
+			if j+2 > r.ColumnEnd {
+				r.ColumnEnd = j + 2
+			}
+			areas[name] = r
+		}
+	}
+	return areas
+}
+
+// parseGridAreaRow splits a string of grid-template-areas into cell
+// tokens. Sequences of "." are a single null cell token.
+func parseGridAreaRow(s string) ([]string, error) {
+	var cells []string
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '.':
+			for i < len(runes) && runes[i] == '.' {
+				i++
+			}
+			cells = append(cells, ".")
+			continue
+		case isNameRune(c):
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			cells = append(cells, string(runes[start:i]))
+			continue
+		}
+		return nil, fmt.Errorf("invalid character %q in grid area %q", c, s)
+	}
+	return cells, nil
+}
+
+func isNameRune(c rune) bool {
+	return c == '-' || c == '_' || c >= 0x80 || c < 0x80 && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
+
+// gridAreasOf returns the areas of the strings among values.
+func gridAreasOf(values []ComponentValue) (GridTemplateAreas, error) {
+	var a GridTemplateAreas
+	for _, v := range values {
+		if v.Type != StringValue {
+			continue
+		}
+		row, err := parseGridAreaRow(v.Text)
+		if err != nil {
+			return a, err
+		}
+		if len(row) == 0 {
+			return a, fmt.Errorf("empty row in grid areas")
+		}
+		if len(a.Rows) > 0 && len(row) != len(a.Rows[0]) {
+			return a, fmt.Errorf("grid area rows have %d and %d columns", len(a.Rows[0]), len(row))
+		}
+		a.Rows = append(a.Rows, row)
+	}
+	return a, nil
+}
+
+// validateGridAreas checks that the rows of grid areas have the same
+// number of columns and that every named area is a rectangle.
+func validateGridAreas(values []ComponentValue) error {
+	a, err := gridAreasOf(values)
+	if err != nil {
+		return err
+	}
+	for name, r := range a.Areas() {
+		for i := r.RowStart; i < r.RowEnd; i++ {
+			for j := r.ColumnStart; j < r.ColumnEnd; j++ {
+				if a.Rows[i-1][j-1] != name {
+					return fmt.Errorf("grid area %q is not a rectangle", name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func gridTemplateAreas(values []ComponentValue) interface{} {
+	a, _ := gridAreasOf(values)
+	return a
+}
+
+// GridTemplate is the typed value of the grid-template shorthand.
+type GridTemplate struct {
+	Rows, Columns GridTrackList
+	Areas         GridTemplateAreas
+}
+
+// splitSlash splits values at the first "/" delimiter. after is nil if
+// there is none.
+func splitSlash(values []ComponentValue) (before, after []ComponentValue) {
+	for i, v := range values {
+		if v.IsDelim("/") {
+			return values[:i], values[i+1:]
+		}
+	}
+	return values, nil
+}
+
+func gridTemplate(values []ComponentValue) interface{} {
+	none := GridTrackList{None: true}
+	if values[0].Is("none") {
+		return GridTemplate{Rows: none, Columns: none}
+	}
+	rows, columns := splitSlash(values)
+	areas, _ := gridAreasOf(rows)
+	if len(areas.Rows) == 0 {
+		return GridTemplate{Rows: gridTrackList(rows).(GridTrackList), Columns: gridTrackList(columns).(GridTrackList)}
+	}
+
+	t := GridTemplate{Columns: none, Areas: areas}
+	if columns != nil {
+		t.Columns = gridTrackList(columns).(GridTrackList)
+	}
+	// every string is a row, sized auto unless a track size follows it
+	var names []string
+	for _, v := range rows {
+		switch {
+		case v.Type == BlockValue:
+			names = append(names, lineNamesOf(v)...)
+		case v.Type == StringValue:
+			t.Rows.Tracks = append(t.Rows.Tracks, GridTrack{Names: names, Size: autoTrack})
+			names = nil
+		default:
+			t.Rows.Tracks[len(t.Rows.Tracks)-1].Size = gridTrackSizeOf(v)
+		}
+	}
+	t.Rows.EndNames = names
+	return t
+}
+
+func expandGridTemplate(values []ComponentValue) map[string]string {
+	t := gridTemplate(values).(GridTemplate)
+	return map[string]string{
+		"grid-template-rows":    t.Rows.String(),
+		"grid-template-columns": t.Columns.String(),
+		"grid-template-areas":   t.Areas.String(),
+	}
+}
+
+// GridAutoFlow is the typed value of grid-auto-flow.
+type GridAutoFlow struct {
+	Column, Dense bool
+}
+
+func (f GridAutoFlow) String() string {
+	s := "row"
+	if f.Column {
+		s = "column"
+	}
+	if f.Dense {
+		s += " dense"
+	}
+	return s
+}
+
+func gridAutoFlow(values []ComponentValue) interface{} {
+	var f GridAutoFlow
+	for _, v := range values {
+		switch {
+		case v.Is("column"):
+			f.Column = true
+		case v.Is("dense"):
+			f.Dense = true
+		}
+	}
+	return f
+}
+
+// Grid is the typed value of the grid shorthand.
+type Grid struct {
+	Template              GridTemplate
+	AutoFlow              GridAutoFlow
+	AutoRows, AutoColumns []GridTrackSize
+}
+
+func grid(values []ComponentValue) interface{} {
+	g := Grid{AutoRows: []GridTrackSize{autoTrack}, AutoColumns: []GridTrackSize{autoTrack}}
+	before, after := splitSlash(values)
+	auto, rest := autoFlowOf(before)
+	if auto != nil {
+		g.AutoFlow = *auto
+		if len(rest) > 0 {
+			g.AutoRows = gridTrackSizes(rest).([]GridTrackSize)
+		}
+		g.Template = GridTemplate{Rows: GridTrackList{None: true}, Columns: gridTrackList(after).(GridTrackList)}
+		return g
+	}
+	if auto, rest = autoFlowOf(after); auto != nil {
+		g.AutoFlow = *auto
+		g.AutoFlow.Column = true
+		if len(rest) > 0 {
+			g.AutoColumns = gridTrackSizes(rest).([]GridTrackSize)
+		}
+		g.Template = GridTemplate{Rows: gridTrackList(before).(GridTrackList), Columns: GridTrackList{None: true}}
+		return g
+	}
+	g.Template = gridTemplate(values).(GridTemplate)
+	return g
+}
+
+// autoFlowOf returns the auto-flow of values that start with auto-flow or
+// dense, and the values after it.
+func autoFlowOf(values []ComponentValue) (*GridAutoFlow, []ComponentValue) {
+	var f *GridAutoFlow
+	for len(values) > 0 && (values[0].Is("auto-flow") || values[0].Is("dense")) {
+		if f == nil {
+			f = &GridAutoFlow{}
+		}
+		f.Dense = f.Dense || values[0].Is("dense")
+		values = values[1:]
+	}
+	return f, values
+}
+
+func expandGrid(values []ComponentValue) map[string]string {
+	g := grid(values).(Grid)
+	return map[string]string{
+		"grid-template-rows":    g.Template.Rows.String(),
+		"grid-template-columns": g.Template.Columns.String(),
+		"grid-template-areas":   g.Template.Areas.String(),
+		"grid-auto-flow":        g.AutoFlow.String(),
+		"grid-auto-rows":        gridTrackSizesString(g.AutoRows),
+		"grid-auto-columns":     gridTrackSizesString(g.AutoColumns),
+	}
+}
+
+func gridTrackSizesString(sizes []GridTrackSize) string {
+	parts := make([]string, len(sizes))
+	for i, s := range sizes {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// GridLine is the typed value of grid-row-start, grid-row-end,
+// grid-column-start and grid-column-end. The zero value is auto.
+type GridLine struct {
+	// Span is set for spans like "span 2".
+	Span bool
+	// Integer is the line number or span, zero if only a name is given.
+	Integer int
+	// Name is the line or area name.
+	Name string
+}
+
+// IsAuto reports whether l is auto.
+func (l GridLine) IsAuto() bool {
+	return !l.Span && l.Integer == 0 && l.Name == ""
+}
+
+func (l GridLine) String() string {
+	var parts []string
+	if l.Span {
+		parts = append(parts, "span")
+	}
+	if l.Integer != 0 {
+		parts = append(parts, strconv.Itoa(l.Integer))
+	}
+	if l.Name != "" {
+		parts = append(parts, l.Name)
+	}
+	if len(parts) == 0 {
+		return "auto"
+	}
+	return strings.Join(parts, " ")
+}
+
+func gridLine(values []ComponentValue) interface{} {
+	var l GridLine
+	for _, v := range values {
+		switch {
+		case v.Is("auto"):
+		case v.Is("span") && !l.Span:
+			l.Span = true
+		case v.Type == IdentValue:
+			l.Name = v.Text
+		default:
+			l.Integer = integerValue([]ComponentValue{v}).(int)
+		}
+	}
+	return l
+}
+
+// validateGridLines checks that the lines of a placement property don't
+// use span or auto as line names, which the syntax accepts as
+// <custom-ident>.
+func validateGridLines(values []ComponentValue) error {
+	for _, l := range gridLines(values) {
+		switch {
+		case strings.EqualFold(l.Name, "span"), strings.EqualFold(l.Name, "auto"):
+			return fmt.Errorf("invalid grid line name %q", l.Name)
+		case l.Span && l.Integer == 0 && l.Name == "":
+			return fmt.Errorf("span without a count or name")
+		}
+	}
+	return nil
+}
+
+// GridPlacement is the typed value of the grid-row and grid-column
+// shorthands. An omitted end line is the start line if that is a name,
+// and auto otherwise.
+type GridPlacement struct {
+	Start, End GridLine
+}
+
+func (p GridPlacement) String() string {
+	return p.Start.String() + " / " + p.End.String()
+}
+
+// defaultEnd returns the line to use for an omitted end line.
+func defaultEnd(start GridLine) GridLine {
+	if !start.Span && start.Integer == 0 {
+		return start
+	}
+	return GridLine{}
+}
+
+// gridLines converts the "/" separated lines of a placement shorthand.
+func gridLines(values []ComponentValue) []GridLine {
+	var lines []GridLine
+	start := 0
+	for i := 0; i <= len(values); i++ {
+		if i == len(values) || values[i].IsDelim("/") {
+			lines = append(lines, gridLine(values[start:i]).(GridLine))
+			start = i + 1
+		}
+	}
+	return lines
+}
+
+func gridPlacement(values []ComponentValue) interface{} {
+	lines := gridLines(values)
+	p := GridPlacement{Start: lines[0], End: defaultEnd(lines[0])}
+	if len(lines) == 2 {
+		p.End = lines[1]
+	}
+	return p
+}
+
+func expandGridPlacement(name string) func(values []ComponentValue) map[string]string {
+	return func(values []ComponentValue) map[string]string {
+		p := gridPlacement(values).(GridPlacement)
+		return map[string]string{name + "-start": p.Start.String(), name + "-end": p.End.String()}
+	}
+}
+
+// GridArea is the typed value of the grid-area shorthand.
+type GridArea struct {
+	Row, Column GridPlacement
+}
+
+func (a GridArea) String() string {
+	return strings.Join([]string{a.Row.Start.String(), a.Column.Start.String(), a.Row.End.String(), a.Column.End.String()}, " / ")
+}
+
+func gridArea(values []ComponentValue) interface{} {
+	lines := gridLines(values)
+	a := GridArea{Row: GridPlacement{Start: lines[0]}}
+	a.Column.Start = defaultEnd(a.Row.Start)
+	if len(lines) > 1 {
+		a.Column.Start = lines[1]
+	}
+	a.Row.End = defaultEnd(a.Row.Start)
+	if len(lines) > 2 {
+		a.Row.End = lines[2]
+	}
+	a.Column.End = defaultEnd(a.Column.Start)
+	if len(lines) > 3 {
+		a.Column.End = lines[3]
+	}
+	return a
+}
+
+func expandGridArea(values []ComponentValue) map[string]string {
+	a := gridArea(values).(GridArea)
+	return map[string]string{
+		"grid-row-start":    a.Row.Start.String(),
+		"grid-column-start": a.Column.Start.String(),
+		"grid-row-end":      a.Row.End.String(),
+		"grid-column-end":   a.Column.End.String(),
+	}
+}
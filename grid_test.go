@@ -0,0 +1,137 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridProperties(t *testing.T) {
+	px := func(n float64) Length { return Length{Value: n, Unit: "px"} }
+	fr := func(n float64) Length { return Length{Value: n, Unit: "fr"} }
+	fixed := func(l Length) GridTrackSize { return GridTrackSize{Min: l, Max: l} }
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"grid-template-columns", "none", GridTrackList{None: true}},
+		{"grid-template-columns", "[a] 100px repeat(2, [b] 1fr) [c d]", GridTrackList{
+			Tracks: []GridTrack{
+				{Names: []string{"a"}, Size: fixed(px(100))},
+				{Repeat: &GridRepeat{Count: 2, Tracks: []GridTrack{{Names: []string{"b"}, Size: fixed(fr(1))}}}},
+			},
+			EndNames: []string{"c", "d"},
+		}},
+		{"grid-template-rows", "repeat(auto-fill, minmax(100px, 1fr)) [end]", GridTrackList{
+			Tracks:   []GridTrack{{Repeat: &GridRepeat{Auto: "auto-fill", Tracks: []GridTrack{{Size: GridTrackSize{Min: px(100), Max: fr(1)}}}}}},
+			EndNames: []string{"end"},
+		}},
+		{"grid-template-columns", "fit-content(40%) min-content", GridTrackList{Tracks: []GridTrack{
+			{Size: GridTrackSize{Min: Length{Keyword: "auto"}, Max: percent(40), FitContent: true}},
+			{Size: fixed(Length{Keyword: "min-content"})},
+		}}},
+		{"grid-template-columns", "subgrid [a] repeat(2, [b])", GridTrackList{Subgrid: true, SubgridNames: [][]string{{"a"}, {"b"}, {"b"}}}},
+		{"grid-template-areas", `"head head" "nav  main" ". main"`, GridTemplateAreas{Rows: [][]string{{"head", "head"}, {"nav", "main"}, {".", "main"}}}},
+		{"grid-template-areas", `"a...b"`, GridTemplateAreas{Rows: [][]string{{"a", ".", "b"}}}},
+		{"grid-auto-rows", "40px minmax(10px, auto)", []GridTrackSize{fixed(px(40)), {Min: px(10), Max: Length{Keyword: "auto"}}}},
+		{"grid-auto-flow", "dense column", GridAutoFlow{Column: true, Dense: true}},
+		{"grid-row-start", "span 2", GridLine{Span: true, Integer: 2}},
+		{"grid-row-end", "-1 foo", GridLine{Integer: -1, Name: "foo"}},
+		{"grid-column-start", "auto", GridLine{}},
+		{"grid-column", "a", GridPlacement{Start: GridLine{Name: "a"}, End: GridLine{Name: "a"}}},
+		{"grid-row", "2 / span foo", GridPlacement{Start: GridLine{Integer: 2}, End: GridLine{Span: true, Name: "foo"}}},
+		{"grid-area", "1 / 2", GridArea{Row: GridPlacement{Start: GridLine{Integer: 1}}, Column: GridPlacement{Start: GridLine{Integer: 2}}}},
+		{"justify-self", "safe left", Alignment{Position: "left", Overflow: "safe"}},
+		{"justify-items", "legacy center", Alignment{Position: "center"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"grid-template-columns": "repeat(auto-fill, 10px) repeat(auto-fit, 10px)",
+		"grid-template-rows":    "repeat(auto-fill, 1fr)",
+		"grid-auto-columns":     "-10px",
+		"grid-template-areas":   `"a b a"`,
+		"grid-template":         `"a b" "b b"`,
+		"grid":                  `"a b" "c"`,
+		"grid-column-start":     "span",
+		"grid-row":              "span 0",
+		"grid-area":             "1 / 2 / 3 / 4 / 5",
+		"justify-items":         "legacy stretch",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	assert.Equal(t, map[string]GridRegion{
+		"head": {RowStart: 1, RowEnd: 2, ColumnStart: 1, ColumnEnd: 3},
+		"main": {RowStart: 2, RowEnd: 4, ColumnStart: 2, ColumnEnd: 3},
+	}, GridTemplateAreas{Rows: [][]string{{"head", "head"}, {".", "main"}, {".", "main"}}}.Areas())
+	assert.Equal(t, "span 2 foo", GridLine{Span: true, Integer: 2, Name: "foo"}.String())
+	assert.Equal(t, "1 / auto / 3 / auto", GridArea{Row: GridPlacement{Start: GridLine{Integer: 1}, End: GridLine{Integer: 3}}}.String())
+}
+
+func TestExpandGrid(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{"grid-template", `[top] "a a" 40px [mid] "b c" [bottom] / 1fr 2fr`, map[string]string{
+			"grid-template-rows":    "[top] 40px [mid] auto [bottom]",
+			"grid-template-columns": "1fr 2fr",
+			"grid-template-areas":   `"a a" "b c"`,
+		}},
+		{"grid-template", "100px 1fr / repeat(2, 50px)", map[string]string{
+			"grid-template-rows":    "100px 1fr",
+			"grid-template-columns": "repeat(2, 50px)",
+			"grid-template-areas":   "none",
+		}},
+		{"grid", "auto-flow dense 40px / 1fr 1fr", map[string]string{
+			"grid-template-rows":    "none",
+			"grid-template-columns": "1fr 1fr",
+			"grid-template-areas":   "none",
+			"grid-auto-flow":        "row dense",
+			"grid-auto-rows":        "40px",
+			"grid-auto-columns":     "auto",
+		}},
+		{"grid", "repeat(3, 10px) / auto-flow", map[string]string{
+			"grid-template-rows":    "repeat(3, 10px)",
+			"grid-template-columns": "none",
+			"grid-template-areas":   "none",
+			"grid-auto-flow":        "column",
+			"grid-auto-rows":        "auto",
+			"grid-auto-columns":     "auto",
+		}},
+		{"grid-row", "span 2 / 5", map[string]string{"grid-row-start": "span 2", "grid-row-end": "5"}},
+		{"grid-column", "3", map[string]string{"grid-column-start": "3", "grid-column-end": "auto"}},
+		{"grid-area", "main", map[string]string{
+			"grid-row-start": "main", "grid-column-start": "main", "grid-row-end": "main", "grid-column-end": "main",
+		}},
+		{"grid-area", "1 / a / 2", map[string]string{
+			"grid-row-start": "1", "grid-column-start": "a", "grid-row-end": "2", "grid-column-end": "a",
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			longhands, err := ExpandShorthand(tt.name, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, longhands)
+		})
+	}
+
+	_, err := ExpandShorthand("grid-template", `"a b" "a a"`)
+	assert.Error(t, err)
+}
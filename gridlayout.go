@@ -0,0 +1,995 @@
+package css
+
+import (
+	"math"
+	"sort"
+)
+
+// GridItem is a child of a grid container. Its fields are those of
+// FlexItem.
+type GridItem = FlexItem
+
+// The axes of a grid, used to index per axis values.
+const (
+	gridRows = iota
+	gridColumns
+)
+
+// gridSides are the indexes of the start and end sides of the axes.
+var gridSides = [2][2]int{gridRows: {0, 2}, gridColumns: {3, 1}}
+
+// gridSizeNames are the size properties of the axes.
+var gridSizeNames = [2]string{gridRows: "height", gridColumns: "width"}
+
+// gridTrack is a track of the implicit grid during layout.
+type gridTrack struct {
+	size GridTrackSize
+	// base and limit are the base size and growth limit of the track.
+	base, limit float64
+	// autoFit is set for tracks of an auto-fit repetition, and collapsed
+	// for those of them that no item is placed in.
+	autoFit, collapsed bool
+	// pos is the offset of the track in the content box.
+	pos float64
+}
+
+// gridAxis is the explicit and implicit grid along one axis.
+type gridAxis struct {
+	tracks []*gridTrack
+	// explicit are the tracks of the explicit grid, and names the names of
+	// its lines.
+	explicit []GridTrackSize
+	autoFit  []bool
+	names    map[string][]int
+	auto     []GridTrackSize
+	// offset is the number of implicit tracks before the explicit grid.
+	offset    int
+	gap       float64
+	available float64
+	content   Alignment
+}
+
+// gridItem is a grid item during layout.
+type gridItem struct {
+	*layoutBox
+	index, order int
+	placement    [2]GridPlacement
+	// area are the start and end lines of the item along each axis,
+	// relative to the start of the explicit grid until the implicit grid
+	// is known.
+	area     [2][2]int
+	definite [2]bool
+	content  [2][2]float64
+	align    [2]Alignment
+}
+
+func (g *gridItem) span(axis int) int {
+	return g.area[axis][1] - g.area[axis][0]
+}
+
+// GridLayout lays out the children of a grid container with the given
+// computed style. container is the size of its content box, with an
+// Indefinite height or width if it depends on the items. Items are placed
+// in order of their order property, but their boxes are returned in the
+// order of items. Items with display: none or absolute positioning don't
+// take part in the layout and get an empty box. Baseline alignment falls
+// back to start.
+func GridLayout(style map[string]string, container Size, items []GridItem) (Layout, error) {
+	c, err := newLayoutBox(style, container.Width)
+	if err != nil {
+		return Layout{}, err
+	}
+	var axes [2]*gridAxis
+	for axis, name := range [2]string{gridRows: "rows", gridColumns: "columns"} {
+		a := &gridAxis{names: make(map[string][]int), auto: []GridTrackSize{autoTrack}}
+		a.available = container.Height
+		if axis == gridColumns {
+			a.available = container.Width
+		}
+		if v, err := c.value("grid-auto-" + name); err != nil {
+			return Layout{}, err
+		} else if auto, ok := v.([]GridTrackSize); ok {
+			a.auto = auto
+		}
+		gap, err := c.length(map[int]string{gridRows: "row-gap", gridColumns: "column-gap"}[axis], a.available)
+		if err != nil {
+			return Layout{}, err
+		}
+		a.gap = gap.px
+		content := map[int]string{gridRows: "align-content", gridColumns: "justify-content"}[axis]
+		if a.content, err = alignmentOr(c, content, "normal"); err != nil {
+			return Layout{}, err
+		}
+		v, err := c.value("grid-template-" + name)
+		if err != nil {
+			return Layout{}, err
+		}
+		if tracks, ok := v.(GridTrackList); ok && !tracks.None && !tracks.Subgrid {
+			a.addTracks(tracks)
+		}
+		axes[axis] = a
+	}
+	if v, err := c.value("grid-template-areas"); err != nil {
+		return Layout{}, err
+	} else if areas, ok := v.(GridTemplateAreas); ok {
+		axes[gridRows].addAreas(areas, gridRows)
+		axes[gridColumns].addAreas(areas, gridColumns)
+	}
+	var flow GridAutoFlow
+	if v, err := c.value("grid-auto-flow"); err != nil {
+		return Layout{}, err
+	} else if f, ok := v.(GridAutoFlow); ok {
+		flow = f
+	}
+	var defaultAlign [2]Alignment
+	for axis, name := range [2]string{gridRows: "align-items", gridColumns: "justify-items"} {
+		if defaultAlign[axis], err = alignmentOr(c, name, "normal"); err != nil {
+			return Layout{}, err
+		}
+	}
+
+	layout := Layout{Boxes: make([]Rect, len(items))}
+	var gridItems []*gridItem
+	for i, item := range items {
+		g, err := newGridItem(item, i, defaultAlign)
+		if err != nil {
+			return Layout{}, err
+		}
+		if g != nil {
+			gridItems = append(gridItems, g)
+		}
+	}
+	sort.SliceStable(gridItems, func(i, j int) bool {
+		return gridItems[i].order < gridItems[j].order
+	})
+
+	placeGridItems(gridItems, axes, flow)
+	for axis, a := range axes {
+		a.collapseAutoFit(gridItems, axis)
+	}
+	axes[gridColumns].sizeTracks(gridItems, gridColumns)
+	axes[gridRows].sizeTracks(gridItems, gridRows)
+
+	var size [2]float64
+	for axis, a := range axes {
+		size[axis] = a.position()
+	}
+	for _, g := range gridItems {
+		var rect [2][2]float64
+		for axis, a := range axes {
+			start, end := a.tracks[g.area[axis][0]], a.tracks[g.area[axis][1]-1]
+			rect[axis] = g.alignSelf(axis, end.pos+end.base-start.pos)
+			rect[axis][0] += start.pos
+		}
+		layout.Boxes[g.index] = Rect{rect[gridColumns][0], rect[gridRows][0], rect[gridColumns][1], rect[gridRows][1]}
+	}
+	layout.Size = Size{size[gridColumns], size[gridRows]}
+	return layout, nil
+}
+
+// addTracks adds the explicit tracks of l, repeating automatic
+// repetitions as often as they fit into the available space.
+func (a *gridAxis) addTracks(l GridTrackList) {
+	for _, t := range l.Tracks {
+		a.addNames(t.Names)
+		if t.Repeat == nil {
+			a.explicit = append(a.explicit, t.Size)
+			a.autoFit = append(a.autoFit, false)
+			continue
+		}
+		count := t.Repeat.Count
+		if t.Repeat.Auto != "" {
+			count = a.repetitions(l, t.Repeat)
+		}
+		for i := 0; i < count && len(a.explicit) < maxGridLines; i++ {
+			for _, rt := range t.Repeat.Tracks {
+				a.addNames(rt.Names)
+				a.explicit = append(a.explicit, rt.Size)
+				a.autoFit = append(a.autoFit, t.Repeat.Auto == "auto-fit")
+			}
+			a.addNames(t.Repeat.EndNames)
+		}
+	}
+	a.addNames(l.EndNames)
+}
+
+// addNames adds names to the line after the last explicit track.
+func (a *gridAxis) addNames(names []string) {
+	for _, name := range names {
+		a.names[name] = append(a.names[name], len(a.explicit))
+	}
+}
+
+// repetitions returns the number of repetitions of an automatic repeat()
+// of the track list l that fit into the available space, at least 1.
+func (a *gridAxis) repetitions(l GridTrackList, r *GridRepeat) int {
+	if a.available == Indefinite {
+		return 1
+	}
+	fixed, count := 0.0, 0
+	for _, t := range l.Tracks {
+		if t.Repeat == nil {
+			fixed += a.fixedSize(t.Size)
+			count++
+		}
+	}
+	repeated := 0.0
+	for _, t := range r.Tracks {
+		repeated += a.fixedSize(t.Size)
+	}
+	n := math.Floor((a.available - fixed - a.gap*float64(count) + a.gap) / (repeated + a.gap*float64(len(r.Tracks))))
+	if n < 1 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 1
+	}
+	return int(n)
+}
+
+// fixedSize returns the size of a track for automatic repetitions: its max
+// breadth if that is definite, else its min breadth, else zero.
+func (a *gridAxis) fixedSize(s GridTrackSize) float64 {
+	if px, ok := s.Max.Pixels(a.available, defaultFontSize); ok && !s.FitContent {
+		return px
+	}
+	px, _ := s.Min.Pixels(a.available, defaultFontSize)
+	return px
+}
+
+// addAreas extends the explicit grid to the areas and names the lines of
+// the areas name-start and name-end.
+func (a *gridAxis) addAreas(areas GridTemplateAreas, axis int) {
+	n := len(areas.Rows)
+	if axis == gridColumns && n > 0 {
+		n = len(areas.Rows[0])
+	}
+	for i := len(a.explicit); i < n; i++ {
+		a.explicit = append(a.explicit, a.auto[i%len(a.auto)])
+		a.autoFit = append(a.autoFit, false)
+	}
+	for name, r := range areas.Areas() {
+		start, end := r.RowStart, r.RowEnd
+		if axis == gridColumns {
+			start, end = r.ColumnStart, r.ColumnEnd
+		}
+		a.names[name+"-start"] = append(a.names[name+"-start"], start-1)
+		a.names[name+"-end"] = append(a.names[name+"-end"], end-1)
+	}
+	for name := range a.names {
+		sort.Ints(a.names[name])
+	}
+}
+
+func (a *gridAxis) hasName(line int, name string) bool {
+	for _, l := range a.names[name] {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// namedLine returns the nth line named name from line from in direction
+// dir. Lines outside the explicit grid have every name.
+func (a *gridAxis) namedLine(name string, from, n, dir int) int {
+	if n > 2*maxGridLines { // the line is clamped anyway
+		n = 2 * maxGridLines
+	}
+	line := from
+	for n > 0 {
+		line += dir
+		if line < 0 || line > len(a.explicit) || a.hasName(line, name) {
+			n--
+		}
+	}
+	return line
+}
+
+// line resolves a definite grid line to a line index relative to the
+// start of the explicit grid.
+func (a *gridAxis) line(l GridLine, end bool) int {
+	if l.Integer == 0 {
+		suffix := "-start"
+		if end {
+			suffix = "-end"
+		}
+		if lines := a.names[l.Name+suffix]; len(lines) > 0 {
+			return lines[0]
+		}
+		l.Integer = 1
+	}
+	switch {
+	case l.Name == "" && l.Integer > 0:
+		return l.Integer - 1
+	case l.Name == "":
+		return len(a.explicit) + 1 + l.Integer
+	case l.Integer > 0:
+		return a.namedLine(l.Name, -1, l.Integer, 1)
+	}
+	return a.namedLine(l.Name, len(a.explicit)+1, -l.Integer, -1)
+}
+
+// resolve resolves the placement p of an item along the axis. It returns
+// false with the span of the item if it needs to be auto-placed.
+func (a *gridAxis) resolve(p GridPlacement) (area [2]int, definite bool) {
+	start, end := p.Start, p.End
+	startDefinite := !start.IsAuto() && !start.Span
+	endDefinite := !end.IsAuto() && !end.Span
+	span := func(l GridLine) int {
+		switch {
+		case !l.Span || l.Name != "":
+			return 1
+		case l.Integer > maxGridLines:
+			return maxGridLines
+		}
+		return l.Integer
+	}
+	switch {
+	case startDefinite && endDefinite:
+		s, e := a.line(start, false), a.line(end, true)
+		if e < s {
+			s, e = e, s
+		}
+		if e == s {
+			e++
+		}
+		return clampGridArea([2]int{s, e}), true
+	case startDefinite:
+		s := a.line(start, false)
+		if end.Span && end.Name != "" {
+			return clampGridArea([2]int{s, a.namedLine(end.Name, s, maxInt(end.Integer, 1), 1)}), true
+		}
+		return clampGridArea([2]int{s, s + span(end)}), true
+	case endDefinite:
+		e := a.line(end, true)
+		if start.Span && start.Name != "" {
+			return clampGridArea([2]int{a.namedLine(start.Name, e, maxInt(start.Integer, 1), -1), e}), true
+		}
+		return clampGridArea([2]int{e - span(start), e}), true
+	}
+	return [2]int{0, span(start)}, false
+}
+
+// maxGridLines limits the grid to the lines from -maxGridLines to
+// maxGridLines relative to the start of the explicit grid, as css-grid
+// allows for overly large grids.
+const maxGridLines = 10000
+
+// clampGridArea clamps an area to the limited grid. Areas completely
+// outside of it are moved into its first or last track.
+func clampGridArea(area [2]int) [2]int {
+	for i, line := range area {
+		if line < -maxGridLines {
+			area[i] = -maxGridLines
+		} else if line > maxGridLines {
+			area[i] = maxGridLines
+		}
+	}
+	if area[0] == area[1] {
+		if area[1] == maxGridLines {
+			area[0]--
+		} else {
+			area[1]++
+		}
+	}
+	return area
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func newGridItem(item GridItem, index int, defaultAlign [2]Alignment) (*gridItem, error) {
+	b, err := newLayoutBox(item.Style, Indefinite)
+	if err != nil {
+		return nil, err
+	}
+	if d, err := b.value("display"); err != nil {
+		return nil, err
+	} else if d, ok := d.(Display); ok && d.IsNone() {
+		return nil, nil
+	}
+	if p, err := b.value("position"); err != nil {
+		return nil, err
+	} else if p, ok := p.(PositionScheme); ok && p.IsAbsolute() {
+		return nil, nil
+	}
+	g := &gridItem{layoutBox: b, index: index}
+	if order, err := b.value("order"); err != nil {
+		return nil, err
+	} else if order, ok := order.(int); ok {
+		g.order = order
+	}
+	for axis, name := range [2]string{gridRows: "grid-row", gridColumns: "grid-column"} {
+		for i, edge := range [2]string{"-start", "-end"} {
+			v, err := b.value(name + edge)
+			if err != nil {
+				return nil, err
+			}
+			if l, ok := v.(GridLine); ok {
+				if i == 0 {
+					g.placement[axis].Start = l
+				} else {
+					g.placement[axis].End = l
+				}
+			}
+		}
+	}
+	g.content[gridRows] = [2]float64{item.MinContent.Height, item.MaxContent.Height}
+	g.content[gridColumns] = [2]float64{item.MinContent.Width, item.MaxContent.Width}
+	for axis, name := range [2]string{gridRows: "align-self", gridColumns: "justify-self"} {
+		if g.align[axis], err = alignmentOr(b, name, "auto"); err != nil {
+			return nil, err
+		}
+		if g.align[axis].Position == "auto" {
+			g.align[axis] = defaultAlign[axis]
+		}
+	}
+	return g, nil
+}
+
+// placeGridItems places the items into the grid, runs the auto-placement
+// algorithm and creates the implicit tracks of both axes.
+func placeGridItems(items []*gridItem, axes [2]*gridAxis, flow GridAutoFlow) {
+	for _, g := range items {
+		for axis, a := range axes {
+			g.area[axis], g.definite[axis] = a.resolve(g.placement[axis])
+		}
+	}
+
+	// shift the lines so that implicit tracks before the explicit grid
+	// have non-negative indexes
+	for axis, a := range axes {
+		for _, g := range items {
+			if g.definite[axis] && -g.area[axis][0] > a.offset {
+				a.offset = -g.area[axis][0]
+			}
+		}
+		for _, g := range items {
+			if g.definite[axis] {
+				g.area[axis][0] += a.offset
+				g.area[axis][1] += a.offset
+			}
+		}
+	}
+
+	// major is the axis auto-placement fills, minor the one it grows
+	major, minor := gridColumns, gridRows
+	if flow.Column {
+		major, minor = gridRows, gridColumns
+	}
+	var occupied [][2][2]int
+	place := func(g *gridItem) {
+		occupied = append(occupied, g.area)
+	}
+	fits := func(g *gridItem) bool {
+		for _, area := range occupied {
+			if g.area[minor][0] < area[minor][1] && area[minor][0] < g.area[minor][1] &&
+				g.area[major][0] < area[major][1] && area[major][0] < g.area[major][1] {
+				return false
+			}
+		}
+		return true
+	}
+	move := func(g *gridItem, axis, start int) {
+		g.area[axis] = [2]int{start, start + g.span(axis)}
+	}
+
+	for _, g := range items {
+		if g.definite[major] && g.definite[minor] {
+			place(g)
+		}
+	}
+
+	// items locked to a row in row flow
+	cursors := make(map[int]int)
+	for _, g := range items {
+		if g.definite[major] || !g.definite[minor] {
+			continue
+		}
+		start := 0
+		if !flow.Dense {
+			start = cursors[g.area[minor][0]]
+		}
+		move(g, major, start)
+		for !fits(g) {
+			start++
+			move(g, major, start)
+		}
+		place(g)
+		g.definite[major] = true
+		cursors[g.area[minor][0]] = g.area[major][1]
+	}
+
+	columns := axes[major].offset + len(axes[major].explicit)
+	for _, g := range items {
+		if g.definite[major] && g.area[major][1] > columns {
+			columns = g.area[major][1]
+		} else if !g.definite[major] && g.span(major) > columns {
+			columns = g.span(major)
+		}
+	}
+
+	var cursor [2]int
+	for _, g := range items {
+		if g.definite[major] && g.definite[minor] {
+			continue
+		}
+		if flow.Dense {
+			cursor = [2]int{}
+		}
+		if g.definite[major] {
+			if g.area[major][0] < cursor[major] && !flow.Dense {
+				cursor[minor]++
+			}
+			cursor[major] = g.area[major][0]
+			move(g, minor, cursor[minor])
+			for !fits(g) {
+				cursor[minor]++
+				move(g, minor, cursor[minor])
+			}
+		} else {
+			for {
+				move(g, minor, cursor[minor])
+				move(g, major, cursor[major])
+				if g.area[major][1] > columns {
+					cursor[minor]++
+					cursor[major] = 0
+					continue
+				}
+				if fits(g) {
+					break
+				}
+				cursor[major]++
+			}
+			cursor[major] = g.area[major][1]
+		}
+		place(g)
+		g.definite[major], g.definite[minor] = true, true
+	}
+
+	for axis, a := range axes {
+		n := a.offset + len(a.explicit)
+		for _, g := range items {
+			// auto-placement may go past the limited grid too
+			area := clampGridArea([2]int{g.area[axis][0] - a.offset, g.area[axis][1] - a.offset})
+			g.area[axis] = [2]int{area[0] + a.offset, area[1] + a.offset}
+			if g.area[axis][1] > n {
+				n = g.area[axis][1]
+			}
+		}
+		a.tracks = make([]*gridTrack, n)
+		for i := range a.tracks {
+			a.tracks[i] = &gridTrack{size: a.trackSize(i - a.offset)}
+			if j := i - a.offset; j >= 0 && j < len(a.explicit) {
+				a.tracks[i].autoFit = a.autoFit[j]
+			}
+		}
+	}
+}
+
+// trackSize returns the sizing functions of the track i of the explicit
+// grid. Implicit tracks repeat grid-auto-rows or grid-auto-columns, before
+// the explicit grid backwards from its last value.
+func (a *gridAxis) trackSize(i int) GridTrackSize {
+	n := len(a.auto)
+	switch {
+	case i < 0:
+		return a.auto[n-1-(-i-1)%n]
+	case i < len(a.explicit):
+		return a.explicit[i]
+	}
+	return a.auto[(i-len(a.explicit))%n]
+}
+
+// collapseAutoFit collapses the tracks of auto-fit repetitions that no
+// item is placed in.
+func (a *gridAxis) collapseAutoFit(items []*gridItem, axis int) {
+	for i, t := range a.tracks {
+		if !t.autoFit {
+			continue
+		}
+		t.collapsed = true
+		for _, g := range items {
+			if g.area[axis][0] <= i && i < g.area[axis][1] {
+				t.collapsed = false
+				break
+			}
+		}
+	}
+}
+
+// Contributions of an item to the size of the tracks it spans.
+const (
+	minContribution = iota
+	maxContribution
+)
+
+// contribution returns the outer min-content or max-content size of the
+// item along the axis.
+func (g *gridItem) contribution(axis, kind int) float64 {
+	sides := gridSides[axis]
+	edges := g.edges(sides[0], sides[1])
+	size := g.content[axis][kind] + edges
+	if s, err := g.size(gridSizeNames[axis], Indefinite, edges); err == nil && s.definite() {
+		size = s.px
+	}
+	min, _ := g.size("min-"+gridSizeNames[axis], Indefinite, edges)
+	max, _ := g.size("max-"+gridSizeNames[axis], Indefinite, edges)
+	return clampSize(size, min, max) + g.margins(sides[0], sides[1])
+}
+
+// isFlexible reports whether the max sizing function of t is an fr value.
+func (t *gridTrack) isFlexible() bool {
+	return t.size.Max.Unit == "fr" && t.size.Max.Math == nil
+}
+
+// intrinsicMin reports whether the min sizing function of t depends on the
+// content.
+func (t *gridTrack) intrinsicMin(available float64) bool {
+	_, ok := t.size.Min.Pixels(available, defaultFontSize)
+	return !ok
+}
+
+func (t *gridTrack) intrinsicMax(available float64) bool {
+	if t.size.FitContent {
+		return true
+	}
+	_, ok := t.size.Max.Pixels(available, defaultFontSize)
+	return !ok && !t.isFlexible()
+}
+
+// sizeTracks runs the grid track sizing algorithm along the axis.
+func (a *gridAxis) sizeTracks(items []*gridItem, axis int) {
+	var visible []*gridTrack
+	for _, t := range a.tracks {
+		if !t.collapsed {
+			visible = append(visible, t)
+		}
+	}
+	totalGaps := gaps(a.gap, len(visible))
+	infinity := math.Inf(1)
+
+	for _, t := range visible {
+		t.base, t.limit = 0, infinity
+		if px, ok := t.size.Min.Pixels(a.available, defaultFontSize); ok {
+			t.base = px
+		}
+		if px, ok := t.size.Max.Pixels(a.available, defaultFontSize); ok && !t.size.FitContent && !t.isFlexible() {
+			t.limit = px
+		}
+		if t.limit < t.base {
+			t.limit = t.base
+		}
+	}
+
+	// items spanning a single track, then items spanning more tracks
+	spanned := func(g *gridItem) []*gridTrack {
+		var tracks []*gridTrack
+		for _, t := range a.tracks[g.area[axis][0]:g.area[axis][1]] {
+			if !t.collapsed {
+				tracks = append(tracks, t)
+			}
+		}
+		return tracks
+	}
+	sorted := make([]*gridItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].span(axis) < sorted[j].span(axis)
+	})
+	for _, g := range sorted {
+		tracks := spanned(g)
+		flexible := false
+		for _, t := range tracks {
+			flexible = flexible || t.isFlexible()
+		}
+		if len(tracks) == 0 {
+			continue
+		}
+		minSize := g.contribution(axis, minContribution)
+		maxSize := g.contribution(axis, maxContribution)
+		spanGaps := gaps(a.gap, len(tracks))
+
+		if len(tracks) == 1 && !flexible {
+			t := tracks[0]
+			if t.intrinsicMin(a.available) {
+				size := minSize
+				if t.size.Min.Is("max-content") {
+					size = maxSize
+				}
+				if size > t.base {
+					t.base = size
+				}
+			}
+			if t.intrinsicMax(a.available) {
+				size := maxSize
+				if t.size.Max.Is("min-content") {
+					size = minSize
+				}
+				if t.size.FitContent {
+					if limit, ok := t.size.Max.Pixels(a.available, defaultFontSize); ok && size > limit {
+						size = math.Max(limit, minSize)
+					}
+				}
+				if t.limit == infinity || size > t.limit {
+					t.limit = size
+				}
+			}
+			continue
+		}
+
+		// distribute the extra space of spanning items
+		var growable []*gridTrack
+		for _, t := range tracks {
+			if flexible && t.isFlexible() || !flexible && t.intrinsicMin(a.available) {
+				growable = append(growable, t)
+			}
+		}
+		extra := minSize - spanGaps
+		for _, t := range tracks {
+			extra -= t.base
+		}
+		if flexible {
+			factors := 0.0
+			for _, t := range growable {
+				factors += t.size.Max.Value
+			}
+			for _, t := range growable {
+				if extra > 0 && factors > 0 {
+					t.base += extra * t.size.Max.Value / factors
+				}
+			}
+			continue
+		}
+		growTracks(growable, extra)
+		growable = growable[:0]
+		extra = maxSize - spanGaps
+		for _, t := range tracks {
+			if t.limit == infinity {
+				extra -= t.base
+			} else {
+				extra -= t.limit
+			}
+			if t.intrinsicMax(a.available) {
+				growable = append(growable, t)
+			}
+		}
+		for _, t := range growable {
+			if t.limit == infinity {
+				t.limit = t.base
+			}
+			if extra > 0 {
+				t.limit += extra / float64(len(growable))
+			}
+		}
+	}
+	for _, t := range visible {
+		if t.limit == infinity || t.limit < t.base {
+			t.limit = t.base
+		}
+	}
+
+	// maximize the tracks
+	if a.available == Indefinite {
+		for _, t := range visible {
+			t.base = t.limit
+		}
+	} else {
+		free := a.available - totalGaps
+		for _, t := range visible {
+			free -= t.base
+		}
+		distributeTracks(visible, free, func(t *gridTrack) bool { return t.base < t.limit })
+	}
+
+	a.expandFlexibleTracks(visible, items, axis, spanned)
+
+	// stretch auto tracks
+	if (a.content.Position == "normal" || a.content.Position == "stretch") && a.available != Indefinite {
+		free := a.available - totalGaps
+		var auto []*gridTrack
+		for _, t := range visible {
+			free -= t.base
+			if t.size.Max.Is("auto") && !t.size.FitContent {
+				auto = append(auto, t)
+			}
+		}
+		if free > 0 {
+			for _, t := range auto {
+				t.base += free / float64(len(auto))
+			}
+		}
+	}
+}
+
+// growTracks distributes extra space equally to the base sizes of tracks,
+// first up to their growth limits and then beyond them.
+func growTracks(tracks []*gridTrack, extra float64) {
+	if extra <= 0 || len(tracks) == 0 {
+		return
+	}
+	base := make(map[*gridTrack]float64, len(tracks))
+	for _, t := range tracks {
+		base[t] = t.base
+	}
+	distributeTracks(tracks, extra, func(t *gridTrack) bool { return t.base < t.limit })
+	for _, t := range tracks {
+		extra -= t.base - base[t]
+	}
+	if extra > 1e-9 {
+		for _, t := range tracks {
+			t.base += extra / float64(len(tracks))
+		}
+	}
+}
+
+// distributeTracks distributes free space equally to the tracks that can
+// grow, capping them at their growth limits.
+func distributeTracks(tracks []*gridTrack, free float64, canGrow func(*gridTrack) bool) {
+	for free > 1e-9 {
+		var growable []*gridTrack
+		for _, t := range tracks {
+			if canGrow(t) {
+				growable = append(growable, t)
+			}
+		}
+		if len(growable) == 0 {
+			return
+		}
+		share := free / float64(len(growable))
+		for _, t := range growable {
+			grow := math.Min(share, t.limit-t.base)
+			t.base += grow
+			free -= grow
+		}
+	}
+}
+
+// expandFlexibleTracks sizes the fr tracks.
+func (a *gridAxis) expandFlexibleTracks(tracks []*gridTrack, items []*gridItem, axis int, spanned func(*gridItem) []*gridTrack) {
+	var flexible []*gridTrack
+	for _, t := range tracks {
+		if t.isFlexible() {
+			flexible = append(flexible, t)
+		}
+	}
+	if len(flexible) == 0 {
+		return
+	}
+
+	var fr float64
+	if a.available != Indefinite {
+		fr = findFrSize(tracks, a.available-gaps(a.gap, len(tracks)))
+	} else {
+		for _, t := range flexible {
+			size := t.base
+			if t.size.Max.Value > 1 {
+				size /= t.size.Max.Value
+			}
+			fr = math.Max(fr, size)
+		}
+		for _, g := range items {
+			spans := spanned(g)
+			space := g.contribution(axis, maxContribution) - gaps(a.gap, len(spans))
+			factors, crosses := 0.0, false
+			for _, t := range spans {
+				if t.isFlexible() {
+					factors += t.size.Max.Value
+					crosses = true
+				} else {
+					space -= t.base
+				}
+			}
+			if crosses {
+				fr = math.Max(fr, space/math.Max(factors, 1))
+			}
+		}
+	}
+	for _, t := range flexible {
+		if size := fr * t.size.Max.Value; size > t.base {
+			t.base = size
+		}
+	}
+}
+
+// findFrSize returns the size of an fr that fills space with tracks,
+// treating flexible tracks whose base size is larger than their share as
+// inflexible.
+func findFrSize(tracks []*gridTrack, space float64) float64 {
+	inflexible := make(map[*gridTrack]bool)
+	for {
+		leftover, factors := space, 0.0
+		for _, t := range tracks {
+			if t.isFlexible() && !inflexible[t] {
+				factors += t.size.Max.Value
+			} else {
+				leftover -= t.base
+			}
+		}
+		fr := leftover / math.Max(factors, 1)
+		done := true
+		for _, t := range tracks {
+			if t.isFlexible() && !inflexible[t] && fr*t.size.Max.Value < t.base {
+				inflexible[t] = true
+				done = false
+			}
+		}
+		if done {
+			return math.Max(fr, 0)
+		}
+	}
+}
+
+// position aligns the tracks with the content distribution of the axis
+// and sets their positions. It returns the size of the content box along
+// the axis.
+func (a *gridAxis) position() float64 {
+	var visible []*gridTrack
+	used := 0.0
+	for _, t := range a.tracks {
+		if !t.collapsed {
+			visible = append(visible, t)
+			used += t.base
+		} else {
+			t.base = 0
+		}
+	}
+	used += gaps(a.gap, len(visible))
+	size := a.available
+	if size == Indefinite {
+		size = used
+	}
+	offset, between := distribute(a.content, size-used, len(visible), false)
+	for _, t := range a.tracks {
+		t.pos = offset
+		if !t.collapsed {
+			offset += t.base + a.gap + between
+		}
+	}
+	return size
+}
+
+// alignSelf returns the offset and size of the border box of the item
+// along the axis in its grid area of the given size.
+func (g *gridItem) alignSelf(axis int, area float64) [2]float64 {
+	sides := gridSides[axis]
+	edges := g.edges(sides[0], sides[1])
+	margins := g.margins(sides[0], sides[1])
+	autoStart, autoEnd := g.autoMargin[sides[0]], g.autoMargin[sides[1]]
+	name := gridSizeNames[axis]
+	size, _ := g.size(name, area, edges)
+	min, _ := g.size("min-"+name, area, edges)
+	max, _ := g.size("max-"+name, area, edges)
+
+	align := g.align[axis]
+	var used float64
+	switch {
+	case size.definite():
+		used = size.px
+	case (align.Position == "normal" || align.Position == "stretch" || align.Position == "legacy") && !autoStart && !autoEnd:
+		used = area - margins
+	default:
+		// fit-content
+		used = math.Min(g.content[axis][1]+edges, math.Max(g.content[axis][0]+edges, area-margins))
+	}
+	used = clampSize(math.Max(used, edges), min, max)
+
+	free := area - used - margins
+	offset := g.margin[sides[0]]
+	switch {
+	case free > 0 && autoStart && autoEnd:
+		offset += free / 2
+	case free > 0 && autoStart:
+		offset += free
+	case free > 0 && autoEnd:
+	default:
+		if align.Position == "legacy" {
+			align.Position = "start"
+		}
+		o, _ := distribute(selfAlignment(align), free, 1, false)
+		offset += o
+	}
+	return [2]float64{offset, used}
+}
@@ -0,0 +1,147 @@
+package css
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridLayout(t *testing.T) {
+	item := func(style map[string]string, width, height float64) GridItem {
+		return GridItem{Style: style, MinContent: Size{width, height}, MaxContent: Size{width, height}}
+	}
+	cases := []struct {
+		name      string
+		style     map[string]string
+		container Size
+		items     []GridItem
+		expected  Layout
+	}{
+		{
+			"fixed and flexible tracks with gaps",
+			map[string]string{"grid-template-columns": "100px 1fr 2fr", "gap": "10px"},
+			Size{400, Indefinite},
+			[]GridItem{item(nil, 10, 20), item(nil, 10, 30), item(nil, 10, 10), item(nil, 10, 10)},
+			Layout{Size{400, 50}, []Rect{{0, 0, 100, 30}, {110, 0, 280.0 / 3, 30}, {120 + 280.0/3, 0, 560.0 / 3, 30}, {0, 40, 100, 10}}},
+		},
+		{
+			"template areas",
+			map[string]string{"grid-template-areas": `"h h" "s m"`, "grid-template-columns": "50px 1fr"},
+			Size{200, 100},
+			[]GridItem{
+				item(map[string]string{"grid-area": "m"}, 10, 20),
+				item(map[string]string{"grid-area": "h"}, 10, 10),
+				item(map[string]string{"grid-area": "s"}, 10, 10),
+			},
+			Layout{Size{200, 100}, []Rect{{50, 45, 150, 55}, {0, 0, 200, 45}, {0, 45, 50, 55}}},
+		},
+		{
+			"auto-fill",
+			map[string]string{"grid-template-columns": "repeat(auto-fill, 100px)"},
+			Size{350, Indefinite},
+			[]GridItem{item(nil, 10, 20), item(nil, 10, 30), item(nil, 10, 10), item(nil, 10, 10)},
+			Layout{Size{350, 40}, []Rect{{0, 0, 100, 30}, {100, 0, 100, 30}, {200, 0, 100, 30}, {0, 30, 100, 10}}},
+		},
+		{
+			"auto-fit collapses empty tracks",
+			map[string]string{"grid-template-columns": "repeat(auto-fit, 100px)", "justify-content": "center"},
+			Size{350, Indefinite},
+			[]GridItem{item(nil, 10, 20)},
+			Layout{Size{350, 20}, []Rect{{125, 0, 100, 20}}},
+		},
+		{
+			"spanning item in intrinsic tracks",
+			map[string]string{"grid-template-columns": "auto auto"},
+			Size{Indefinite, Indefinite},
+			[]GridItem{item(nil, 30, 20), item(map[string]string{"grid-column": "span 2"}, 100, 30)},
+			Layout{Size{100, 50}, []Rect{{0, 0, 30, 20}, {0, 20, 100, 30}}},
+		},
+		{
+			"dense auto-placement",
+			map[string]string{"grid-template-columns": "1fr 1fr 1fr", "grid-auto-flow": "dense"},
+			Size{300, Indefinite},
+			[]GridItem{
+				item(nil, 10, 10),
+				item(map[string]string{"grid-column": "span 3"}, 10, 10),
+				item(nil, 10, 10),
+				item(map[string]string{"grid-row": "1", "grid-column": "-2"}, 10, 10),
+			},
+			Layout{Size{300, 20}, []Rect{{0, 0, 100, 10}, {0, 10, 300, 10}, {100, 0, 100, 10}, {200, 0, 100, 10}}},
+		},
+		{
+			"self alignment and implicit tracks",
+			map[string]string{"grid-template-columns": "minmax(50px, 1fr) 1fr", "justify-items": "center", "align-items": "end"},
+			Size{300, 100},
+			[]GridItem{
+				{MinContent: Size{80, 10}, MaxContent: Size{120, 10}},
+				item(map[string]string{"grid-row": "2", "margin": "auto"}, 20, 20),
+				item(map[string]string{"grid-column": "a / span 2"}, 20, 20),
+			},
+			Layout{Size{300, 100}, []Rect{{10, 40, 120, 10}, {60, 65, 20, 20}, {280, 30, 20, 20}}},
+		},
+		{
+			"named and negative lines",
+			map[string]string{"grid": "[a] 40px [b] / [c] 1fr [d]"},
+			Size{100, Indefinite},
+			[]GridItem{
+				item(map[string]string{"grid-row": "b", "grid-column": "d"}, 10, 10),
+				item(map[string]string{"grid-area": "-1 / -1 / -3 / -3"}, 10, 10),
+			},
+			Layout{Size{100, 50}, []Rect{{90, 40, 10, 10}, {0, 0, 90, 40}}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := GridLayout(tt.style, tt.container, tt.items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected.Size, layout.Size)
+			assert.Equal(t, len(tt.expected.Boxes), len(layout.Boxes))
+			for i, box := range tt.expected.Boxes {
+				assert.InDeltaSlice(t, []float64{box.X, box.Y, box.Width, box.Height},
+					[]float64{layout.Boxes[i].X, layout.Boxes[i].Y, layout.Boxes[i].Width, layout.Boxes[i].Height}, 1e-9, "box %d", i)
+			}
+		})
+	}
+
+	_, err := GridLayout(map[string]string{"grid-template-areas": `"a b a"`}, Size{100, 100}, nil)
+	assert.Error(t, err)
+	_, err = GridLayout(nil, Size{100, 100}, []GridItem{{Style: map[string]string{"grid-row": "span 0"}}})
+	assert.Error(t, err)
+}
+
+func TestGridLayoutLimits(t *testing.T) {
+	item := func(style map[string]string) GridItem {
+		return GridItem{Style: style, MinContent: Size{10, 10}, MaxContent: Size{10, 10}}
+	}
+	cases := []struct {
+		name  string
+		style map[string]string
+		items []GridItem
+		size  Size
+	}{
+		{"repeat", map[string]string{"grid-template-columns": "repeat(10000000, 1px)"}, []GridItem{item(nil)}, Size{10000, 10}},
+		{"span", map[string]string{"grid-auto-columns": "1px"}, []GridItem{item(map[string]string{"grid-column": "span 10000000"})}, Size{10000, 10}},
+		{"end line", map[string]string{"grid-auto-columns": "1px"}, []GridItem{item(map[string]string{"grid-column": "1 / 10000000"})}, Size{10000, 10}},
+		{"start line", map[string]string{"grid-auto-columns": "1px"}, []GridItem{item(map[string]string{"grid-column": "-10000000 / 1"})}, Size{10000, 10}},
+		{"outside", map[string]string{"grid-auto-columns": "1px"}, []GridItem{item(map[string]string{"grid-column": "20000 / 30000"})}, Size{10000, 10}},
+		{"large areas", map[string]string{"grid-auto-columns": "1px", "grid-auto-rows": "1px"}, []GridItem{
+			item(map[string]string{"grid-area": "1 / 1 / 10000 / 10000"}),
+			item(map[string]string{"grid-area": "span 10000 / span 10000"}),
+		}, Size{10000, 10000}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			layout, err := GridLayout(tt.style, Size{Indefinite, Indefinite}, tt.items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.size, layout.Size)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Image is a typed <image>. Both URL and Gradient are empty for none.
+type Image struct {
+	URL      string
+	Gradient *Gradient
+}
+
+// IsNone reports whether i is the keyword none.
+func (i Image) IsNone() bool {
+	return i.URL == "" && i.Gradient == nil
+}
+
+func (i Image) String() string {
+	switch {
+	case i.Gradient != nil:
+		return i.Gradient.src
+	case i.URL != "":
+		return "url(" + strconv.Quote(i.URL) + ")"
+	}
+	return "none"
+}
+
+// Gradient is a linear, radial or conic gradient.
+type Gradient struct {
+	// Kind is "linear", "radial" or "conic".
+	Kind      string
+	Repeating bool
+	// Angle is the direction of linear gradients and the start angle of
+	// conic gradients, in degrees. Linear gradients point down by default.
+	Angle float64
+	// Corner is set for linear gradients towards a corner, like "top right".
+	// Their angle depends on the size of the box, Angle is the angle for a
+	// square.
+	Corner string
+	// Shape is "circle" or "ellipse" for radial gradients.
+	Shape string
+	// Size of radial gradients, a keyword like "farthest-corner" or one or
+	// two radii.
+	Size []Length
+	// Position is the center of radial and conic gradients.
+	Position Position
+	Stops    []ColorStop
+
+	src string
+}
+
+// ColorStop is a color stop or, if Hint is set, a color interpolation hint
+// of a gradient.
+type ColorStop struct {
+	Color Color
+	// Position is nil if the stop has no position.
+	Position *Length
+	Hint     bool
+}
+
+// imageOf converts a value that matched <image> or none to an Image.
+func imageOf(v ComponentValue) Image {
+	switch v.Type {
+	case URLValue:
+		return Image{URL: v.Text}
+	case FunctionValue:
+		return Image{Gradient: gradientOf(v)}
+	}
+	return Image{}
+}
+
+func gradientOf(v ComponentValue) *Gradient {
+	v.Production = ""
+	g := &Gradient{Position: centerPosition, src: v.String()}
+	name := strings.TrimPrefix(v.Text, "repeating-")
+	g.Repeating = name != v.Text
+	g.Kind = strings.TrimSuffix(name, "-gradient")
+
+	groups := splitCommas(v.Args)
+	if !isColorStop(groups[0]) {
+		g.setLine(groups[0])
+		groups = groups[1:]
+	} else if g.Kind == "linear" {
+		g.Angle = 180
+	}
+	if g.Kind == "radial" && g.Shape == "" {
+		g.Shape = "ellipse"
+		if len(g.Size) == 1 && g.Size[0].Keyword == "" {
+			g.Shape = "circle"
+		}
+	}
+	if g.Kind == "radial" && len(g.Size) == 0 {
+		g.Size = []Length{{Keyword: "farthest-corner"}}
+	}
+
+	for _, group := range groups {
+		if len(group) == 1 && !isColorStop(group) {
+			position := lengthOf(group[0])
+			g.Stops = append(g.Stops, ColorStop{Position: &position, Hint: true})
+			continue
+		}
+		stop := ColorStop{Color: colorOf(group[0])}
+		if len(group) == 1 {
+			g.Stops = append(g.Stops, stop)
+		}
+		// a stop with two positions is two stops of the same color
+		for _, p := range group[1:] {
+			position := lengthOf(p)
+			stop.Position = &position
+			g.Stops = append(g.Stops, stop)
+		}
+	}
+	return g
+}
+
+func isColorStop(group []ComponentValue) bool {
+	return productionGrammars["color"].MatchValues(group[:1])
+}
+
+var sideAngles = map[string]float64{
+	"top": 0, "top right": 45, "right": 90, "bottom right": 135,
+	"bottom": 180, "bottom left": 225, "left": 270, "top left": 315,
+}
+
+// setLine sets the gradient line, shape or center from the first argument.
+func (g *Gradient) setLine(line []ComponentValue) {
+	if g.Kind == "linear" {
+		if line[0].Is("to") {
+			var vertical, horizontal string
+			for _, v := range line[1:] {
+				if isVerticalEdge(v) {
+					vertical = strings.ToLower(v.Text)
+				} else {
+					horizontal = strings.ToLower(v.Text)
+				}
+			}
+			side := strings.TrimSpace(vertical + " " + horizontal)
+			g.Angle = sideAngles[side]
+			if vertical != "" && horizontal != "" {
+				g.Corner = side
+			}
+			return
+		}
+		g.Angle = angleDegrees(line[0])
+		return
+	}
+
+	for i := 0; i < len(line); i++ {
+		v := line[i]
+		switch {
+		case v.Is("at"):
+			g.Position = positionOf(line[i+1:])
+			return
+		case v.Is("from"):
+			g.Angle = angleDegrees(line[i+1])
+			i++
+		case v.Is("circle") || v.Is("ellipse"):
+			g.Shape = strings.ToLower(v.Text)
+		default:
+			g.Size = append(g.Size, lengthOf(v))
+		}
+	}
+}
@@ -0,0 +1,731 @@
+package css
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interpolate returns the value of the longhand property at progress
+// between from and to, the typed values of the property, as animations and
+// transitions do. Properties with a discrete animation type, and values
+// that can't be interpolated, like auto and a length, flip from from to to
+// at a progress of 0.5. Progress outside of [0,1] extrapolates.
+func Interpolate(property string, from, to Style, progress float64) (Style, error) {
+	m, ok := DefaultRegistry.Metadata(property)
+	switch {
+	case !ok:
+		return Style{}, fmt.Errorf("unknown property %s", property)
+	case m.IsShorthand():
+		return Style{}, fmt.Errorf("%s is a shorthand property", property)
+	case m.AnimationType == AnimationNotAnimatable:
+		return Style{}, fmt.Errorf("%s is not animatable", property)
+	}
+	return interpolateStyle(property, from, to, progress), nil
+}
+
+// interpolateStyle interpolates the property name by its animation type.
+func interpolateStyle(name string, from, to Style, p float64) Style {
+	if m, _ := DefaultRegistry.Metadata(name); m.AnimationType == AnimationByComputedValue || m.AnimationType == AnimationRepeatableList {
+		if v, ok := interpolate(from.Value, to.Value, p); ok {
+			if p < 0 || p > 1 {
+				// only overshooting easings leave the range of the endpoints
+				min, max := valueRange(name)
+				v = clampValue(v, min, max)
+			}
+			unit := from.unit
+			if to.unit != unit {
+				unit = UnitNone
+			}
+			return Style{Value: v, unit: unit}
+		}
+	}
+	return discrete(from, to, p).(Style)
+}
+
+// valueRange returns the range of the numbers, lengths and percentages the
+// property name accepts, from the ranges in its grammar. Alpha values are
+// between 0 and 1. Min is greater than max if it accepts none.
+func valueRange(name string) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	visited := make(map[*grammarNode]bool)
+	var walk func(n *grammarNode)
+	walk = func(n *grammarNode) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		if n.kind != typeNode {
+			for _, c := range n.children {
+				walk(c)
+			}
+			return
+		}
+		switch n.name {
+		case "alpha-value":
+			min, max = math.Min(min, 0), math.Max(max, 1)
+		case "number", "integer", "length", "percentage", "length-percentage":
+			if !n.hasRange {
+				min, max = math.Inf(-1), math.Inf(1)
+			}
+			min, max = math.Min(min, n.min), math.Max(max, n.max)
+		default:
+			if ref, _ := n.reference(); ref != nil {
+				walk(ref.root)
+			}
+		}
+	}
+	if g := propertyGrammars[name]; g != nil {
+		walk(g.root)
+	}
+	return min, max
+}
+
+// clampValue clamps numbers and lengths to the range from min to max.
+// Lengths with math functions are wrapped in min() and max().
+func clampValue(v interface{}, min, max float64) interface{} {
+	if min > max {
+		return v
+	}
+	switch v := v.(type) {
+	case float64:
+		return clamp(v, min, max)
+	case int:
+		return int(clamp(float64(v), min, max))
+	case Length:
+		return clampLength(v, min, max)
+	case [2]Length:
+		return [2]Length{clampLength(v[0], min, max), clampLength(v[1], min, max)}
+	}
+	return v
+}
+
+func clampLength(l Length, min, max float64) Length {
+	switch {
+	case l.Keyword != "":
+		return l
+	case l.Math == nil:
+		l.Value = clamp(l.Value, min, max)
+		return l
+	}
+	expr := l.String()
+	if !math.IsInf(min, -1) {
+		expr = fmt.Sprintf("max(%spx, %s)", formatNumber(min), expr)
+	}
+	if !math.IsInf(max, 1) {
+		expr = fmt.Sprintf("min(%spx, %s)", formatNumber(max), expr)
+	}
+	if values, err := ParseValue(expr); err == nil {
+		return lengthOf(values[0])
+	}
+	return l
+}
+
+// interpolate returns the value at progress p between the typed values from
+// and to, or false if they can't be interpolated and animate discretely.
+func interpolate(from, to interface{}, p float64) (interface{}, bool) {
+	switch a := from.(type) {
+	case float64:
+		if b, ok := to.(float64); ok {
+			return lerp(a, b, p), true
+		}
+	case int:
+		if b, ok := to.(int); ok {
+			return toInteger(lerp(float64(a), float64(b), p)), true
+		}
+	case Length:
+		if b, ok := to.(Length); ok {
+			return interpolateLength(a, b, p)
+		}
+	case [2]Length:
+		if b, ok := to.([2]Length); ok {
+			if l, ok := interpolateLengths(a[:], b[:], p); ok {
+				return [2]Length{l[0], l[1]}, true
+			}
+		}
+	case Color:
+		if b, ok := to.(Color); ok {
+			return interpolateColor(a, b, p)
+		}
+	case []Shadow:
+		if b, ok := to.([]Shadow); ok {
+			return interpolateShadows(a, b, p)
+		}
+	case Transform:
+		if b, ok := to.(Transform); ok {
+			return interpolateTransform(a, b, p)
+		}
+	case Translate:
+		if b, ok := to.(Translate); ok {
+			return interpolateTranslate(a, b, p)
+		}
+	case Rotate:
+		if b, ok := to.(Rotate); ok {
+			return interpolateRotate(a, b, p), true
+		}
+	case Scale:
+		if b, ok := to.(Scale); ok {
+			return interpolateScale(a, b, p), true
+		}
+	case TransformOrigin:
+		if b, ok := to.(TransformOrigin); ok {
+			if l, ok := interpolateLengths([]Length{a.X, a.Y, a.Z}, []Length{b.X, b.Y, b.Z}, p); ok {
+				return TransformOrigin{X: l[0], Y: l[1], Z: l[2]}, true
+			}
+		}
+	case Position:
+		if b, ok := to.(Position); ok && a.XEdge == b.XEdge && a.YEdge == b.YEdge {
+			if l, ok := interpolateLengths([]Length{a.X, a.Y}, []Length{b.X, b.Y}, p); ok {
+				return Position{XEdge: a.XEdge, X: l[0], YEdge: a.YEdge, Y: l[1]}, true
+			}
+		}
+	case []Position:
+		if b, ok := to.([]Position); ok {
+			items, ok := interpolateList(len(a), len(b), func(i, j int) (interface{}, bool) {
+				return interpolate(a[i], b[j], p)
+			})
+			positions := make([]Position, len(items))
+			for i, item := range items {
+				positions[i] = item.(Position)
+			}
+			return positions, ok
+		}
+	case BackgroundSize:
+		if b, ok := to.(BackgroundSize); ok {
+			if a.Keyword != "" || b.Keyword != "" {
+				return a, a.Keyword == b.Keyword
+			}
+			if l, ok := interpolateLengths([]Length{a.Width, a.Height}, []Length{b.Width, b.Height}, p); ok {
+				return BackgroundSize{Width: l[0], Height: l[1]}, true
+			}
+		}
+	case []BackgroundSize:
+		if b, ok := to.([]BackgroundSize); ok {
+			items, ok := interpolateList(len(a), len(b), func(i, j int) (interface{}, bool) {
+				return interpolate(a[i], b[j], p)
+			})
+			sizes := make([]BackgroundSize, len(items))
+			for i, item := range items {
+				sizes[i] = item.(BackgroundSize)
+			}
+			return sizes, ok
+		}
+	case FontWeight:
+		if b, ok := to.(FontWeight); ok && a.Keyword == "" && b.Keyword == "" {
+			return FontWeight{Weight: clamp(lerp(a.Weight, b.Weight, p), 1, 1000)}, true
+		}
+	case FontStyle:
+		if b, ok := to.(FontStyle); ok && a.Style == "oblique" && b.Style == "oblique" {
+			return FontStyle{Style: a.Style, Angle: lerp(a.Angle, b.Angle, p)}, true
+		}
+	case ZIndex:
+		if b, ok := to.(ZIndex); ok && !a.Auto && !b.Auto {
+			return ZIndex{Value: toInteger(lerp(float64(a.Value), float64(b.Value), p))}, true
+		}
+	case TextIndent:
+		if b, ok := to.(TextIndent); ok && a.Hanging == b.Hanging && a.EachLine == b.EachLine {
+			if l, ok := interpolateLength(a.Length, b.Length, p); ok {
+				return TextIndent{Length: l.(Length), Hanging: a.Hanging, EachLine: a.EachLine}, true
+			}
+		}
+	case ClipRect:
+		if b, ok := to.(ClipRect); ok && !a.Auto && !b.Auto {
+			if l, ok := interpolateLengths([]Length{a.Top, a.Right, a.Bottom, a.Left}, []Length{b.Top, b.Right, b.Bottom, b.Left}, p); ok {
+				return ClipRect{Top: l[0], Right: l[1], Bottom: l[2], Left: l[3]}, true
+			}
+		}
+	case BorderImageSlice:
+		if b, ok := to.(BorderImageSlice); ok && a.Fill == b.Fill {
+			if l, ok := interpolateLengths(a.Offsets[:], b.Offsets[:], p); ok {
+				o := BorderImageSlice{Fill: a.Fill}
+				copy(o.Offsets[:], l)
+				return o, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// interpolateLengths interpolates the lengths of a and b pairwise, and
+// returns false if any pair can't be interpolated.
+func interpolateLengths(a, b []Length, p float64) ([]Length, bool) {
+	lengths := make([]Length, len(a))
+	for i := range a {
+		l, ok := interpolateLength(a[i], b[i], p)
+		if !ok {
+			return nil, false
+		}
+		lengths[i] = l.(Length)
+	}
+	return lengths, true
+}
+
+// interpolateList interpolates repeatable lists of n and m items. Both
+// lists are repeated to the least common multiple of their lengths, and
+// item interpolates their items i and j.
+func interpolateList(n, m int, item func(i, j int) (interface{}, bool)) ([]interface{}, bool) {
+	if n == 0 || m == 0 {
+		return nil, false
+	}
+	gcd := func(a, b int) int {
+		for b != 0 {
+			a, b = b, a%b
+		}
+		return a
+	}
+	items := make([]interface{}, n*m/gcd(n, m))
+	for k := range items {
+		v, ok := item(k%n, k%m)
+		if !ok {
+			return nil, false
+		}
+		items[k] = v
+	}
+	return items, true
+}
+
+// discrete returns from before the halfway point and to after it.
+func discrete(from, to interface{}, p float64) interface{} {
+	if p < 0.5 {
+		return from
+	}
+	return to
+}
+
+func lerp(a, b, p float64) float64 {
+	return a + (b-a)*p
+}
+
+// interpolateLength interpolates lengths numerically. Lengths with
+// different units are combined with calc().
+func interpolateLength(a, b Length, p float64) (interface{}, bool) {
+	switch {
+	case a.Keyword != "" || b.Keyword != "":
+		// keywords like auto only interpolate with themselves
+		return a, a.Keyword == b.Keyword
+	case a.Math == nil && b.Math == nil && a.Unit == b.Unit:
+		return Length{Value: lerp(a.Value, b.Value, p), Unit: a.Unit}, true
+	case a.Math == nil && a.Unit == "" && a.Value == 0:
+		return interpolateLength(Length{Unit: b.Unit}, b, p)
+	case b.Math == nil && b.Unit == "" && b.Value == 0:
+		return interpolateLength(a, Length{Unit: a.Unit}, p)
+	}
+	values, err := ParseValue(fmt.Sprintf("calc(%s * %s + %s * %s)", a, formatNumber(1-p), b, formatNumber(p)))
+	if err != nil {
+		return nil, false
+	}
+	return lengthOf(values[0]), true
+}
+
+// interpolateColor interpolates colors with premultiplied alpha, in sRGB
+// if both are legacy sRGB colors and in the OKLab color space otherwise.
+// currentcolor animates discretely.
+func interpolateColor(a, b Color, p float64) (interface{}, bool) {
+	if a.CurrentColor || b.CurrentColor {
+		return nil, false
+	}
+	alpha := clamp(lerp(a.A, b.A, p), 0, 1)
+	if alpha == 0 {
+		return Color{Modern: a.Modern || b.Modern}, true
+	}
+	premultiplied := func(c1, c2 float64) float64 {
+		return lerp(c1*a.A, c2*b.A, p) / alpha
+	}
+	var c Color
+	if !a.Modern && !b.Modern {
+		c = Color{R: premultiplied(a.R, b.R), G: premultiplied(a.G, b.G), B: premultiplied(a.B, b.B)}
+	} else {
+		l1, a1, b1 := a.oklab()
+		l2, a2, b2 := b.oklab()
+		c = oklabColor(premultiplied(l1, l2), premultiplied(a1, a2), premultiplied(b1, b2))
+		c.Modern = true
+	}
+	c.R, c.G, c.B, c.A = clamp(c.R, 0, 1), clamp(c.G, 0, 1), clamp(c.B, 0, 1), alpha
+	return c, true
+}
+
+// interpolateTransform interpolates transform lists function by function
+// if they have the same functions, and through the decomposed matrices
+// otherwise. none is a list of identity functions.
+func interpolateTransform(a, b Transform, p float64) (interface{}, bool) {
+	switch {
+	case len(a.Functions) == 0 && len(b.Functions) == 0:
+		return a, true
+	case len(a.Functions) == 0:
+		a = identityTransform(b)
+	case len(b.Functions) == 0:
+		b = identityTransform(a)
+	}
+	if t, ok := interpolateFunctions(a, b, p); ok {
+		return t, true
+	}
+
+	unknown := Size{Width: Indefinite, Height: Indefinite}
+	ma, err := a.Matrix(unknown, defaultFontSize)
+	if err != nil {
+		return nil, false
+	}
+	mb, err := b.Matrix(unknown, defaultFontSize)
+	if err != nil {
+		return nil, false
+	}
+	da, ok := decompose(ma)
+	if !ok {
+		return nil, false
+	}
+	db, ok := decompose(mb)
+	if !ok {
+		return nil, false
+	}
+	m := da.interpolate(db, p).recompose()
+	if m.Is2D() {
+		affine := m.Affine()
+		return Transform{Functions: []TransformFunction{{Name: "matrix", Numbers: affine[:]}}}, true
+	}
+	return Transform{Functions: []TransformFunction{{Name: "matrix3d", Numbers: m[:]}}}, true
+}
+
+// interpolateTranslate interpolates translations, where none is a
+// translation by zero.
+func interpolateTranslate(a, b Translate, p float64) (interface{}, bool) {
+	if a.None && b.None {
+		return a, true
+	}
+	l, ok := interpolateLengths([]Length{a.X, a.Y, a.Z}, []Length{b.X, b.Y, b.Z}, p)
+	if !ok {
+		return nil, false
+	}
+	return Translate{X: l[0], Y: l[1], Z: l[2]}, true
+}
+
+// interpolateScale interpolates scales, where none is a scale by one.
+func interpolateScale(a, b Scale, p float64) Scale {
+	switch {
+	case a.None && b.None:
+		return a
+	case a.None:
+		a = Scale{X: 1, Y: 1, Z: 1}
+	case b.None:
+		b = Scale{X: 1, Y: 1, Z: 1}
+	}
+	return Scale{X: lerp(a.X, b.X, p), Y: lerp(a.Y, b.Y, p), Z: lerp(a.Z, b.Z, p)}
+}
+
+// interpolateRotate interpolates the angles of rotations around the same
+// axis, and the quaternions of rotations around different axes. none is a
+// rotation by zero around the other axis.
+func interpolateRotate(a, b Rotate, p float64) Rotate {
+	switch {
+	case a.None && b.None:
+		return a
+	case a.None:
+		a = Rotate{X: b.X, Y: b.Y, Z: b.Z}
+	case b.None:
+		b = Rotate{X: a.X, Y: a.Y, Z: a.Z}
+	}
+	ax, bx := normalize3([3]float64{a.X, a.Y, a.Z}), normalize3([3]float64{b.X, b.Y, b.Z})
+	if ax == bx {
+		return Rotate{X: a.X, Y: a.Y, Z: a.Z, Angle: lerp(a.Angle, b.Angle, p)}
+	}
+	quaternion := func(axis [3]float64, angle float64) [4]float64 {
+		sin, cos := math.Sincos(angle * math.Pi / 360)
+		return [4]float64{axis[0] * sin, axis[1] * sin, axis[2] * sin, cos}
+	}
+	q := slerp(quaternion(ax, a.Angle), quaternion(bx, b.Angle), p)
+	half := math.Acos(clamp(q[3], -1, 1))
+	if math.Sin(half) == 0 {
+		return Rotate{X: a.X, Y: a.Y, Z: a.Z}
+	}
+	axis := scale3([3]float64{q[0], q[1], q[2]}, 1/math.Sin(half))
+	return Rotate{X: axis[0], Y: axis[1], Z: axis[2], Angle: half * 360 / math.Pi}
+}
+
+// identityTransform returns the identity functions for the functions of t.
+func identityTransform(t Transform) Transform {
+	identity := Transform{Functions: make([]TransformFunction, len(t.Functions))}
+	for i, f := range t.Functions {
+		g := TransformFunction{Name: f.Name}
+		switch f.Name {
+		case "matrix", "matrix3d":
+			m := IdentityMatrix()
+			g.Name, g.Numbers = "matrix3d", m[:]
+		case "rotate3d":
+			g.Numbers, g.Angles = f.Numbers, []float64{0}
+		case "perspective":
+		default:
+			g.Lengths = make([]Length, len(f.Lengths))
+			g.Numbers = make([]float64, len(f.Numbers))
+			for j := range g.Numbers {
+				g.Numbers[j] = 1
+			}
+			g.Angles = make([]float64, len(f.Angles))
+		}
+		identity.Functions[i] = g
+	}
+	return identity
+}
+
+// interpolateFunctions interpolates the arguments of the functions of a and
+// b if they are the same functions, except for those that need matrices.
+func interpolateFunctions(a, b Transform, p float64) (Transform, bool) {
+	if len(a.Functions) != len(b.Functions) {
+		return Transform{}, false
+	}
+	t := Transform{Functions: make([]TransformFunction, len(a.Functions))}
+	for i, fa := range a.Functions {
+		fb := b.Functions[i]
+		switch {
+		case fa.Name != fb.Name, fa.Name == "matrix", fa.Name == "matrix3d",
+			len(fa.Lengths) != len(fb.Lengths), len(fa.Numbers) != len(fb.Numbers):
+			return Transform{}, false
+		case fa.Name == "rotate3d":
+			for j, n := range fa.Numbers {
+				if n != fb.Numbers[j] {
+					return Transform{}, false
+				}
+			}
+		}
+		f := TransformFunction{Name: fa.Name}
+		for j, l := range fa.Lengths {
+			v, ok := interpolateLength(l, fb.Lengths[j], p)
+			if !ok {
+				return Transform{}, false
+			}
+			f.Lengths = append(f.Lengths, v.(Length))
+		}
+		for j, n := range fa.Numbers {
+			f.Numbers = append(f.Numbers, lerp(n, fb.Numbers[j], p))
+		}
+		for j, angle := range fa.Angles {
+			f.Angles = append(f.Angles, lerp(angle, fb.Angles[j], p))
+		}
+		t.Functions[i] = f
+	}
+	return t, true
+}
+
+// decomposedMatrix is a 3D matrix decomposed into its components as
+// described in CSS Transforms Level 2.
+type decomposedMatrix struct {
+	translate   [3]float64
+	scale       [3]float64
+	skew        [3]float64
+	perspective [4]float64
+	quaternion  [4]float64
+}
+
+// decompose decomposes m, and returns false if m is singular.
+func decompose(m Matrix) (decomposedMatrix, bool) {
+	var d decomposedMatrix
+	if m[15] == 0 {
+		return d, false
+	}
+	for i := range m {
+		m[i] /= m[15]
+	}
+	perspective := m
+	perspective[3], perspective[7], perspective[11], perspective[15] = 0, 0, 0, 1
+	inverse, ok := perspective.inverse()
+	if !ok {
+		return d, false
+	}
+	d.perspective = [4]float64{0, 0, 0, 1}
+	if m[3] != 0 || m[7] != 0 || m[11] != 0 {
+		// the right hand side times the transposed inverse
+		for i := 0; i < 4; i++ {
+			d.perspective[i] = 0
+			for j := 0; j < 4; j++ {
+				d.perspective[i] += inverse[i*4+j] * m[j*4+3]
+			}
+		}
+	}
+	d.translate = [3]float64{m[12], m[13], m[14]}
+
+	var rows [3][3]float64
+	for i := range rows {
+		rows[i] = [3]float64{m[i*4], m[i*4+1], m[i*4+2]}
+	}
+	d.scale[0] = length3(rows[0])
+	rows[0] = scale3(rows[0], 1/d.scale[0])
+	d.skew[0] = dot3(rows[0], rows[1])
+	rows[1] = combine3(rows[1], rows[0], 1, -d.skew[0])
+	d.scale[1] = length3(rows[1])
+	rows[1] = scale3(rows[1], 1/d.scale[1])
+	d.skew[0] /= d.scale[1]
+	d.skew[1] = dot3(rows[0], rows[2])
+	rows[2] = combine3(rows[2], rows[0], 1, -d.skew[1])
+	d.skew[2] = dot3(rows[1], rows[2])
+	rows[2] = combine3(rows[2], rows[1], 1, -d.skew[2])
+	d.scale[2] = length3(rows[2])
+	rows[2] = scale3(rows[2], 1/d.scale[2])
+	d.skew[1] /= d.scale[2]
+	d.skew[2] /= d.scale[2]
+
+	// flip the coordinate system if it is inverted
+	if dot3(rows[0], cross3(rows[1], rows[2])) < 0 {
+		for i := range rows {
+			d.scale[i] = -d.scale[i]
+			rows[i] = scale3(rows[i], -1)
+		}
+	}
+
+	// rows are the columns of the rotation matrix r, whose quaternion is
+	// computed from its largest diagonal element to stay accurate for
+	// rotations by half a turn
+	r := func(i, j int) float64 { return rows[j][i] }
+	q := &d.quaternion
+	switch trace := r(0, 0) + r(1, 1) + r(2, 2); {
+	case trace > 0:
+		s := 2 * math.Sqrt(trace+1)
+		q[0], q[1], q[2], q[3] = (r(2, 1)-r(1, 2))/s, (r(0, 2)-r(2, 0))/s, (r(1, 0)-r(0, 1))/s, s/4
+	case r(0, 0) > r(1, 1) && r(0, 0) > r(2, 2):
+		s := 2 * math.Sqrt(1+r(0, 0)-r(1, 1)-r(2, 2))
+		q[0], q[1], q[2], q[3] = s/4, (r(0, 1)+r(1, 0))/s, (r(0, 2)+r(2, 0))/s, (r(2, 1)-r(1, 2))/s
+	case r(1, 1) > r(2, 2):
+		s := 2 * math.Sqrt(1+r(1, 1)-r(0, 0)-r(2, 2))
+		q[0], q[1], q[2], q[3] = (r(0, 1)+r(1, 0))/s, s/4, (r(1, 2)+r(2, 1))/s, (r(0, 2)-r(2, 0))/s
+	default:
+		s := 2 * math.Sqrt(1+r(2, 2)-r(0, 0)-r(1, 1))
+		q[0], q[1], q[2], q[3] = (r(0, 2)+r(2, 0))/s, (r(1, 2)+r(2, 1))/s, s/4, (r(1, 0)-r(0, 1))/s
+	}
+	return d, true
+}
+
+// interpolate interpolates the components of d and e linearly, and their
+// rotations with spherical linear interpolation along the shorter arc.
+func (d decomposedMatrix) interpolate(e decomposedMatrix, p float64) decomposedMatrix {
+	var r decomposedMatrix
+	for i := 0; i < 3; i++ {
+		r.translate[i] = lerp(d.translate[i], e.translate[i], p)
+		r.scale[i] = lerp(d.scale[i], e.scale[i], p)
+		r.skew[i] = lerp(d.skew[i], e.skew[i], p)
+	}
+	for i := 0; i < 4; i++ {
+		r.perspective[i] = lerp(d.perspective[i], e.perspective[i], p)
+	}
+
+	r.quaternion = slerp(d.quaternion, e.quaternion, p)
+	return r
+}
+
+// slerp interpolates the unit quaternions a and b along the shorter arc.
+func slerp(a, b [4]float64, p float64) [4]float64 {
+	product := 0.0
+	for i := range a {
+		product += a[i] * b[i]
+	}
+	// q and -q are the same rotation
+	if product < 0 {
+		for i := range b {
+			b[i] = -b[i]
+		}
+		product = -product
+	}
+	product = math.Min(product, 1)
+	if product == 1 {
+		return a
+	}
+	theta := math.Acos(product)
+	w := math.Sin(p*theta) / math.Sqrt(1-product*product)
+	var q [4]float64
+	for i := range q {
+		q[i] = a[i]*(math.Cos(p*theta)-product*w) + b[i]*w
+	}
+	return q
+}
+
+// recompose returns the matrix of the components of d.
+func (d decomposedMatrix) recompose() Matrix {
+	m := IdentityMatrix()
+	m[3], m[7], m[11], m[15] = d.perspective[0], d.perspective[1], d.perspective[2], d.perspective[3]
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			m[12+i] += d.translate[j] * m[j*4+i]
+		}
+	}
+
+	x, y, z, w := d.quaternion[0], d.quaternion[1], d.quaternion[2], d.quaternion[3]
+	rotation := IdentityMatrix()
+	rotation[0], rotation[1], rotation[2] = 1-2*(y*y+z*z), 2*(x*y+z*w), 2*(x*z-y*w)
+	rotation[4], rotation[5], rotation[6] = 2*(x*y-z*w), 1-2*(x*x+z*z), 2*(y*z+x*w)
+	rotation[8], rotation[9], rotation[10] = 2*(x*z+y*w), 2*(y*z-x*w), 1-2*(x*x+y*y)
+	m = m.Multiply(rotation)
+
+	if d.skew[2] != 0 {
+		skew := IdentityMatrix()
+		skew[9] = d.skew[2]
+		m = m.Multiply(skew)
+	}
+	if d.skew[1] != 0 {
+		skew := IdentityMatrix()
+		skew[8] = d.skew[1]
+		m = m.Multiply(skew)
+	}
+	if d.skew[0] != 0 {
+		skew := IdentityMatrix()
+		skew[4] = d.skew[0]
+		m = m.Multiply(skew)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			m[i*4+j] *= d.scale[i]
+		}
+	}
+	return m
+}
+
+// inverse returns the inverse of m, or false if m is singular.
+func (m Matrix) inverse() (Matrix, bool) {
+	var inv Matrix
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+
+	det := m[0]*inv[0] + m[1]*inv[4] + m[2]*inv[8] + m[3]*inv[12]
+	if det == 0 {
+		return Matrix{}, false
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
+
+func length3(v [3]float64) float64 {
+	return math.Sqrt(dot3(v, v))
+}
+
+func normalize3(v [3]float64) [3]float64 {
+	if l := length3(v); l != 0 {
+		return scale3(v, 1/l)
+	}
+	return v
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func scale3(v [3]float64, s float64) [3]float64 {
+	return [3]float64{v[0] * s, v[1] * s, v[2] * s}
+}
+
+func combine3(a, b [3]float64, sa, sb float64) [3]float64 {
+	return [3]float64{a[0]*sa + b[0]*sb, a[1]*sa + b[1]*sb, a[2]*sa + b[2]*sb}
+}
+
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
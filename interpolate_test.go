@@ -0,0 +1,165 @@
+package css
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to string
+		progress float64
+		expected string
+	}{
+		{"width", "10px", "20px", 0.25, "12.5px"},
+		{"width", "0", "2em", 0.5, "1em"},
+		{"width", "10px", "50%", 0.25, "calc(10px * 0.75 + 50% * 0.25)"},
+		{"width", "auto", "10px", 0.25, "auto"},
+		{"width", "auto", "10px", 0.5, "10px"},
+		{"background-color", "red", "blue", 0.5, "#800080"},
+		{"background-color", "hsl(0 100% 50%)", "rgb(0 0 255)", 0.5, "#800080"},
+		{"background-color", "red", "color(srgb 0 0 1)", 0.5, "#8c53a2"},
+		{"background-color", "transparent", "red", 0.5, "rgba(255, 0, 0, 0.5)"},
+		{"background-color", "red", "currentcolor", 0.4, "#ff0000"},
+		{"transform", "none", "rotate(90deg) scale(2)", 0.5, "rotate(45deg) scale(1.5, 1.5)"},
+		{"transform", "translateX(10px)", "translateX(2em)", 0.5, "translateX(calc(10px * 0.5 + 2em * 0.5))"},
+		{"transform", "rotate(0deg)", "rotate(90deg) scale(2)", 0.5, "matrix(1.06066, 1.06066, -1.06066, 1.06066, 0, 0)"},
+		{"transform", "matrix(1, 0, 0, 1, 0, 0)", "matrix(0, 1, -1, 0, 10, 20)", 0.5, "matrix(0.707107, 0.707107, -0.707107, 0.707107, 5, 10)"},
+		{"transform", "none", "rotateY(90deg)", 0.5, "rotateY(45deg)"},
+		{"transform", "none", "matrix3d(-0.5, 0, -0.866025, 0, 0, 1, 0, 0, 0.866025, 0, -0.5, 0, 0, 0, 0, 1)", 0.5, "matrix3d(0.5, 0, -0.866025, 0, 0, 1, 0, 0, 0.866025, 0, 0.5, 0, 0, 0, 0, 1)"},
+		{"transform", "translate(50%)", "rotate(1deg)", 0.5, "rotate(1deg)"},
+		{"display", "block", "none", 0.49, "block"},
+		// overshooting easings are clamped to the range of the property
+		{"opacity", "0", "1", 1.5, "1"},
+		{"opacity", "0", "1", -0.5, "0"},
+		{"width", "10px", "20px", -2, "0px"},
+		{"width", "10px", "50%", -2, "max(0px, calc(10px * 3 + 50% * -2))"},
+		{"margin-top", "10px", "20px", -2, "-10px"},
+		{"line-height", "1", "2", -2, "0"},
+		{"flex-grow", "1", "2", -2, "0"},
+		{"border-spacing", "1px 2px", "2px 4px", -2, "[0px 0px]"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.from+" "+tt.to, func(t *testing.T) {
+			from, err := animatedStyle(tt.name, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			to, err := animatedStyle(tt.name, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			style := interpolateStyle(tt.name, from, to, tt.progress)
+			if tf, ok := style.Value.(Transform); ok {
+				style.Value = roundTransform(tf)
+			}
+			assert.Equal(t, tt.expected, style.String())
+		})
+	}
+}
+
+func TestInterpolateTypes(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{"translate", "none", "10px 20px", "5px 10px"},
+		{"rotate", "none", "z 90deg", "45deg"},
+		{"rotate", "x 90deg", "y 90deg", "0.707107 0.707107 0 70.5288deg"},
+		{"scale", "none", "2 3", "1.5 2"},
+		{"transform-origin", "0 0", "100% 10px", "50% 5px 0"},
+		{"background-position", "0 0, left 10px top", "100% 10px", "[50% 5px calc(10px * 0.5 + 100% * 0.5) calc(0% * 0.5 + 10px * 0.5)]"},
+		{"background-position", "left 10px top", "right 10px top", "[right 10px top 0%]"},
+		{"background-size", "10px auto, cover", "20px auto, cover", "[15px cover]"},
+		{"font-weight", "bold", "100", "400"},
+		{"font-style", "oblique 10deg", "oblique 20deg", "oblique 15deg"},
+		{"z-index", "1", "4", "3"},
+		{"z-index", "auto", "4", "4"},
+		{"text-indent", "10px hanging", "30px hanging", "20px hanging"},
+		{"clip", "rect(0, 10px, 10px, 0)", "rect(10px, 20px, 20px, 10px)", "rect(5px, 15px, 15px, 5px)"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.from+" "+tt.to, func(t *testing.T) {
+			from, err := CSSStyle(tt.name, map[string]string{tt.name: tt.from})
+			if err != nil {
+				t.Fatal(err)
+			}
+			to, err := CSSStyle(tt.name, map[string]string{tt.name: tt.to})
+			if err != nil {
+				t.Fatal(err)
+			}
+			style, err := Interpolate(tt.name, from, to, 0.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r, ok := style.Value.(Rotate); ok {
+				r.X, r.Y, r.Z, r.Angle = round6(r.X), round6(r.Y), round6(r.Z), round6(r.Angle)
+				style.Value = r
+			}
+			assert.Equal(t, tt.expected, style.String())
+		})
+	}
+
+	for _, name := range []string{"margin", "animation-name", "-x-unknown"} {
+		_, err := Interpolate(name, Style{}, Style{}, 0.5)
+		assert.Error(t, err, name)
+	}
+}
+
+// roundTransform rounds the arguments of t to 6 significant digits.
+func roundTransform(t Transform) Transform {
+	for _, f := range t.Functions {
+		for i, n := range f.Numbers {
+			f.Numbers[i] = round6(n)
+		}
+		for i, a := range f.Angles {
+			f.Angles[i] = round6(a)
+		}
+	}
+	return t
+}
+
+func round6(n float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(n, 'g', 6, 64), 64)
+	// avoid negative zeros
+	return rounded + 0
+}
+
+func TestDecompose(t *testing.T) {
+	for _, value := range []string{
+		"rotate(30deg)",
+		"rotate(180deg)",
+		"rotate(30deg) scale(1, -1)",
+		"skew(20deg, 10deg) scale(2, -3)",
+		"rotate3d(1, 2, 3, 50deg) translate(10px, 5px)",
+		"rotateX(179deg) rotateY(200deg)",
+		"skewX(30deg) rotateX(20deg) scaleZ(2)",
+		"perspective(100px) rotateY(30deg) translate3d(1px, 2px, 3px)",
+		"matrix3d(1, 0, 0, 0.01, 0, 1, 0, 0.02, 0, 0, 1, 0, 5, 6, 7, 1)",
+	} {
+		style, err := CSSStyle("transform", map[string]string{"transform": value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := style.Value.(Transform).Matrix(Size{100, 100}, defaultFontSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, ok := decompose(m)
+		if !assert.True(t, ok, value) {
+			continue
+		}
+		recomposed := d.recompose()
+		for i := range m {
+			m[i] /= m[15]
+		}
+		assert.InDeltaSlice(t, m[:], recomposed[:], 1e-9, value)
+	}
+
+	_, ok := decompose(Matrix{})
+	assert.False(t, ok)
+}
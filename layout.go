@@ -0,0 +1,211 @@
+package css
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Size is the size of a box in pixels.
+type Size struct {
+	Width, Height float64
+}
+
+// Rect is the position and size of a box in pixels.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Indefinite is the size of a container whose size depends on its
+// content, like the height of a box with height: auto.
+const Indefinite = -1.0
+
+// Layout is the result of laying out the children of a container: the
+// border boxes of the children relative to the content box of the
+// container, in the order of the children, and the size of the content box.
+type Layout struct {
+	Size  Size
+	Boxes []Rect
+}
+
+// defaultFontSize is the font size used for em units if a box doesn't set
+// font-size in pixels.
+const defaultFontSize = 16
+
+// layoutBox is the part of the computed style of a box that layout
+// algorithms use, resolved to pixels.
+type layoutBox struct {
+	style    map[string]string
+	fontSize float64
+	// margin, padding and border are in top, right, bottom, left order.
+	margin     [4]float64
+	autoMargin [4]bool
+	padding    [4]float64
+	border     [4]float64
+	borderBox  bool
+}
+
+// newLayoutBox resolves the box model properties of style. Percentages of
+// margins and paddings are relative to the width of the containing block.
+func newLayoutBox(style map[string]string, containingWidth float64) (*layoutBox, error) {
+	style, err := expandStyle(style)
+	if err != nil {
+		return nil, err
+	}
+	b := &layoutBox{style: style, fontSize: defaultFontSize}
+	if size, err := b.value("font-size"); err != nil {
+		return nil, err
+	} else if l, ok := size.(Length); ok && l.Keyword == "" {
+		if px, ok := l.Pixels(defaultFontSize, defaultFontSize); ok {
+			b.fontSize = px
+		}
+	}
+	for i, side := range physicalSides {
+		margin, err := b.length("margin-"+side, containingWidth)
+		if err != nil {
+			return nil, err
+		}
+		b.margin[i] = margin.px
+		b.autoMargin[i] = margin.length.Is("auto")
+		padding, err := b.length("padding-"+side, containingWidth)
+		if err != nil {
+			return nil, err
+		}
+		b.padding[i] = padding.px
+		if borderStyle, _ := b.value("border-" + side + "-style"); borderStyle == "none" || borderStyle == "hidden" || borderStyle == nil {
+			continue
+		}
+		border, err := b.length("border-"+side+"-width", -1)
+		if err != nil {
+			return nil, err
+		}
+		b.border[i] = border.px
+		if !border.definite() {
+			b.border[i] = lineWidths[border.length.Keyword]
+		}
+	}
+	sizing, err := b.value("box-sizing")
+	b.borderBox = sizing == "border-box"
+	return b, err
+}
+
+// lineWidths are the widths of the <line-width> keywords in pixels.
+// The initial value is medium.
+var lineWidths = map[string]float64{"": 3, "thin": 1, "medium": 3, "thick": 5}
+
+// expandStyle returns style with its shorthands expanded. Longhands
+// override the shorthands that set them, and shorthands that set fewer
+// longhands, like border-top, override those that set more, like border.
+func expandStyle(style map[string]string) (map[string]string, error) {
+	var expansions []map[string]string
+	for name, value := range style {
+		if _, ok := shorthandExpanders[name]; !ok {
+			continue
+		}
+		longhands, err := ExpandShorthand(name, value)
+		if err != nil {
+			return nil, err
+		}
+		expansions = append(expansions, longhands)
+	}
+	sort.Slice(expansions, func(i, j int) bool {
+		return len(expansions[i]) > len(expansions[j])
+	})
+
+	expanded := make(map[string]string, len(style))
+	for _, longhands := range expansions {
+		for longhand, v := range longhands {
+			expanded[longhand] = v
+		}
+	}
+	for name, value := range style {
+		if _, ok := shorthandExpanders[name]; !ok {
+			expanded[name] = value
+		}
+	}
+	return expanded, nil
+}
+
+// value returns the typed value of the property name, or nil if the box
+// doesn't set it or sets it to a CSS-wide keyword.
+func (b *layoutBox) value(name string) (interface{}, error) {
+	if _, ok := b.style[name]; !ok {
+		return nil, nil
+	}
+	s, err := DefaultRegistry.CSSStyle(name, b.style)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if _, ok := s.Value.(CSSWideKeyword); ok {
+		return nil, nil
+	}
+	return s.Value, nil
+}
+
+// resolvedLength is a length resolved to pixels. If the length isn't
+// definite, keyword is set to its keyword or to "auto".
+type resolvedLength struct {
+	px      float64
+	keyword string
+	length  Length
+}
+
+func (l resolvedLength) definite() bool {
+	return l.keyword == ""
+}
+
+// length resolves the length property name, with percentages relative to
+// percentBase.
+func (b *layoutBox) length(name string, percentBase float64) (resolvedLength, error) {
+	v, err := b.value(name)
+	if err != nil {
+		return resolvedLength{}, err
+	}
+	l, ok := v.(Length)
+	if !ok {
+		return resolvedLength{keyword: "auto"}, nil
+	}
+	if px, ok := l.Pixels(percentBase, b.fontSize); ok {
+		return resolvedLength{px: px, length: l}, nil
+	}
+	if l.Keyword != "" {
+		return resolvedLength{keyword: l.Keyword, length: l}, nil
+	}
+	return resolvedLength{keyword: "auto", length: l}, nil
+}
+
+// edges returns the sum of the paddings and borders of the sides i and j.
+func (b *layoutBox) edges(i, j int) float64 {
+	return b.padding[i] + b.padding[j] + b.border[i] + b.border[j]
+}
+
+// margins returns the sum of the margins of the sides i and j.
+func (b *layoutBox) margins(i, j int) float64 {
+	return b.margin[i] + b.margin[j]
+}
+
+// size resolves the size property name, like width or min-height, to a
+// border box size. edges are the paddings and borders along its axis.
+func (b *layoutBox) size(name string, percentBase, edges float64) (resolvedLength, error) {
+	l, err := b.length(name, percentBase)
+	if err != nil || !l.definite() {
+		return l, err
+	}
+	if !b.borderBox {
+		l.px += edges
+	}
+	if l.px < edges {
+		l.px = edges
+	}
+	return l, nil
+}
+
+// clampSize clamps size between the resolved min and max sizes.
+func clampSize(size float64, min, max resolvedLength) float64 {
+	if max.definite() && size > max.px {
+		size = max.px
+	}
+	if min.definite() && size < min.px {
+		size = min.px
+	}
+	return size
+}
@@ -0,0 +1,66 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLengthPixels(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected float64
+		ok       bool
+	}{
+		{"10px", 10, true},
+		{"1in", 96, true},
+		{"12pt", 16, true},
+		{"2em", 32, true},
+		{"50%", 100, true},
+		{"0", 0, true},
+		{"calc(50% - 2em)", 68, true},
+		{"max(10px, 1rem)", 16, true},
+		{"10vw", 0, false},
+		{"auto", 0, false},
+		{"3", 0, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			values, err := ParseValue(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			px, ok := lengthOf(values[0]).Pixels(200, 16)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.expected, px, 1e-9)
+		})
+	}
+
+	_, ok := percent(50).Pixels(-1, 16)
+	assert.False(t, ok)
+}
+
+func TestLayoutBox(t *testing.T) {
+	b, err := newLayoutBox(map[string]string{
+		"font-size":     "10px",
+		"margin":        "1em auto 10%",
+		"padding":       "2px",
+		"padding-left":  "0",
+		"border":        "thin solid",
+		"border-bottom": "none",
+		"border-right":  "dashed",
+		"box-sizing":    "border-box",
+	}, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [4]float64{10, 0, 20, 0}, b.margin)
+	assert.Equal(t, [4]bool{false, true, false, true}, b.autoMargin)
+	assert.Equal(t, [4]float64{2, 2, 2, 0}, b.padding)
+	assert.Equal(t, [4]float64{1, 3, 0, 1}, b.border)
+	assert.True(t, b.borderBox)
+
+	_, err = newLayoutBox(map[string]string{"margin": "1px 2px 3px 4px 5px"}, 200)
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package css
+
+import (
+	"math"
+	"strings"
+)
+
+// Length is a typed <length>, <percentage> or <number>, or a keyword like
+// auto that can take their place.
+type Length struct {
+	Value float64
+	// Unit is the lower case unit, "%" for percentages and empty for numbers.
+	Unit string
+	// Keyword is set instead of Value and Unit for keywords like "auto".
+	Keyword string
+	// Math is set instead of Value and Unit for functions like calc() or
+	// fit-content().
+	Math *ComponentValue
+}
+
+// lengthOf converts a numeric value, math function or keyword to a Length.
+func lengthOf(v ComponentValue) Length {
+	switch v.Type {
+	case PercentageValue:
+		return Length{Value: v.Number, Unit: "%"}
+	case DimensionValue:
+		return Length{Value: v.Number, Unit: v.Unit}
+	case IdentValue:
+		return Length{Keyword: strings.ToLower(v.Text)}
+	case FunctionValue:
+		v.Production = ""
+		return Length{Math: &v}
+	}
+	return Length{Value: v.Number}
+}
+
+func percent(n float64) Length {
+	return Length{Value: n, Unit: "%"}
+}
+
+// Is reports whether l is the keyword k.
+func (l Length) Is(k string) bool {
+	return l.Keyword == k
+}
+
+// IsPercentage reports whether l is a percentage.
+func (l Length) IsPercentage() bool {
+	return l.Unit == "%" && l.Keyword == "" && l.Math == nil
+}
+
+func (l Length) String() string {
+	switch {
+	case l.Keyword != "":
+		return l.Keyword
+	case l.Math != nil:
+		return l.Math.String()
+	}
+	return formatNumber(l.Value) + l.Unit
+}
+
+// pixelsPerUnit are the sizes of absolute length units in pixels, and of
+// font relative units in em.
+var (
+	pixelsPerUnit = map[string]float64{
+		"px": 1, "in": 96, "cm": 96 / 2.54, "mm": 96 / 25.4, "q": 96 / 101.6, "pt": 96.0 / 72, "pc": 16,
+	}
+	emsPerUnit = map[string]float64{"em": 1, "rem": 1, "ex": 0.5, "ch": 0.5, "cap": 0.7, "ic": 1, "lh": 1.2, "rlh": 1.2}
+)
+
+// Pixels returns l in pixels. Percentages are relative to percentBase and
+// font relative units to fontSize, a negative percentBase means
+// percentages can't be resolved. It returns false for keywords and for
+// values it can't resolve, like viewport units.
+func (l Length) Pixels(percentBase, fontSize float64) (float64, bool) {
+	if l.Math != nil {
+		return evalMath(*l.Math, func(v ComponentValue) (float64, bool) {
+			return lengthOf(v).Pixels(percentBase, fontSize)
+		})
+	}
+	switch {
+	case l.Keyword != "":
+		return 0, false
+	case l.Unit == "%":
+		return l.Value * percentBase / 100, percentBase >= 0
+	case l.Unit == "":
+		// only zero is a valid unitless length
+		return 0, l.Value == 0
+	case pixelsPerUnit[l.Unit] != 0:
+		return l.Value * pixelsPerUnit[l.Unit], true
+	case emsPerUnit[l.Unit] != 0:
+		return l.Value * emsPerUnit[l.Unit] * fontSize, true
+	}
+	return 0, false
+}
+
+// angleDegrees returns an <angle> in degrees.
+func angleDegrees(v ComponentValue) float64 {
+	switch v.Unit {
+	case "rad":
+		return v.Number * 180 / math.Pi
+	case "grad":
+		return v.Number * 0.9
+	case "turn":
+		return v.Number * 360
+	}
+	return v.Number
+}
+
+// Position is a typed <position>, normalized to offsets from the left or
+// right and the top or bottom edge. Center is 50% from the left or top.
+type Position struct {
+	// XEdge is "left" or "right".
+	XEdge string
+	X     Length
+	// YEdge is "top" or "bottom".
+	YEdge string
+	Y     Length
+}
+
+// centerPosition is the position of a lone center keyword.
+var centerPosition = Position{XEdge: "left", X: percent(50), YEdge: "top", Y: percent(50)}
+
+// positionOf converts a value that matched <position> to a Position.
+func positionOf(values []ComponentValue) Position {
+	p := centerPosition
+	if len(values) <= 2 {
+		if len(values) == 2 && (isVerticalEdge(values[0]) || values[1].Is("left") || values[1].Is("right")) {
+			values = []ComponentValue{values[1], values[0]}
+		}
+		for i, v := range values {
+			switch {
+			case v.Type != IdentValue && i == 0:
+				p.X = lengthOf(v)
+			case v.Type != IdentValue:
+				p.Y = lengthOf(v)
+			default:
+				p.setEdge(strings.ToLower(v.Text), percent(0))
+			}
+		}
+		return p
+	}
+
+	// three and four value syntax, with offsets after the edge keywords
+	for i := 0; i < len(values); i++ {
+		edge, offset := strings.ToLower(values[i].Text), percent(0)
+		if i+1 < len(values) && values[i+1].Type != IdentValue {
+			offset = lengthOf(values[i+1])
+			i++
+		}
+		p.setEdge(edge, offset)
+	}
+	return p
+}
+
+func isVerticalEdge(v ComponentValue) bool {
+	return v.Is("top") || v.Is("bottom")
+}
+
+func (p *Position) setEdge(edge string, offset Length) {
+	switch edge {
+	case "left", "right":
+		p.XEdge, p.X = edge, offset
+	case "top", "bottom":
+		p.YEdge, p.Y = edge, offset
+	}
+}
+
+func (p Position) String() string {
+	if p.XEdge == "left" && p.YEdge == "top" {
+		return p.X.String() + " " + p.Y.String()
+	}
+	return p.XEdge + " " + p.X.String() + " " + p.YEdge + " " + p.Y.String()
+}
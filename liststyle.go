@@ -0,0 +1,88 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ListStyleType is the typed value of the list-style-type property. Use
+// CounterStyles.Marker to generate the marker of a list item.
+type ListStyleType struct {
+	// Name is the name of a counter style, or "none".
+	Name string
+	// Text is the marker if it is a string instead of a counter style.
+	Text string
+	// Symbols is the anonymous counter style of a symbols() function.
+	Symbols *CounterStyle
+}
+
+func (t ListStyleType) String() string {
+	switch {
+	case t.Symbols != nil:
+		return t.Symbols.symbolsFunction()
+	case t.Name != "":
+		return t.Name
+	}
+	return strconv.Quote(t.Text)
+}
+
+// ListStyle is the typed value of the list-style shorthand.
+type ListStyle struct {
+	Position string
+	Image    Image
+	Type     ListStyleType
+}
+
+func initialListStyle() ListStyle {
+	return ListStyle{Position: "outside", Type: ListStyleType{Name: "disc"}}
+}
+
+func listStyleTypeOf(v ComponentValue) ListStyleType {
+	switch v.Type {
+	case StringValue:
+		return ListStyleType{Text: v.Text}
+	case FunctionValue:
+		return ListStyleType{Symbols: symbolsCounterStyle(v)}
+	}
+	// predefined names are case-insensitive, custom ones aren't
+	name := strings.ToLower(v.Text)
+	if name != "none" && predefinedCounterStyles[name] == nil {
+		name = v.Text
+	}
+	return ListStyleType{Name: name}
+}
+
+func listStyle(values []ComponentValue) interface{} {
+	l := initialListStyle()
+	nones, typeSet := 0, false
+	for _, v := range values {
+		switch {
+		case v.Is("none"):
+			// none can be either the image or the type
+			nones++
+		case v.Production == "list-style-position":
+			l.Position = strings.ToLower(v.Text)
+		case v.Production == "list-style-image":
+			l.Image = imageOf(v)
+		case v.Production == "list-style-type":
+			l.Type, typeSet = listStyleTypeOf(v), true
+		}
+	}
+	if nones > 0 && !typeSet {
+		l.Type = ListStyleType{Name: "none"}
+	}
+	return l
+}
+
+func listStyleType(values []ComponentValue) interface{} {
+	return listStyleTypeOf(values[0])
+}
+
+func expandListStyle(values []ComponentValue) map[string]string {
+	l := listStyle(values).(ListStyle)
+	return map[string]string{
+		"list-style-position": l.Position,
+		"list-style-image":    l.Image.String(),
+		"list-style-type":     l.Type.String(),
+	}
+}
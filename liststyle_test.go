@@ -0,0 +1,74 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListStyle(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"list-style-type", "Upper-Roman", ListStyleType{Name: "upper-roman"}},
+		{"list-style-type", "myStyle", ListStyleType{Name: "myStyle"}},
+		{"list-style-type", `"- "`, ListStyleType{Text: "- "}},
+		{"list-style-type", "none", ListStyleType{Name: "none"}},
+		{"list-style-position", "INSIDE", "inside"},
+		{"list-style-image", "url(dot.png)", Image{URL: "dot.png"}},
+		{"list-style-image", "none", Image{}},
+		{"list-style", "square inside", ListStyle{Position: "inside", Type: ListStyleType{Name: "square"}}},
+		{"list-style", "none", ListStyle{Position: "outside", Type: ListStyleType{Name: "none"}}},
+		{"list-style", "url(a.png) none", ListStyle{Position: "outside", Image: Image{URL: "a.png"}, Type: ListStyleType{Name: "none"}}},
+		{"list-style", "none lower-alpha", ListStyle{Position: "outside", Type: ListStyleType{Name: "lower-alpha"}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"list-style-type":     "1",
+		"list-style-position": "middle",
+		"list-style-image":    "square",
+		"list-style":          "disc square",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	style, err := CSSStyle("list-style-type", map[string]string{"list-style-type": `symbols(cyclic "*" "+")`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listType := style.Value.(ListStyleType)
+	assert.Equal(t, `symbols(cyclic "*" "+")`, listType.String())
+	assert.Equal(t, "+ ", CounterStyles(nil).Marker(listType, 2))
+	assert.Equal(t, "* ", CounterStyles(nil).Marker(listType, 3))
+}
+
+func TestExpandListStyle(t *testing.T) {
+	longhands, err := ExpandShorthand("list-style", "inside url(dot.png)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"list-style-position": "inside",
+		"list-style-image":    `url("dot.png")`,
+		"list-style-type":     "disc",
+	}, longhands)
+
+	longhands, err = ExpandShorthand("list-style", `"-" outside`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `"-"`, longhands["list-style-type"])
+}
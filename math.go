@@ -0,0 +1,250 @@
+package css
+
+import (
+	"math"
+	"strings"
+)
+
+// mathType returns the type of the result of a math function like calc():
+// "number", "percentage", "length", "angle", "time" or "resolution", or a
+// type followed by "-percentage" if it adds percentages to the type, like
+// "length-percentage". It returns "" if v isn't a valid math function.
+func mathType(v ComponentValue) string {
+	if v.Type != FunctionValue {
+		return ""
+	}
+	switch v.Text {
+	case "calc", "abs":
+		return sumType(v.Args)
+	case "sign":
+		if sumType(v.Args) == "" {
+			return ""
+		}
+		return "number"
+	case "min", "max", "clamp":
+		groups := splitCommas(v.Args)
+		if v.Text == "clamp" && len(groups) != 3 {
+			return ""
+		}
+		t := sumType(groups[0])
+		for _, group := range groups[1:] {
+			if t == "" {
+				break
+			}
+			t = addTypes(t, sumType(group))
+		}
+		return t
+	}
+	return ""
+}
+
+// sumType returns the type of a calculation of products separated by "+"
+// and "-".
+func sumType(values []ComponentValue) string {
+	t := ""
+	start := 0
+	for i := 0; i <= len(values); i++ {
+		if i < len(values) && !values[i].IsDelim("+") && !values[i].IsDelim("-") {
+			continue
+		}
+		product := productType(values[start:i])
+		if start == 0 {
+			t = product
+		} else {
+			t = addTypes(t, product)
+		}
+		if t == "" {
+			return ""
+		}
+		start = i + 1
+	}
+	return t
+}
+
+// productType returns the type of values separated by "*" and "/". One
+// side of a multiplication and the right side of a division must be
+// numbers.
+func productType(values []ComponentValue) string {
+	if len(values)%2 == 0 {
+		return ""
+	}
+	t := calcValueType(values[0])
+	for i := 1; i < len(values) && t != ""; i += 2 {
+		right := calcValueType(values[i+1])
+		switch {
+		case right == "":
+			return ""
+		case values[i].IsDelim("*") && t == "number":
+			t = right
+		case values[i].IsDelim("*") || values[i].IsDelim("/"):
+			if right != "number" {
+				return ""
+			}
+		default:
+			return ""
+		}
+	}
+	return t
+}
+
+func calcValueType(v ComponentValue) string {
+	switch v.Type {
+	case NumberValue:
+		return "number"
+	case PercentageValue:
+		return "percentage"
+	case DimensionValue:
+		switch {
+		case lengthUnits[v.Unit]:
+			return "length"
+		case angleUnits[v.Unit]:
+			return "angle"
+		case timeUnits[v.Unit]:
+			return "time"
+		case resolutionUnits[v.Unit]:
+			return "resolution"
+		}
+	case BlockValue:
+		if v.Text == "(" {
+			return sumType(v.Args)
+		}
+	case FunctionValue:
+		return mathType(v)
+	case IdentValue:
+		switch strings.ToLower(v.Text) {
+		case "e", "pi", "infinity", "-infinity", "nan":
+			return "number"
+		}
+	}
+	return ""
+}
+
+// addTypes returns the type of the sum of values of types a and b.
+func addTypes(a, b string) string {
+	if a == "" || b == "" {
+		return ""
+	}
+	if a == b {
+		return a
+	}
+	baseA, baseB := strings.TrimSuffix(a, "-percentage"), strings.TrimSuffix(b, "-percentage")
+	switch {
+	case baseA == baseB:
+		return baseA + "-percentage"
+	case baseA == "percentage" && baseB != "number":
+		return baseB + "-percentage"
+	case baseB == "percentage" && baseA != "number":
+		return baseA + "-percentage"
+	}
+	return ""
+}
+
+// evalMath evaluates a math function. Numbers are evaluated directly, the
+// other values by leaf, which reports false if it can't resolve a value.
+func evalMath(v ComponentValue, leaf func(ComponentValue) (float64, bool)) (float64, bool) {
+	groups := splitCommas(v.Args)
+	results := make([]float64, len(groups))
+	for i, group := range groups {
+		var ok bool
+		if results[i], ok = evalSum(group, leaf); !ok {
+			return 0, false
+		}
+	}
+	switch v.Text {
+	case "calc":
+		return results[0], true
+	case "abs":
+		return math.Abs(results[0]), true
+	case "sign":
+		switch {
+		case results[0] > 0:
+			return 1, true
+		case results[0] < 0:
+			return -1, true
+		}
+		return results[0], true
+	case "min", "max":
+		result := results[0]
+		for _, r := range results[1:] {
+			if v.Text == "min" {
+				result = math.Min(result, r)
+			} else {
+				result = math.Max(result, r)
+			}
+		}
+		return result, true
+	case "clamp":
+		return math.Max(results[0], math.Min(results[1], results[2])), true
+	}
+	return 0, false
+}
+
+func evalSum(values []ComponentValue, leaf func(ComponentValue) (float64, bool)) (float64, bool) {
+	sum, sign := 0.0, 1.0
+	start := 0
+	for i := 0; i <= len(values); i++ {
+		if i < len(values) && !values[i].IsDelim("+") && !values[i].IsDelim("-") {
+			continue
+		}
+		product, ok := evalProduct(values[start:i], leaf)
+		if !ok {
+			return 0, false
+		}
+		sum += sign * product
+		if i < len(values) && values[i].IsDelim("-") {
+			sign = -1
+		} else {
+			sign = 1
+		}
+		start = i + 1
+	}
+	return sum, true
+}
+
+func evalProduct(values []ComponentValue, leaf func(ComponentValue) (float64, bool)) (float64, bool) {
+	if len(values)%2 == 0 {
+		return 0, false
+	}
+	product, ok := evalCalcValue(values[0], leaf)
+	for i := 1; i < len(values) && ok; i += 2 {
+		var right float64
+		if right, ok = evalCalcValue(values[i+1], leaf); !ok {
+			break
+		}
+		if values[i].IsDelim("/") {
+			product /= right
+		} else {
+			product *= right
+		}
+	}
+	return product, ok
+}
+
+func evalCalcValue(v ComponentValue, leaf func(ComponentValue) (float64, bool)) (float64, bool) {
+	switch v.Type {
+	case NumberValue:
+		return v.Number, true
+	case BlockValue:
+		return evalSum(v.Args, leaf)
+	case FunctionValue:
+		return evalMath(v, leaf)
+	case IdentValue:
+		switch strings.ToLower(v.Text) {
+		case "e":
+			return math.E, true
+		case "pi":
+			return math.Pi, true
+		case "infinity":
+			return math.Inf(1), true
+		case "-infinity":
+			return math.Inf(-1), true
+		case "nan":
+			return math.NaN(), true
+		}
+		return 0, false
+	}
+	if leaf == nil {
+		return 0, false
+	}
+	return leaf(v)
+}
@@ -0,0 +1,81 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMathType(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected string
+	}{
+		{"calc(1px + 2em)", "length"},
+		{"calc(100% - 10px)", "length-percentage"},
+		{"calc(50% * 2)", "percentage"},
+		{"calc(2 * (1px + 1em) / 4)", "length"},
+		{"calc(1 + 2 * pi)", "number"},
+		{"calc(90deg + 0.5turn)", "angle"},
+		{"min(10px, 5vw, 20%)", "length-percentage"},
+		{"clamp(1rem, 2.5vw, 2rem)", "length"},
+		{"max(1s, calc(100ms * 3))", "time"},
+		{"abs(-2px)", "length"},
+		{"sign(-2px)", "number"},
+		{"calc(1px + 2)", ""},
+		{"calc(1px * 2px)", ""},
+		{"calc(2 / 1px)", ""},
+		{"calc(1px -2px)", ""},
+		{"calc(1px +)", ""},
+		{"calc()", ""},
+		{"clamp(1px, 2px)", ""},
+		{"calc(50% + 1)", ""},
+		{"calc(foo)", ""},
+		{"var(--x)", ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			values, err := ParseValue(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, mathType(values[0]))
+		})
+	}
+}
+
+func TestEvalMath(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected float64
+	}{
+		{"calc(1 + 2 * 3)", 7},
+		{"calc((1 + 2) * 3)", 9},
+		{"calc(10 / 4 - 1)", 1.5},
+		{"min(3, 1, 2)", 1},
+		{"max(3, calc(2 * 2))", 4},
+		{"clamp(0, 5, 3)", 3},
+		{"abs(-2)", 2},
+		{"sign(-0.5)", -1},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			values, err := ParseValue(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, ok := evalMath(values[0], nil)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, n)
+		})
+	}
+
+	values, _ := ParseValue("calc(1px + 2px)")
+	_, ok := evalMath(values[0], nil)
+	assert.False(t, ok)
+	n, ok := evalMath(values[0], func(v ComponentValue) (float64, bool) { return v.Number, v.Unit == "px" })
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, n)
+}
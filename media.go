@@ -0,0 +1,787 @@
+package css
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MediaEnvironment describes the device and user preferences that media
+// queries are evaluated against. Zero fields take the value of a desktop
+// screen, or of a printer for the print media type.
+type MediaEnvironment struct {
+	// Type is the lower case media type, "screen" if empty.
+	Type string
+	// Width and Height are the size of the viewport, or of the page box
+	// for paged media, in pixels.
+	Width, Height float64
+	// DeviceWidth and DeviceHeight are the size of the output device in
+	// pixels. Zero uses Width and Height.
+	DeviceWidth, DeviceHeight float64
+	// Resolution is the device pixel ratio in dppx, 1 if zero.
+	Resolution float64
+	// Color is the number of bits per color component, 8 if zero. It is
+	// ignored if Monochrome is set.
+	Color int
+	// ColorIndex is the number of entries of the color lookup table of
+	// indexed color devices.
+	ColorIndex int
+	// Monochrome is the number of bits per pixel of monochrome devices.
+	Monochrome int
+	// FontSize is the initial font size in pixels that relative units in
+	// media queries are resolved with, 16 if zero.
+	FontSize float64
+	// Grid is set for grid devices like terminals.
+	Grid bool
+	// Hover is "none" or "hover", and AnyHover the same for any of the
+	// input devices. AnyHover defaults to Hover.
+	Hover, AnyHover string
+	// Pointer is "none", "coarse" or "fine", and AnyPointer the same for
+	// any of the input devices. AnyPointer defaults to Pointer.
+	Pointer, AnyPointer string
+	// PrefersColorScheme is "light" or "dark".
+	PrefersColorScheme string
+	// PrefersReducedMotion and PrefersReducedTransparency are
+	// "no-preference" or "reduce".
+	PrefersReducedMotion, PrefersReducedTransparency string
+	// PrefersContrast is "no-preference", "more", "less" or "custom".
+	PrefersContrast string
+	// ForcedColors is "none" or "active".
+	ForcedColors string
+	// InvertedColors is "none" or "inverted".
+	InvertedColors string
+	// ColorGamut is "srgb", "p3" or "rec2020".
+	ColorGamut string
+	// DynamicRange is "standard" or "high".
+	DynamicRange string
+	// DisplayMode is "browser", "minimal-ui", "standalone", "fullscreen"
+	// or "picture-in-picture".
+	DisplayMode string
+	// Scan is "progressive" or "interlace".
+	Scan string
+	// Scripting is "enabled", "initial-only" or "none".
+	Scripting string
+}
+
+func (env MediaEnvironment) mediaType() string {
+	if env.Type == "" {
+		return "screen"
+	}
+	return strings.ToLower(env.Type)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// keyword returns the value of the discrete media feature name.
+func (env MediaEnvironment) keyword(name string) string {
+	paged := env.mediaType() == "print"
+	switch name {
+	case "orientation":
+		if env.Height >= env.Width {
+			return "portrait"
+		}
+		return "landscape"
+	case "grid":
+		if env.Grid {
+			return "1"
+		}
+		return "0"
+	case "hover":
+		if paged {
+			return orDefault(env.Hover, "none")
+		}
+		return orDefault(env.Hover, "hover")
+	case "any-hover":
+		return orDefault(env.AnyHover, env.keyword("hover"))
+	case "pointer":
+		if paged {
+			return orDefault(env.Pointer, "none")
+		}
+		return orDefault(env.Pointer, "fine")
+	case "any-pointer":
+		return orDefault(env.AnyPointer, env.keyword("pointer"))
+	case "update", "overflow-inline":
+		if paged {
+			return "none"
+		}
+		if name == "update" {
+			return "fast"
+		}
+		return "scroll"
+	case "overflow-block":
+		if paged {
+			return "paged"
+		}
+		return "scroll"
+	case "prefers-color-scheme":
+		return orDefault(env.PrefersColorScheme, "light")
+	case "prefers-reduced-motion":
+		return orDefault(env.PrefersReducedMotion, "no-preference")
+	case "prefers-reduced-transparency":
+		return orDefault(env.PrefersReducedTransparency, "no-preference")
+	case "prefers-contrast":
+		return orDefault(env.PrefersContrast, "no-preference")
+	case "forced-colors":
+		return orDefault(env.ForcedColors, "none")
+	case "inverted-colors":
+		return orDefault(env.InvertedColors, "none")
+	case "color-gamut":
+		return orDefault(env.ColorGamut, "srgb")
+	case "dynamic-range", "video-dynamic-range":
+		return orDefault(env.DynamicRange, "standard")
+	case "display-mode":
+		return orDefault(env.DisplayMode, "browser")
+	case "scan":
+		return orDefault(env.Scan, "progressive")
+	case "scripting":
+		return orDefault(env.Scripting, "enabled")
+	}
+	return ""
+}
+
+// number returns the value of the range media feature name.
+func (env MediaEnvironment) number(name string) float64 {
+	deviceWidth, deviceHeight := env.DeviceWidth, env.DeviceHeight
+	if deviceWidth == 0 && deviceHeight == 0 {
+		deviceWidth, deviceHeight = env.Width, env.Height
+	}
+	switch name {
+	case "width":
+		return env.Width
+	case "height":
+		return env.Height
+	case "device-width":
+		return deviceWidth
+	case "device-height":
+		return deviceHeight
+	case "aspect-ratio":
+		return env.Width / env.Height
+	case "device-aspect-ratio":
+		return deviceWidth / deviceHeight
+	case "resolution":
+		if env.Resolution == 0 {
+			return 1
+		}
+		return env.Resolution
+	case "color":
+		switch {
+		case env.Monochrome > 0:
+			return 0
+		case env.Color == 0:
+			return 8
+		}
+		return float64(env.Color)
+	case "color-index":
+		return float64(env.ColorIndex)
+	case "monochrome":
+		return float64(env.Monochrome)
+	}
+	return 0
+}
+
+// length resolves a <length> in a media query to pixels.
+func (env MediaEnvironment) length(v ComponentValue) (float64, bool) {
+	if v.Type == FunctionValue {
+		return evalMath(v, env.length)
+	}
+	if v.Type == DimensionValue {
+		switch v.Unit {
+		case "vw":
+			return v.Number * env.Width / 100, true
+		case "vh":
+			return v.Number * env.Height / 100, true
+		case "vmin":
+			return v.Number * math.Min(env.Width, env.Height) / 100, true
+		case "vmax":
+			return v.Number * math.Max(env.Width, env.Height) / 100, true
+		}
+	}
+	fontSize := env.FontSize
+	if fontSize == 0 {
+		fontSize = defaultFontSize
+	}
+	if v.Type != DimensionValue && v.Type != NumberValue {
+		return 0, false
+	}
+	return lengthOf(v).Pixels(-1, fontSize)
+}
+
+// mediaFeature describes how a media feature is evaluated.
+type mediaFeature struct {
+	// kind is "length", "ratio", "resolution" or "integer" for range
+	// features, and empty for discrete features.
+	kind string
+	// values are the keywords of a discrete feature.
+	values []string
+	// none is the value of a discrete feature that is false in a boolean
+	// context, if any.
+	none string
+	// ordered features also match the values listed before theirs, like a
+	// p3 color gamut matches srgb.
+	ordered bool
+}
+
+var mediaFeatures = map[string]mediaFeature{
+	"any-hover":                    {values: []string{"none", "hover"}, none: "none"},
+	"any-pointer":                  {values: []string{"none", "coarse", "fine"}, none: "none"},
+	"aspect-ratio":                 {kind: "ratio"},
+	"color":                        {kind: "integer"},
+	"color-gamut":                  {values: []string{"srgb", "p3", "rec2020"}, ordered: true},
+	"color-index":                  {kind: "integer"},
+	"device-aspect-ratio":          {kind: "ratio"},
+	"device-height":                {kind: "length"},
+	"device-width":                 {kind: "length"},
+	"display-mode":                 {values: []string{"browser", "minimal-ui", "standalone", "fullscreen", "picture-in-picture"}},
+	"dynamic-range":                {values: []string{"standard", "high"}, ordered: true},
+	"forced-colors":                {values: []string{"none", "active"}, none: "none"},
+	"grid":                         {values: []string{"0", "1"}, none: "0"},
+	"height":                       {kind: "length"},
+	"hover":                        {values: []string{"none", "hover"}, none: "none"},
+	"inverted-colors":              {values: []string{"none", "inverted"}, none: "none"},
+	"monochrome":                   {kind: "integer"},
+	"orientation":                  {values: []string{"portrait", "landscape"}},
+	"overflow-block":               {values: []string{"none", "scroll", "paged"}, none: "none"},
+	"overflow-inline":              {values: []string{"none", "scroll"}, none: "none"},
+	"pointer":                      {values: []string{"none", "coarse", "fine"}, none: "none"},
+	"prefers-color-scheme":         {values: []string{"light", "dark"}},
+	"prefers-contrast":             {values: []string{"no-preference", "more", "less", "custom"}, none: "no-preference"},
+	"prefers-reduced-motion":       {values: []string{"no-preference", "reduce"}, none: "no-preference"},
+	"prefers-reduced-transparency": {values: []string{"no-preference", "reduce"}, none: "no-preference"},
+	"resolution":                   {kind: "resolution"},
+	"scan":                         {values: []string{"interlace", "progressive"}},
+	"scripting":                    {values: []string{"none", "initial-only", "enabled"}, none: "none"},
+	"update":                       {values: []string{"none", "slow", "fast"}, none: "none"},
+	"video-dynamic-range":          {values: []string{"standard", "high"}, ordered: true},
+	"width":                        {kind: "length"},
+}
+
+// mediaResult is the result of a media condition in three-valued logic,
+// ordered so that and is the minimum and or the maximum of results.
+type mediaResult int
+
+const (
+	mediaFalse mediaResult = iota
+	mediaUnknown
+	mediaTrue
+)
+
+func mediaResultOf(b bool) mediaResult {
+	if b {
+		return mediaTrue
+	}
+	return mediaFalse
+}
+
+// MediaQueryList is a comma separated list of media queries, like the
+// prelude of an @media rule. It matches if any of its queries matches, or
+// if it is empty.
+type MediaQueryList []MediaQuery
+
+// MediaQuery is a media query, which tests the media type and the media
+// features of an environment.
+type MediaQuery struct {
+	// Not negates the query, Only hides it from legacy user agents.
+	Not, Only bool
+	// Type is the lower case media type, or empty if the query is only a
+	// condition.
+	Type string
+	// Condition tests the media features, if it is not nil.
+	Condition *MediaCondition
+}
+
+// MediaCondition is a media feature test, or the not, and or or of other
+// conditions.
+type MediaCondition struct {
+	// Op is "not", "and" or "or" for conditions combining Conditions, and
+	// empty for a feature test or a general enclosed condition.
+	Op         string
+	Conditions []MediaCondition
+	// Feature is the media feature tested by the condition, if any.
+	Feature *MediaFeature
+	// Enclosed is the text of a general enclosed condition, a function or
+	// parenthesized text that isn't a known condition. It evaluates to
+	// unknown, which never matches.
+	Enclosed string
+}
+
+// MediaFeature is a media feature test like "(hover)", "(min-width:
+// 400px)" or "(400px <= width < 800px)".
+type MediaFeature struct {
+	// Name is the lower case feature name. The min- and max- prefixes of
+	// range features are turned into comparisons.
+	Name string
+	// Comparisons are all satisfied by the feature value. Features without
+	// comparisons are evaluated in a boolean context.
+	Comparisons []MediaComparison
+}
+
+// MediaComparison compares a media feature with a value.
+type MediaComparison struct {
+	// Op is "=", "<", "<=", ">" or ">=", with the feature on the left.
+	Op string
+	// Value is a number, dimension or identifier, or the three values of
+	// a ratio.
+	Value []ComponentValue
+}
+
+var flippedComparisons = map[string]string{"=": "=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+
+// notAll is the query that replaces malformed media queries.
+var notAll = MediaQuery{Not: true, Type: "all"}
+
+// ParseMediaQueryList parses a media query list. Like browsers, it
+// replaces the queries that can't be parsed with "not all", which never
+// matches, and returns the error of the first one.
+func ParseMediaQueryList(s string) (MediaQueryList, error) {
+	values, err := ParseValue(s)
+	if err != nil {
+		return MediaQueryList{notAll}, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var queries MediaQueryList
+	var first error
+	for _, values := range splitCommas(values) {
+		q, err := parseMediaQuery(values)
+		if err != nil {
+			q = notAll
+			if first == nil {
+				first = fmt.Errorf("invalid media query %q: %w", serializeValues(values), err)
+			}
+		}
+		queries = append(queries, q)
+	}
+	return queries, first
+}
+
+// reservedMediaTypes can't be used as media types.
+var reservedMediaTypes = map[string]bool{"only": true, "not": true, "and": true, "or": true, "layer": true}
+
+func parseMediaQuery(values []ComponentValue) (MediaQuery, error) {
+	if len(values) == 0 {
+		return MediaQuery{}, fmt.Errorf("empty media query")
+	}
+	if values[0].Type != IdentValue || values[0].Is("not") && len(values) > 1 && values[1].Type != IdentValue {
+		c, err := parseMediaCondition(values, true)
+		return MediaQuery{Condition: c}, err
+	}
+	var q MediaQuery
+	switch {
+	case values[0].Is("not"):
+		q.Not, values = true, values[1:]
+	case values[0].Is("only"):
+		q.Only, values = true, values[1:]
+	}
+	if len(values) == 0 || values[0].Type != IdentValue || reservedMediaTypes[strings.ToLower(values[0].Text)] {
+		return MediaQuery{}, fmt.Errorf("expected a media type")
+	}
+	q.Type, values = strings.ToLower(values[0].Text), values[1:]
+	if len(values) == 0 {
+		return q, nil
+	}
+	if !values[0].Is("and") {
+		return MediaQuery{}, fmt.Errorf("expected and after the media type")
+	}
+	c, err := parseMediaCondition(values[1:], false)
+	q.Condition = c
+	return q, err
+}
+
+// parseMediaCondition parses a <media-condition>, or a
+// <media-condition-without-or> if or isn't set.
+func parseMediaCondition(values []ComponentValue, or bool) (*MediaCondition, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("expected a media condition")
+	}
+	if values[0].Is("not") {
+		if len(values) != 2 {
+			return nil, fmt.Errorf("expected a single condition after not")
+		}
+		c, err := parseMediaInParens(values[1])
+		if err != nil {
+			return nil, err
+		}
+		return &MediaCondition{Op: "not", Conditions: []MediaCondition{*c}}, nil
+	}
+	first, err := parseMediaInParens(values[0])
+	if err != nil || len(values) == 1 {
+		return first, err
+	}
+	op := strings.ToLower(values[1].Text)
+	if values[1].Type != IdentValue || op != "and" && (op != "or" || !or) {
+		return nil, fmt.Errorf("unexpected %s in media condition", values[1])
+	}
+	c := &MediaCondition{Op: op, Conditions: []MediaCondition{*first}}
+	for i := 1; i < len(values); i += 2 {
+		if !values[i].Is(op) || i+1 == len(values) {
+			return nil, fmt.Errorf("and and or can't be mixed without parentheses")
+		}
+		next, err := parseMediaInParens(values[i+1])
+		if err != nil {
+			return nil, err
+		}
+		c.Conditions = append(c.Conditions, *next)
+	}
+	return c, nil
+}
+
+// parseMediaInParens parses a <media-in-parens>. Parenthesized values that
+// are neither a condition nor a media feature are general enclosed.
+func parseMediaInParens(v ComponentValue) (*MediaCondition, error) {
+	if v.Type == FunctionValue {
+		return &MediaCondition{Enclosed: v.String()}, nil
+	}
+	if v.Type != BlockValue || v.Text != "(" {
+		return nil, fmt.Errorf("unexpected %s in media condition", v)
+	}
+	if len(v.Args) > 0 && (v.Args[0].Is("not") || v.Args[0].Type == BlockValue || v.Args[0].Type == FunctionValue) {
+		if c, err := parseMediaCondition(v.Args, true); err == nil {
+			return c, nil
+		}
+	} else if f, err := parseMediaFeature(v.Args); err == nil {
+		return &MediaCondition{Feature: f}, nil
+	}
+	return &MediaCondition{Enclosed: v.String()}, nil
+}
+
+func parseMediaFeature(values []ComponentValue) (*MediaFeature, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("empty media feature")
+	}
+	if values[0].Type == IdentValue && (len(values) == 1 || values[1].IsDelim(":")) {
+		f := &MediaFeature{Name: strings.ToLower(values[0].Text)}
+		if len(values) == 1 {
+			return f, nil
+		}
+		if !isMediaValue(values[2:]) {
+			return nil, fmt.Errorf("invalid value of media feature %s", f.Name)
+		}
+		op := "="
+		for prefix, prefixOp := range map[string]string{"min-": ">=", "max-": "<="} {
+			name := strings.TrimPrefix(f.Name, prefix)
+			if name != f.Name && mediaFeatures[name].kind != "" {
+				f.Name, op = name, prefixOp
+			}
+		}
+		f.Comparisons = []MediaComparison{{Op: op, Value: values[2:]}}
+		return f, nil
+	}
+
+	// range syntax: split the values at the comparison operators
+	var parts [][]ComponentValue
+	var ops []string
+	start := 0
+	for i := 0; i < len(values); i++ {
+		v := values[i]
+		if !v.IsDelim("<") && !v.IsDelim(">") && !v.IsDelim("=") {
+			continue
+		}
+		op := v.Text
+		if op != "=" && i+1 < len(values) && values[i+1].IsDelim("=") {
+			op += "="
+		}
+		parts, ops = append(parts, values[start:i]), append(ops, op)
+		i += len(op) - 1
+		start = i + 1
+	}
+	parts = append(parts, values[start:])
+	isName := func(values []ComponentValue) bool {
+		return len(values) == 1 && values[0].Type == IdentValue
+	}
+	for _, p := range parts {
+		if len(p) == 0 {
+			return nil, fmt.Errorf("missing value in media feature")
+		}
+	}
+
+	switch {
+	case len(ops) == 1 && isName(parts[0]) && isMediaValue(parts[1]):
+		return &MediaFeature{
+			Name:        strings.ToLower(parts[0][0].Text),
+			Comparisons: []MediaComparison{{Op: ops[0], Value: parts[1]}},
+		}, nil
+	case len(ops) == 1 && isName(parts[1]) && isMediaValue(parts[0]):
+		return &MediaFeature{
+			Name:        strings.ToLower(parts[1][0].Text),
+			Comparisons: []MediaComparison{{Op: flippedComparisons[ops[0]], Value: parts[0]}},
+		}, nil
+	case len(ops) == 2 && isName(parts[1]) && isMediaValue(parts[0]) && isMediaValue(parts[2]) &&
+		ops[0][0] == ops[1][0] && ops[0] != "=" && ops[1] != "=":
+		return &MediaFeature{
+			Name: strings.ToLower(parts[1][0].Text),
+			Comparisons: []MediaComparison{
+				{Op: flippedComparisons[ops[0]], Value: parts[0]},
+				{Op: ops[1], Value: parts[2]},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid media feature %q", serializeValues(values))
+}
+
+// isMediaValue reports whether values are an <mf-value>: a number,
+// dimension or identifier, or a ratio.
+func isMediaValue(values []ComponentValue) bool {
+	switch len(values) {
+	case 1:
+		switch values[0].Type {
+		case NumberValue, DimensionValue, IdentValue, FunctionValue:
+			return true
+		}
+	case 3:
+		return values[0].Type == NumberValue && values[1].IsDelim("/") && values[2].Type == NumberValue
+	}
+	return false
+}
+
+// Matches reports whether any query of l matches env.
+func (l MediaQueryList) Matches(env MediaEnvironment) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, q := range l {
+		if q.Matches(env) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether q matches env. Queries that test unknown media
+// features or use values they don't understand don't match, even if they
+// are negated.
+func (q MediaQuery) Matches(env MediaEnvironment) bool {
+	result := mediaTrue
+	if q.Type != "" && q.Type != "all" {
+		result = mediaResultOf(q.Type == env.mediaType())
+	}
+	if q.Condition != nil && result != mediaFalse {
+		result = q.Condition.eval(env)
+	}
+	if q.Not {
+		result = mediaTrue - result
+	}
+	return result == mediaTrue
+}
+
+func (c MediaCondition) eval(env MediaEnvironment) mediaResult {
+	switch c.Op {
+	case "not":
+		return mediaTrue - c.Conditions[0].eval(env)
+	case "and":
+		result := mediaTrue
+		for _, c := range c.Conditions {
+			if r := c.eval(env); r < result {
+				result = r
+			}
+		}
+		return result
+	case "or":
+		result := mediaFalse
+		for _, c := range c.Conditions {
+			if r := c.eval(env); r > result {
+				result = r
+			}
+		}
+		return result
+	}
+	if c.Feature == nil {
+		return mediaUnknown
+	}
+	return c.Feature.eval(env)
+}
+
+func (f MediaFeature) eval(env MediaEnvironment) mediaResult {
+	feature, ok := mediaFeatures[f.Name]
+	if !ok {
+		return mediaUnknown
+	}
+	if feature.kind == "" {
+		value := env.keyword(f.Name)
+		if len(f.Comparisons) == 0 {
+			return mediaResultOf(value != feature.none)
+		}
+		c := f.Comparisons[0]
+		if c.Op != "=" || len(c.Value) != 1 {
+			return mediaUnknown
+		}
+		want := strings.ToLower(c.Value[0].String())
+		for i, v := range feature.values {
+			if v == want {
+				return mediaResultOf(v == value || feature.ordered && i < feature.index(value))
+			}
+		}
+		return mediaUnknown
+	}
+
+	value := env.number(f.Name)
+	if len(f.Comparisons) == 0 {
+		return mediaResultOf(value != 0)
+	}
+	for _, c := range f.Comparisons {
+		n, ok := feature.number(c.Value, env)
+		if !ok {
+			return mediaUnknown
+		}
+		var result bool
+		switch c.Op {
+		case "=":
+			result = value == n
+		case "<":
+			result = value < n
+		case "<=":
+			result = value <= n
+		case ">":
+			result = value > n
+		case ">=":
+			result = value >= n
+		}
+		if !result {
+			return mediaFalse
+		}
+	}
+	return mediaTrue
+}
+
+func (f mediaFeature) index(value string) int {
+	for i, v := range f.values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// number converts the value compared with a range feature to the unit of
+// the feature: pixels, dppx or a plain number.
+func (f mediaFeature) number(values []ComponentValue, env MediaEnvironment) (float64, bool) {
+	if f.kind == "ratio" {
+		if len(values) == 3 {
+			return values[0].Number / values[2].Number, values[0].Number >= 0 && values[2].Number > 0
+		}
+		return values[0].Number, values[0].Type == NumberValue && values[0].Number > 0
+	}
+	if len(values) != 1 {
+		return 0, false
+	}
+	v := values[0]
+	switch f.kind {
+	case "length":
+		return env.length(v)
+	case "integer":
+		return v.Number, v.Type == NumberValue && v.Integer && v.Number >= 0
+	case "resolution":
+		if v.Is("infinite") {
+			return math.Inf(1), true
+		}
+		if v.Type != DimensionValue {
+			return 0, false
+		}
+		switch v.Unit {
+		case "dppx", "x":
+			return v.Number, true
+		case "dpi":
+			return v.Number / 96, true
+		case "dpcm":
+			return v.Number * 2.54 / 96, true
+		}
+	}
+	return 0, false
+}
+
+func (l MediaQueryList) String() string {
+	queries := make([]string, len(l))
+	for i, q := range l {
+		queries[i] = q.String()
+	}
+	return strings.Join(queries, ", ")
+}
+
+func (q MediaQuery) String() string {
+	var parts []string
+	switch {
+	case q.Not:
+		parts = append(parts, "not")
+	case q.Only:
+		parts = append(parts, "only")
+	}
+	if q.Type != "" {
+		parts = append(parts, q.Type)
+	}
+	if q.Condition != nil {
+		if q.Type != "" {
+			parts = append(parts, "and")
+		}
+		parts = append(parts, q.Condition.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c MediaCondition) String() string {
+	switch c.Op {
+	case "not":
+		return "not " + c.Conditions[0].inParens()
+	case "and", "or":
+		conditions := make([]string, len(c.Conditions))
+		for i, c := range c.Conditions {
+			conditions[i] = c.inParens()
+		}
+		return strings.Join(conditions, " "+c.Op+" ")
+	}
+	if c.Feature != nil {
+		return c.Feature.String()
+	}
+	return c.Enclosed
+}
+
+// inParens returns c as a <media-in-parens>.
+func (c MediaCondition) inParens() string {
+	if c.Op == "" {
+		return c.String()
+	}
+	return "(" + c.String() + ")"
+}
+
+func (f MediaFeature) String() string {
+	switch len(f.Comparisons) {
+	case 0:
+		return "(" + f.Name + ")"
+	case 1:
+		c := f.Comparisons[0]
+		if c.Op == "=" {
+			return "(" + f.Name + ": " + serializeValues(c.Value) + ")"
+		}
+		return "(" + f.Name + " " + c.Op + " " + serializeValues(c.Value) + ")"
+	}
+	first, second := f.Comparisons[0], f.Comparisons[1]
+	return "(" + serializeValues(first.Value) + " " + flippedComparisons[first.Op] + " " + f.Name + " " + second.Op + " " + serializeValues(second.Value) + ")"
+}
+
+// MediaHandler returns a Handler that reports a stylesheet to h as it
+// applies to env: the contents of the @media rules that match env are
+// reported without the rule, and the other @media rules are skipped.
+func MediaHandler(h Handler, env MediaEnvironment) Handler {
+	return &conditionalHandler{h: h, rule: "media", matches: func(prelude string) bool {
+		// malformed queries are "not all" and never match
+		queries, _ := ParseMediaQueryList(prelude)
+		return queries.Matches(env)
+	}}
+}
+
+// UnmarshalMedia is like Unmarshal, but also returns the rules of the
+// @media rules that match env. Later rules override earlier ones.
+func UnmarshalMedia(b []byte, env MediaEnvironment) (map[Rule]map[string]string, error) {
+	h := &mapHandler{
+		css: make(map[Rule]map[string]string),
+	}
+	if err := parseBytes(context.Background(), b, MediaHandler(h, env)); err != nil {
+		return h.css, err
+	}
+	return h.css, nil
+}
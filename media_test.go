@@ -0,0 +1,184 @@
+package css
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMediaQueryList(t *testing.T) {
+	cases := []struct {
+		value      string
+		serialized string
+	}{
+		{"", ""},
+		{"SCREEN, print", "screen, print"},
+		{"only screen and (orientation: landscape)", "only screen and (orientation: landscape)"},
+		{"not print and (min-width: 500px) and (max-width:50em)", "not print and (width >= 500px) and (width <= 50em)"},
+		{"(400px <= width < 800px)", "(400px <= width < 800px)"},
+		{"(width>=700px)", "(width >= 700px)"},
+		{"(30em < width)", "(width > 30em)"},
+		{"(hover) and (not (pointer: coarse))", "(hover) and (not (pointer: coarse))"},
+		{"not ((color) or (grid))", "not ((color) or (grid))"},
+		{"screen and not (color)", "screen and not (color)"},
+		{"(min-aspect-ratio: 16/9)", "(aspect-ratio >= 16 / 9)"},
+		{"(min-hover: hover)", "(min-hover: hover)"},
+		{"(width = 1px = 2px), custom(x) or (color)", "(width = 1px = 2px), custom(x) or (color)"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			queries, err := ParseMediaQueryList(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.serialized, queries.String())
+		})
+	}
+
+	queries, err := ParseMediaQueryList("(400px <= width)")
+	assert.NoError(t, err)
+	assert.Equal(t, MediaQueryList{{Condition: &MediaCondition{Feature: &MediaFeature{
+		Name:        "width",
+		Comparisons: []MediaComparison{{Op: ">=", Value: []ComponentValue{{Type: DimensionValue, Number: 400, Integer: true, Unit: "px"}}}},
+	}}}}, queries)
+
+	for _, value := range []string{
+		"only (color)",
+		"screen or print",
+		"not",
+		"and",
+		"screen and",
+		"screen (color)",
+		"(color) and (grid) or (hover)",
+		"not (color) and (grid)",
+		"screen and (color) or (grid)",
+		"(color",
+	} {
+		queries, err := ParseMediaQueryList(value)
+		if assert.Error(t, err, value) {
+			assert.Equal(t, MediaQueryList{notAll}, queries, value)
+		}
+	}
+
+	queries, err = ParseMediaQueryList("screen, only and, print")
+	assert.Error(t, err)
+	assert.Equal(t, "screen, not all, print", queries.String())
+}
+
+func TestMediaQueryMatches(t *testing.T) {
+	screen := MediaEnvironment{Width: 600, Height: 400}
+	phone := MediaEnvironment{
+		Width: 390, Height: 844, Resolution: 3, Hover: "none", Pointer: "coarse",
+		PrefersColorScheme: "dark", PrefersReducedMotion: "reduce", ColorGamut: "p3",
+	}
+	paper := MediaEnvironment{Type: "print", Width: 794, Height: 1123, Monochrome: 1}
+	cases := []struct {
+		query    string
+		expected [3]bool
+	}{
+		{"", [3]bool{true, true, true}},
+		{"all", [3]bool{true, true, true}},
+		{"screen", [3]bool{true, true, false}},
+		{"print", [3]bool{false, false, true}},
+		{"tv", [3]bool{false, false, false}},
+		{"not print", [3]bool{true, true, false}},
+		{"screen, print", [3]bool{true, true, true}},
+		{"(min-width: 500px)", [3]bool{true, false, true}},
+		{"not print and (max-width: 500px)", [3]bool{true, true, true}},
+		{"(400px <= width < 800px)", [3]bool{true, false, true}},
+		{"(width > 30em)", [3]bool{true, false, true}},
+		{"(width: calc(50vw + 300px))", [3]bool{true, false, false}},
+		{"(orientation: landscape)", [3]bool{true, false, false}},
+		{"(orientation: portrait)", [3]bool{false, true, true}},
+		{"(min-aspect-ratio: 16/9)", [3]bool{false, false, false}},
+		{"(aspect-ratio: 3/2)", [3]bool{true, false, false}},
+		{"(min-resolution: 2dppx)", [3]bool{false, true, false}},
+		{"(resolution: 96dpi)", [3]bool{true, false, true}},
+		{"(resolution < infinite)", [3]bool{true, true, true}},
+		{"(hover) and (pointer: fine)", [3]bool{true, false, false}},
+		{"(any-pointer: coarse)", [3]bool{false, true, false}},
+		{"(prefers-color-scheme: dark)", [3]bool{false, true, false}},
+		{"(prefers-reduced-motion)", [3]bool{false, true, false}},
+		{"(prefers-reduced-motion: no-preference)", [3]bool{true, false, true}},
+		{"(color-gamut: srgb) and (color-gamut: p3)", [3]bool{false, true, false}},
+		{"(color)", [3]bool{true, true, false}},
+		{"(min-color: 8)", [3]bool{true, true, false}},
+		{"(monochrome)", [3]bool{false, false, true}},
+		{"(overflow-block: paged)", [3]bool{false, false, true}},
+		{"(update)", [3]bool{true, true, false}},
+		{"(grid: 0)", [3]bool{true, true, true}},
+		{"not (width < 500px)", [3]bool{true, false, true}},
+		{"(width < 500px) or (hover)", [3]bool{true, true, false}},
+		// unknown features and values never match, even negated
+		{"(foo)", [3]bool{false, false, false}},
+		{"not (foo)", [3]bool{false, false, false}},
+		{"(hover: maybe)", [3]bool{false, false, false}},
+		{"(min-hover: hover)", [3]bool{false, false, false}},
+		{"(width: 10%)", [3]bool{false, false, false}},
+		{"custom(x) or (color)", [3]bool{true, true, false}},
+		{"only (color)", [3]bool{false, false, false}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.query, func(t *testing.T) {
+			queries, _ := ParseMediaQueryList(tt.query)
+			var actual [3]bool
+			for i, env := range []MediaEnvironment{screen, phone, paper} {
+				actual[i] = queries.Matches(env)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMediaHandler(t *testing.T) {
+	h := &recordingHandler{}
+	err := Parse(strings.NewReader(`
+@media print { a { b: c } @font-face { d: e } }
+@media screen and (min-width: 100px) {
+	f { g: h }
+	@media (hover: none) { i { j: k } }
+	@supports (display: grid) { l { m: n } }
+}
+o { @media screen { p: q } }
+@media bad and { r { s: t } }`), MediaHandler(h, MediaEnvironment{Width: 200}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		`rule ["f"]`, "decl g=h important=false", "end rule",
+		`at supports "(display: grid)"`, `rule ["l"]`, "decl m=n important=false", "end rule", "end at supports",
+		`rule ["o"]`, "decl p=q important=false", "end rule",
+	}, h.events)
+}
+
+func TestUnmarshalMedia(t *testing.T) {
+	styleSheet := []byte(`
+a { color: red; margin: 0 }
+@media (max-width: 700px) {
+	a { color: blue }
+	b { width: 1px }
+	@media print { c { width: 2px } }
+}
+@media print { a { color: green } }
+@page { margin: 0 }
+d { height: 0 }`)
+	css, err := UnmarshalMedia(styleSheet, MediaEnvironment{Width: 600, Height: 400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[Rule]map[string]string{
+		"a": {"color": "blue", "margin": "0"},
+		"b": {"width": "1px"},
+		"d": {"height": "0"},
+	}, css)
+
+	css, err = UnmarshalMedia(styleSheet, MediaEnvironment{Type: "print", Width: 800})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[Rule]map[string]string{
+		"a": {"color": "green", "margin": "0"},
+		"d": {"height": "0"},
+	}, css)
+}
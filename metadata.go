@@ -0,0 +1,327 @@
+package css
+
+// AnimationType describes how a property is interpolated by animations and
+// transitions.
+type AnimationType int
+
+const (
+	// AnimationDiscrete properties flip from the start to the end value
+	// halfway through the animation. It is the zero value.
+	AnimationDiscrete AnimationType = iota
+	// AnimationByComputedValue properties interpolate their computed values.
+	AnimationByComputedValue
+	// AnimationRepeatableList properties interpolate lists item by item.
+	AnimationRepeatableList
+	// AnimationNotAnimatable properties can't be animated.
+	AnimationNotAnimatable
+	// AnimationShorthand properties are animated through their longhands.
+	AnimationShorthand
+)
+
+func (t AnimationType) String() string {
+	switch t {
+	case AnimationDiscrete:
+		return "discrete"
+	case AnimationByComputedValue:
+		return "by computed value"
+	case AnimationRepeatableList:
+		return "repeatable list"
+	case AnimationNotAnimatable:
+		return "not animatable"
+	case AnimationShorthand:
+		return "see individual properties"
+	}
+	return "unknown"
+}
+
+const (
+	allElements        = "all elements"
+	notApplicable      = "n/a"
+	asSpecified        = "as specified"
+	absoluteLength     = "absolute length"
+	lengthPercentage   = "absolute length or percentage"
+	individually       = "see individual properties"
+	computedColor      = "computed color"
+	containingBlock    = "width of containing block"
+	containingHeight   = "height of containing block"
+	keywordAsSpecified = "specified keyword"
+	borderBoxSize      = "corresponding dimension of the border box"
+	blockContainers    = "block containers"
+	listItems          = "list items"
+	nonReplacedInlines = "all elements but non-replaced inline elements, table rows, and row groups"
+	positioned         = "positioned elements"
+	tableElements      = "table and inline-table elements"
+)
+
+// metadataRow is one line of the property metadata table.
+type metadataRow struct {
+	name        string
+	inherited   bool
+	initial     string
+	appliesTo   string
+	percentages string
+	computed    string
+	animation   AnimationType
+	group       string
+	longhands   []string
+}
+
+// propertyTable lists the metadata of the properties known to the package.
+// Shorthand membership is derived from the longhands of each shorthand.
+var propertyTable = []metadataRow{
+	{"align-content", false, "normal", "block containers, multicol containers, flex containers and grid containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"align-items", false, "normal", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"align-self", false, "auto", "flex items, grid items, and absolutely-positioned boxes", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"animation", false, individually, allElements, individually, individually, AnimationShorthand, "", []string{"animation-name", "animation-duration", "animation-timing-function", "animation-delay", "animation-iteration-count", "animation-direction", "animation-fill-mode", "animation-play-state"}},
+	{"animation-delay", false, "0s", allElements, notApplicable, "list, each item a duration", AnimationNotAnimatable, "", nil},
+	{"animation-direction", false, "normal", allElements, notApplicable, "list, each item a keyword as specified", AnimationNotAnimatable, "", nil},
+	{"animation-duration", false, "0s", allElements, notApplicable, "list, each item a duration", AnimationNotAnimatable, "", nil},
+	{"animation-fill-mode", false, "none", allElements, notApplicable, "list, each item a keyword as specified", AnimationNotAnimatable, "", nil},
+	{"animation-iteration-count", false, "1", allElements, notApplicable, "list, each item a number or the keyword infinite", AnimationNotAnimatable, "", nil},
+	{"animation-name", false, "none", allElements, notApplicable, "list, each item either a case-sensitive identifier or the keyword none", AnimationNotAnimatable, "", nil},
+	{"animation-play-state", false, "running", allElements, notApplicable, "list, each item a keyword as specified", AnimationNotAnimatable, "", nil},
+	{"animation-timing-function", false, "ease", allElements, notApplicable, "list, each item a computed <easing-function>", AnimationNotAnimatable, "", nil},
+	{"backdrop-filter", false, "none", "all elements; in SVG, container elements and graphics elements", notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"background", false, individually, allElements, individually, individually, AnimationShorthand, "", []string{"background-color", "background-image", "background-repeat", "background-attachment", "background-position", "background-size", "background-origin", "background-clip"}},
+	{"background-attachment", false, "scroll", allElements, notApplicable, "list of keywords", AnimationDiscrete, "", nil},
+	{"background-clip", false, "border-box", allElements, notApplicable, "list of keywords", AnimationRepeatableList, "", nil},
+	{"background-color", false, "transparent", allElements, notApplicable, computedColor, AnimationByComputedValue, "", nil},
+	{"background-image", false, "none", allElements, notApplicable, "list of images with absolute URLs", AnimationDiscrete, "", nil},
+	{"background-origin", false, "padding-box", allElements, notApplicable, "list of keywords", AnimationRepeatableList, "", nil},
+	{"background-position", false, "0% 0%", allElements, "size of background positioning area minus size of background image", "list of length-percentage offsets", AnimationRepeatableList, "", nil},
+	{"background-repeat", false, "repeat", allElements, notApplicable, "list of keyword pairs", AnimationDiscrete, "", nil},
+	{"background-size", false, "auto", allElements, "size of background positioning area", "list of length-percentage or auto pairs", AnimationRepeatableList, "", nil},
+	{"border", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-top-width", "border-right-width", "border-bottom-width", "border-left-width", "border-top-style", "border-right-style", "border-bottom-style", "border-left-style", "border-top-color", "border-right-color", "border-bottom-color", "border-left-color", "border-image-source", "border-image-slice", "border-image-width", "border-image-outset", "border-image-repeat"}},
+	{"border-block", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-start-width", "border-block-start-style", "border-block-start-color", "border-block-end-width", "border-block-end-style", "border-block-end-color"}},
+	{"border-block-color", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-start-color", "border-block-end-color"}},
+	{"border-block-end", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-end-width", "border-block-end-style", "border-block-end-color"}},
+	{"border-block-end-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-block-end-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-block-end-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-block-start", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-start-width", "border-block-start-style", "border-block-start-color"}},
+	{"border-block-start-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-block-start-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-block-start-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-block-style", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-start-style", "border-block-end-style"}},
+	{"border-block-width", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-block-start-width", "border-block-end-width"}},
+	{"border-bottom", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-bottom-width", "border-bottom-style", "border-bottom-color"}},
+	{"border-bottom-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-bottom-left-radius", false, "0", allElements, borderBoxSize, "pair of length-percentage", AnimationByComputedValue, "border-radius", nil},
+	{"border-bottom-right-radius", false, "0", allElements, borderBoxSize, "pair of length-percentage", AnimationByComputedValue, "border-radius", nil},
+	{"border-bottom-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-bottom-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-collapse", true, "separate", tableElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"border-color", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-top-color", "border-right-color", "border-bottom-color", "border-left-color"}},
+	{"border-image", false, individually, allElements, individually, individually, AnimationShorthand, "", []string{"border-image-source", "border-image-slice", "border-image-width", "border-image-outset", "border-image-repeat"}},
+	{"border-image-outset", false, "0", allElements, notApplicable, "four values, each a number or absolute length", AnimationByComputedValue, "", nil},
+	{"border-image-repeat", false, "stretch", allElements, notApplicable, "two keywords", AnimationDiscrete, "", nil},
+	{"border-image-slice", false, "100%", allElements, "size of the border image", "one to four numbers or percentages, and the fill keyword", AnimationByComputedValue, "", nil},
+	{"border-image-source", false, "none", allElements, notApplicable, "none or the image with an absolute URL", AnimationDiscrete, "", nil},
+	{"border-image-width", false, "1", allElements, "width or height of the border image area", "four values, each a number, auto or absolute length-percentage", AnimationByComputedValue, "", nil},
+	{"border-inline", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-start-width", "border-inline-start-style", "border-inline-start-color", "border-inline-end-width", "border-inline-end-style", "border-inline-end-color"}},
+	{"border-inline-color", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-start-color", "border-inline-end-color"}},
+	{"border-inline-end", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-end-width", "border-inline-end-style", "border-inline-end-color"}},
+	{"border-inline-end-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-inline-end-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-inline-end-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-inline-start", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-start-width", "border-inline-start-style", "border-inline-start-color"}},
+	{"border-inline-start-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-inline-start-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-inline-start-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-inline-style", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-start-style", "border-inline-end-style"}},
+	{"border-inline-width", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-inline-start-width", "border-inline-end-width"}},
+	{"border-left", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-left-width", "border-left-style", "border-left-color"}},
+	{"border-left-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-left-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-left-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-radius", false, individually, allElements, borderBoxSize, individually, AnimationShorthand, "", []string{"border-top-left-radius", "border-top-right-radius", "border-bottom-right-radius", "border-bottom-left-radius"}},
+	{"border-right", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-right-width", "border-right-style", "border-right-color"}},
+	{"border-right-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-right-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-right-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-spacing", true, "0", tableElements, notApplicable, "two absolute lengths", AnimationByComputedValue, "", nil},
+	{"border-style", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-top-style", "border-right-style", "border-bottom-style", "border-left-style"}},
+	{"border-top", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-top-width", "border-top-style", "border-top-color"}},
+	{"border-top-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "border-color", nil},
+	{"border-top-left-radius", false, "0", allElements, borderBoxSize, "pair of length-percentage", AnimationByComputedValue, "border-radius", nil},
+	{"border-top-right-radius", false, "0", allElements, borderBoxSize, "pair of length-percentage", AnimationByComputedValue, "border-radius", nil},
+	{"border-top-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "border-style", nil},
+	{"border-top-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the border style is none or hidden", AnimationByComputedValue, "border-width", nil},
+	{"border-width", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"border-top-width", "border-right-width", "border-bottom-width", "border-left-width"}},
+	{"bottom", false, "auto", positioned, containingHeight, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"box-shadow", false, "none", allElements, notApplicable, "list of colors, absolute lengths and inset keywords", AnimationByComputedValue, "", nil},
+	{"box-sizing", false, "content-box", "elements that accept width or height", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"break-after", false, "auto", "block-level boxes, grid items, flex items, table row groups, table rows", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"break-before", false, "auto", "block-level boxes, grid items, flex items, table row groups, table rows", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"break-inside", false, "auto", "all elements except inline-level boxes, table columns and absolutely positioned boxes", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"caption-side", true, "top", "table-caption elements", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"clear", false, "none", "block-level elements", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"clip", false, "auto", "absolutely positioned elements", notApplicable, "auto or a rectangle of absolute lengths", AnimationByComputedValue, "", nil},
+	{"color", true, "canvastext", allElements, notApplicable, computedColor, AnimationByComputedValue, "", nil},
+	{"column-gap", false, "normal", "multi-column containers, flex containers, grid containers", "size of the content area", "specified keyword or computed length-percentage", AnimationByComputedValue, "", nil},
+	{"content", false, "normal", "all elements, tree-abiding pseudo-elements, and page margin boxes", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"counter-increment", false, "none", allElements, notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"counter-reset", false, "none", allElements, notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"cursor", true, "auto", allElements, notApplicable, "list of images with absolute URLs and a keyword", AnimationDiscrete, "", nil},
+	{"direction", true, "ltr", allElements, notApplicable, keywordAsSpecified, AnimationNotAnimatable, "", nil},
+	{"display", false, "inline", allElements, notApplicable, "a pair of keywords", AnimationDiscrete, "", nil},
+	{"empty-cells", true, "show", "table-cell elements", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"filter", false, "none", allElements, notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"flex", false, individually, "flex items", individually, individually, AnimationShorthand, "", []string{"flex-grow", "flex-shrink", "flex-basis"}},
+	{"flex-basis", false, "auto", "flex items", "inner main size of the flex container", "specified keyword or computed length-percentage", AnimationByComputedValue, "", nil},
+	{"flex-direction", false, "row", "flex containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"flex-flow", false, individually, "flex containers", notApplicable, individually, AnimationShorthand, "", []string{"flex-direction", "flex-wrap"}},
+	{"flex-grow", false, "0", "flex items", notApplicable, "specified number", AnimationByComputedValue, "", nil},
+	{"flex-shrink", false, "1", "flex items", notApplicable, "specified number", AnimationByComputedValue, "", nil},
+	{"flex-wrap", false, "nowrap", "flex containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"float", false, "none", "all elements, but only applies to elements that are not absolutely positioned", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"font", true, individually, allElements, individually, individually, AnimationShorthand, "", []string{"font-style", "font-variant", "font-weight", "font-stretch", "font-size", "line-height", "font-family"}},
+	{"font-family", true, "depends on user agent", allElements, notApplicable, "list of family names", AnimationDiscrete, "", nil},
+	{"font-feature-settings", true, "normal", allElements, notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"font-size", true, "medium", allElements, "parent element's font size", "absolute length", AnimationByComputedValue, "", nil},
+	{"font-stretch", true, "normal", allElements, notApplicable, "percentage", AnimationByComputedValue, "", nil},
+	{"font-style", true, "normal", allElements, notApplicable, "keyword, with an oblique angle", AnimationByComputedValue, "", nil},
+	{"font-variant", true, "normal", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"font-weight", true, "normal", allElements, notApplicable, "number between 1 and 1000", AnimationByComputedValue, "", nil},
+	{"gap", false, individually, "multi-column containers, flex containers, grid containers", individually, individually, AnimationShorthand, "", []string{"row-gap", "column-gap"}},
+	{"grid", false, individually, "grid containers", individually, individually, AnimationShorthand, "", []string{"grid-template-rows", "grid-template-columns", "grid-template-areas", "grid-auto-rows", "grid-auto-columns", "grid-auto-flow"}},
+	{"grid-area", false, individually, "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, individually, AnimationShorthand, "", []string{"grid-row-start", "grid-column-start", "grid-row-end", "grid-column-end"}},
+	{"grid-auto-columns", false, "auto", "grid containers", "refer to corresponding dimension of the content area", "as specified, with lengths absolute", AnimationByComputedValue, "", nil},
+	{"grid-auto-flow", false, "row", "grid containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"grid-auto-rows", false, "auto", "grid containers", "refer to corresponding dimension of the content area", "as specified, with lengths absolute", AnimationByComputedValue, "", nil},
+	{"grid-column", false, individually, "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, individually, AnimationShorthand, "", []string{"grid-column-start", "grid-column-end"}},
+	{"grid-column-end", false, "auto", "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"grid-column-start", false, "auto", "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"grid-row", false, individually, "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, individually, AnimationShorthand, "", []string{"grid-row-start", "grid-row-end"}},
+	{"grid-row-end", false, "auto", "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"grid-row-start", false, "auto", "grid items and absolutely-positioned boxes whose containing block is a grid container", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"grid-template", false, individually, "grid containers", individually, individually, AnimationShorthand, "", []string{"grid-template-rows", "grid-template-columns", "grid-template-areas"}},
+	{"grid-template-areas", false, "none", "grid containers", notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"grid-template-columns", false, "none", "grid containers", "refer to corresponding dimension of the content area", "as specified, with lengths absolute", AnimationByComputedValue, "", nil},
+	{"grid-template-rows", false, "none", "grid containers", "refer to corresponding dimension of the content area", "as specified, with lengths absolute", AnimationByComputedValue, "", nil},
+	{"height", false, "auto", nonReplacedInlines, containingHeight, "length-percentage or auto", AnimationByComputedValue, "size", nil},
+	{"inset", false, individually, positioned, containingBlock, individually, AnimationShorthand, "", []string{"top", "right", "bottom", "left"}},
+	{"inset-block", false, individually, positioned, containingHeight, individually, AnimationShorthand, "", []string{"inset-block-start", "inset-block-end"}},
+	{"inset-block-end", false, "auto", positioned, containingHeight, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"inset-block-start", false, "auto", positioned, containingHeight, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"inset-inline", false, individually, positioned, containingBlock, individually, AnimationShorthand, "", []string{"inset-inline-start", "inset-inline-end"}},
+	{"inset-inline-end", false, "auto", positioned, containingBlock, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"inset-inline-start", false, "auto", positioned, containingBlock, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"justify-content", false, "normal", "multicol containers, flex containers, and grid containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"justify-items", false, "legacy", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"justify-self", false, "auto", "block-level boxes, absolutely-positioned boxes, and grid items", notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"left", false, "auto", positioned, containingBlock, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"letter-spacing", true, "normal", allElements, notApplicable, "absolute length", AnimationByComputedValue, "", nil},
+	{"line-height", true, "normal", allElements, "font size of the element itself", "normal, number or absolute length", AnimationByComputedValue, "", nil},
+	{"list-style", true, individually, listItems, notApplicable, individually, AnimationShorthand, "", []string{"list-style-position", "list-style-image", "list-style-type"}},
+	{"list-style-image", true, "none", listItems, notApplicable, "none or the image with an absolute URL", AnimationDiscrete, "", nil},
+	{"list-style-position", true, "outside", listItems, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"list-style-type", true, "disc", listItems, notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"margin", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"margin-top", "margin-right", "margin-bottom", "margin-left"}},
+	{"margin-block", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"margin-block-start", "margin-block-end"}},
+	{"margin-block-end", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-block-start", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-bottom", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-inline", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"margin-inline-start", "margin-inline-end"}},
+	{"margin-inline-end", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-inline-start", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-left", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-right", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"margin-top", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "margin", nil},
+	{"max-height", false, "none", nonReplacedInlines, containingHeight, "length-percentage or none", AnimationByComputedValue, "max-size", nil},
+	{"max-width", false, "none", nonReplacedInlines, containingBlock, "length-percentage or none", AnimationByComputedValue, "max-size", nil},
+	{"min-height", false, "auto", nonReplacedInlines, containingHeight, "length-percentage or auto", AnimationByComputedValue, "min-size", nil},
+	{"min-width", false, "auto", nonReplacedInlines, containingBlock, "length-percentage or auto", AnimationByComputedValue, "min-size", nil},
+	{"opacity", false, "1", allElements, "map to the range [0,1]", "number clamped to the range [0,1]", AnimationByComputedValue, "", nil},
+	{"order", false, "0", "flex items and grid items", notApplicable, "specified integer", AnimationByComputedValue, "", nil},
+	{"orphans", true, "2", blockContainers, notApplicable, "positive integer", AnimationByComputedValue, "", nil},
+	{"outline", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"outline-color", "outline-style", "outline-width"}},
+	{"outline-color", false, "auto", allElements, notApplicable, computedColor, AnimationByComputedValue, "", nil},
+	{"outline-offset", false, "0", allElements, notApplicable, absoluteLength, AnimationByComputedValue, "", nil},
+	{"outline-style", false, "none", allElements, notApplicable, keywordAsSpecified, AnimationByComputedValue, "", nil},
+	{"outline-width", false, "medium", allElements, notApplicable, "absolute length, 0 if the outline style is none", AnimationByComputedValue, "", nil},
+	{"overflow", false, individually, "block containers, flex containers and grid containers", notApplicable, individually, AnimationShorthand, "", []string{"overflow-x", "overflow-y"}},
+	{"overflow-wrap", true, "normal", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"overflow-x", false, "visible", "block containers, flex containers and grid containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "overflow", nil},
+	{"overflow-y", false, "visible", "block containers, flex containers and grid containers", notApplicable, keywordAsSpecified, AnimationDiscrete, "overflow", nil},
+	{"padding", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"padding-top", "padding-right", "padding-bottom", "padding-left"}},
+	{"padding-block", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"padding-block-start", "padding-block-end"}},
+	{"padding-block-end", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-block-start", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-bottom", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-inline", false, individually, allElements, containingBlock, individually, AnimationShorthand, "", []string{"padding-inline-start", "padding-inline-end"}},
+	{"padding-inline-end", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-inline-start", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-left", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-right", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"padding-top", false, "0", allElements, containingBlock, lengthPercentage, AnimationByComputedValue, "padding", nil},
+	{"page-break-after", false, individually, "block-level elements", notApplicable, individually, AnimationShorthand, "", []string{"break-after"}},
+	{"page-break-before", false, individually, "block-level elements", notApplicable, individually, AnimationShorthand, "", []string{"break-before"}},
+	{"page-break-inside", false, individually, "block-level elements", notApplicable, individually, AnimationShorthand, "", []string{"break-inside"}},
+	{"perspective", false, "none", "transformable elements", notApplicable, "the keyword none or an absolute length", AnimationByComputedValue, "", nil},
+	{"position", false, "static", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"quotes", true, "auto", allElements, notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"right", false, "auto", positioned, containingBlock, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"rotate", false, "none", "transformable elements", notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"row-gap", false, "normal", "multi-column containers, flex containers, grid containers", "size of the content area", "specified keyword or computed length-percentage", AnimationByComputedValue, "", nil},
+	{"scale", false, "none", "transformable elements", notApplicable, asSpecified, AnimationByComputedValue, "", nil},
+	{"table-layout", false, "auto", tableElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"text-align", true, "start", blockContainers, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"text-decoration", false, individually, allElements, notApplicable, individually, AnimationShorthand, "", []string{"text-decoration-line", "text-decoration-style", "text-decoration-color", "text-decoration-thickness"}},
+	{"text-decoration-color", false, "currentcolor", allElements, notApplicable, computedColor, AnimationByComputedValue, "", nil},
+	{"text-decoration-line", false, "none", allElements, notApplicable, "specified keywords", AnimationDiscrete, "", nil},
+	{"text-decoration-style", false, "solid", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"text-decoration-thickness", false, "auto", allElements, "1em", "auto, from-font or absolute length", AnimationByComputedValue, "", nil},
+	{"text-indent", true, "0", blockContainers, containingBlock, "percentage or absolute length, plus any keywords as specified", AnimationByComputedValue, "", nil},
+	{"text-overflow", false, "clip", blockContainers, notApplicable, asSpecified, AnimationDiscrete, "", nil},
+	{"text-shadow", true, "none", allElements, notApplicable, "list of colors and absolute lengths", AnimationByComputedValue, "", nil},
+	{"text-transform", true, "none", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"text-underline-offset", true, "auto", allElements, "1em", "auto or absolute length", AnimationByComputedValue, "", nil},
+	{"top", false, "auto", positioned, containingHeight, "length-percentage or auto", AnimationByComputedValue, "inset", nil},
+	{"transform", false, "none", "transformable elements", "refer to the size of reference box", "as specified, but with lengths made absolute", AnimationByComputedValue, "", nil},
+	{"transform-origin", false, "50% 50% 0", "transformable elements", "refer to the size of reference box", "see background-position", AnimationByComputedValue, "", nil},
+	{"transition", false, individually, allElements, individually, individually, AnimationShorthand, "", []string{"transition-property", "transition-duration", "transition-timing-function", "transition-delay", "transition-behavior"}},
+	{"transition-behavior", false, "normal", allElements, notApplicable, "as specified", AnimationNotAnimatable, "", nil},
+	{"transition-delay", false, "0s", allElements, notApplicable, "list, each item a duration", AnimationNotAnimatable, "", nil},
+	{"transition-duration", false, "0s", allElements, notApplicable, "list, each item a duration", AnimationNotAnimatable, "", nil},
+	{"transition-property", false, "all", allElements, notApplicable, "as specified", AnimationNotAnimatable, "", nil},
+	{"transition-timing-function", false, "ease", allElements, notApplicable, "list, each item a computed <easing-function>", AnimationNotAnimatable, "", nil},
+	{"translate", false, "none", "transformable elements", "relative to the width and height of the reference box", "as specified, but with lengths made absolute", AnimationByComputedValue, "", nil},
+	{"unicode-bidi", false, "normal", allElements, notApplicable, keywordAsSpecified, AnimationNotAnimatable, "", nil},
+	{"vertical-align", false, "baseline", "inline-level and table-cell elements", "line-height of the element itself", "keyword or absolute length-percentage", AnimationByComputedValue, "", nil},
+	{"visibility", true, "visible", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"white-space", true, "normal", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"widows", true, "2", blockContainers, notApplicable, "positive integer", AnimationByComputedValue, "", nil},
+	{"width", false, "auto", nonReplacedInlines, containingBlock, "length-percentage or auto", AnimationByComputedValue, "size", nil},
+	{"word-break", true, "normal", allElements, notApplicable, keywordAsSpecified, AnimationDiscrete, "", nil},
+	{"word-spacing", true, "normal", allElements, "font size of the element itself", lengthPercentage, AnimationByComputedValue, "", nil},
+	{"z-index", false, "auto", positioned, notApplicable, "integer or auto", AnimationByComputedValue, "", nil},
+}
+
+// propertyMetadata returns the metadata of propertyTable by property name,
+// with the shorthand membership of every longhand filled in.
+func propertyMetadata() map[string]PropertyMetadata {
+	metadata := make(map[string]PropertyMetadata, len(propertyTable))
+	shorthands := make(map[string][]string)
+	for _, row := range propertyTable {
+		metadata[row.name] = PropertyMetadata{
+			Inherited:     row.inherited,
+			Initial:       row.initial,
+			AppliesTo:     row.appliesTo,
+			Percentages:   row.percentages,
+			ComputedValue: row.computed,
+			AnimationType: row.animation,
+			LogicalGroup:  row.group,
+			Longhands:     row.longhands,
+		}
+		for _, longhand := range row.longhands {
+			shorthands[longhand] = append(shorthands[longhand], row.name)
+		}
+	}
+	for name, list := range shorthands {
+		m := metadata[name]
+		m.Shorthands = list
+		metadata[name] = m
+	}
+	return metadata
+}
@@ -0,0 +1,53 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataCoverage(t *testing.T) {
+	for name := range StylesTable {
+		_, ok := DefaultRegistry.Metadata(name)
+		assert.True(t, ok, "missing metadata for %s", name)
+	}
+
+	for _, name := range DefaultRegistry.Names() {
+		m, _ := DefaultRegistry.Metadata(name)
+		for _, longhand := range m.Longhands {
+			_, ok := DefaultRegistry.Metadata(longhand)
+			assert.True(t, ok, "%s has unknown longhand %s", name, longhand)
+		}
+	}
+
+	for i := 1; i < len(propertyTable); i++ {
+		assert.Less(t, propertyTable[i-1].name, propertyTable[i].name, "property table should be sorted")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	m, ok := DefaultRegistry.Metadata("margin-top")
+	assert.True(t, ok)
+	assert.Equal(t, PropertyMetadata{
+		Initial:       "0",
+		AppliesTo:     "all elements",
+		Percentages:   "width of containing block",
+		ComputedValue: "absolute length or percentage",
+		AnimationType: AnimationByComputedValue,
+		LogicalGroup:  "margin",
+		Shorthands:    []string{"margin"},
+	}, m)
+	assert.False(t, m.IsShorthand())
+
+	m, _ = DefaultRegistry.Metadata("border-top-width")
+	assert.Equal(t, []string{"border", "border-top", "border-width"}, m.Shorthands)
+
+	m, _ = DefaultRegistry.Metadata("font")
+	assert.True(t, m.Inherited)
+	assert.True(t, m.IsShorthand())
+	assert.Equal(t, "see individual properties", m.AnimationType.String())
+
+	m, _ = DefaultRegistry.Metadata("visibility")
+	assert.True(t, m.Inherited)
+	assert.Equal(t, AnimationDiscrete, m.AnimationType)
+}
@@ -0,0 +1,406 @@
+package css
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// legacyPageBreaks maps the values of page-break-before and
+// page-break-after to those of break-before and break-after.
+var legacyPageBreaks = map[string]string{"always": "page"}
+
+// pageBreak converts page-break-before, page-break-after and
+// page-break-inside to the value of the break property they alias.
+func pageBreak(values []ComponentValue) interface{} {
+	keyword := strings.ToLower(values[0].Text)
+	if b, ok := legacyPageBreaks[keyword]; ok {
+		return b
+	}
+	return keyword
+}
+
+func expandPageBreak(name string) func(values []ComponentValue) map[string]string {
+	return func(values []ComponentValue) map[string]string {
+		return map[string]string{name: pageBreak(values).(string)}
+	}
+}
+
+func integerValue(values []ComponentValue) interface{} {
+	if values[0].Type == FunctionValue {
+		n, _ := evalMath(values[0], nil)
+		return toInteger(n)
+	}
+	return toInteger(values[0].Number)
+}
+
+// toInteger rounds n to the nearest integer, clamped to the range of
+// int32 like browsers do, so that huge values don't overflow.
+func toInteger(n float64) int {
+	if math.IsNaN(n) {
+		return 0
+	}
+	return int(math.Round(clamp(n, math.MinInt32, math.MaxInt32)))
+}
+
+// PageSize is the typed value of the size descriptor of @page rules.
+type PageSize struct {
+	// Auto is set if the page size is up to the user agent.
+	Auto bool
+	// Width and Height are absolute lengths, or zero if only the
+	// orientation is given.
+	Width, Height Length
+	// Name is the lower case name of a standard size like "a4", if one was
+	// given.
+	Name string
+	// Orientation is "portrait", "landscape" or empty.
+	Orientation string
+}
+
+// pageSizes are the standard page sizes in portrait orientation.
+var pageSizes = map[string][2]Length{
+	"a5":     {{Value: 148, Unit: "mm"}, {Value: 210, Unit: "mm"}},
+	"a4":     {{Value: 210, Unit: "mm"}, {Value: 297, Unit: "mm"}},
+	"a3":     {{Value: 297, Unit: "mm"}, {Value: 420, Unit: "mm"}},
+	"b5":     {{Value: 176, Unit: "mm"}, {Value: 250, Unit: "mm"}},
+	"b4":     {{Value: 250, Unit: "mm"}, {Value: 353, Unit: "mm"}},
+	"jis-b5": {{Value: 182, Unit: "mm"}, {Value: 257, Unit: "mm"}},
+	"jis-b4": {{Value: 257, Unit: "mm"}, {Value: 364, Unit: "mm"}},
+	"letter": {{Value: 8.5, Unit: "in"}, {Value: 11, Unit: "in"}},
+	"legal":  {{Value: 8.5, Unit: "in"}, {Value: 14, Unit: "in"}},
+	"ledger": {{Value: 11, Unit: "in"}, {Value: 17, Unit: "in"}},
+}
+
+var pageSizeGrammar = MustCompileGrammar("<length [0,∞]>{1,2} | auto | [ <page-size> || [ portrait | landscape ] ]")
+
+// ParsePageSize parses the value of the size descriptor.
+func ParsePageSize(value string) (PageSize, error) {
+	values, err := pageSizeGrammar.Match(value)
+	if err != nil {
+		return PageSize{}, err
+	}
+	var size PageSize
+	lengths := 0
+	for _, v := range values {
+		switch {
+		case v.Is("auto"):
+			size.Auto = true
+		case v.Is("portrait"), v.Is("landscape"):
+			size.Orientation = strings.ToLower(v.Text)
+		case v.Type == IdentValue:
+			size.Name = strings.ToLower(v.Text)
+			dimensions := pageSizes[size.Name]
+			size.Width, size.Height = dimensions[0], dimensions[1]
+		case lengths == 0:
+			size.Width, size.Height = lengthOf(v), lengthOf(v)
+			lengths++
+		default:
+			size.Height = lengthOf(v)
+		}
+	}
+	if size.Orientation == "landscape" && size.Name != "" {
+		size.Width, size.Height = size.Height, size.Width
+	}
+	return size, nil
+}
+
+// PageSelector selects pages by name and the :first, :left, :right and
+// :blank pseudo-classes.
+type PageSelector struct {
+	// Name is the page name, or empty to match all pages.
+	Name          string
+	PseudoClasses []string
+}
+
+func (s PageSelector) String() string {
+	if len(s.PseudoClasses) == 0 {
+		return s.Name
+	}
+	return s.Name + ":" + strings.Join(s.PseudoClasses, ":")
+}
+
+// Page describes a page of a document to match page rules against.
+type Page struct {
+	// Name is the page name from the page property, or empty.
+	Name string
+	// Number is the 1-based page index in the document.
+	Number int
+	// Blank is set for pages inserted to satisfy a forced break.
+	Blank bool
+	// RTL is set if the document progresses from right to left, which
+	// makes the first page a left page.
+	RTL bool
+}
+
+// IsLeft reports whether p is a left page.
+func (p Page) IsLeft() bool {
+	return (p.Number%2 == 0) != p.RTL
+}
+
+// Matches reports whether the selector matches the page p.
+func (s PageSelector) Matches(p Page) bool {
+	if s.Name != "" && s.Name != p.Name {
+		return false
+	}
+	for _, pseudo := range s.PseudoClasses {
+		switch pseudo {
+		case "first":
+			if p.Number != 1 {
+				return false
+			}
+		case "blank":
+			if !p.Blank {
+				return false
+			}
+		case "left":
+			if !p.IsLeft() {
+				return false
+			}
+		case "right":
+			if p.IsLeft() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// specificity returns the specificity of the selector: names count most,
+// then :first and :blank, then :left and :right.
+func (s PageSelector) specificity() int {
+	n := 0
+	if s.Name != "" {
+		n += 100
+	}
+	for _, pseudo := range s.PseudoClasses {
+		if pseudo == "first" || pseudo == "blank" {
+			n += 10
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// pageMarginBoxes are the at-rules of the margin boxes of a page.
+var pageMarginBoxes = map[string]bool{
+	"top-left-corner": true, "top-left": true, "top-center": true, "top-right": true, "top-right-corner": true,
+	"bottom-left-corner": true, "bottom-left": true, "bottom-center": true, "bottom-right": true, "bottom-right-corner": true,
+	"left-top": true, "left-middle": true, "left-bottom": true,
+	"right-top": true, "right-middle": true, "right-bottom": true,
+}
+
+// PageRule is a @page rule.
+type PageRule struct {
+	// Selectors are the comma separated selectors of the rule, or nil if
+	// it applies to all pages.
+	Selectors []PageSelector
+	// Size is the size descriptor, Auto if the rule doesn't set it.
+	Size PageSize
+	// Declarations are the descriptors of the page context, with the
+	// margin shorthand expanded to its longhands.
+	Declarations map[string]string
+	// MarginBoxes are the declarations of the margin boxes by name, like
+	// "top-center".
+	MarginBoxes map[string]map[string]string
+}
+
+// Margins returns the page margins set by the rule in top, right, bottom,
+// left order. Margins the rule doesn't set are zero.
+func (r PageRule) Margins() [4]Length {
+	var margins [4]Length
+	for i, side := range physicalSides {
+		if value, ok := r.Declarations["margin-"+side]; ok {
+			if values, err := ParseValue(value); err == nil && len(values) == 1 {
+				margins[i] = lengthOf(values[0])
+			}
+		}
+	}
+	return margins
+}
+
+// specificity returns the specificity of the most specific selector of r
+// that matches p, or -1 if r doesn't match.
+func (r PageRule) specificity(p Page) int {
+	if len(r.Selectors) == 0 {
+		return 0
+	}
+	specificity := -1
+	for _, s := range r.Selectors {
+		if s.Matches(p) && s.specificity() > specificity {
+			specificity = s.specificity()
+		}
+	}
+	return specificity
+}
+
+// PageContext cascades the rules that match page p and returns its
+// declarations and the declarations of its margin boxes. Later rules
+// override earlier ones of the same specificity.
+func PageContext(rules []PageRule, p Page) (map[string]string, map[string]map[string]string) {
+	type match struct {
+		rule        PageRule
+		specificity int
+	}
+	var matches []match
+	for _, r := range rules {
+		if s := r.specificity(p); s >= 0 {
+			matches = append(matches, match{r, s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].specificity < matches[j].specificity
+	})
+
+	declarations := make(map[string]string)
+	boxes := make(map[string]map[string]string)
+	for _, m := range matches {
+		for name, value := range m.rule.Declarations {
+			declarations[name] = value
+		}
+		for box, boxDeclarations := range m.rule.MarginBoxes {
+			if boxes[box] == nil {
+				boxes[box] = make(map[string]string)
+			}
+			for name, value := range boxDeclarations {
+				boxes[box][name] = value
+			}
+		}
+	}
+	return declarations, boxes
+}
+
+// UnmarshalPages returns the top level @page rules of a stylesheet in
+// the order they appear.
+func UnmarshalPages(b []byte) ([]PageRule, error) {
+	h := &pageHandler{}
+	if err := parseBytes(context.Background(), b, h); err != nil {
+		return h.rules, err
+	}
+	return h.rules, nil
+}
+
+// pageHandler collects the @page rules of a stylesheet.
+type pageHandler struct {
+	NopHandler
+
+	rules []PageRule
+	// box is the name of the margin box being read, if any
+	box   string
+	depth int
+	// page is set while a top level @page rule is read
+	page bool
+}
+
+func (h *pageHandler) StartRule(selectors []string) error {
+	h.depth++
+	return nil
+}
+
+func (h *pageHandler) EndRule() error {
+	h.depth--
+	return nil
+}
+
+func (h *pageHandler) StartAtRule(name, prelude string) error {
+	h.depth++
+	switch {
+	case h.depth == 1 && name == "page":
+		selectors, err := parsePageSelectors(prelude)
+		if err != nil {
+			return err
+		}
+		h.rules = append(h.rules, PageRule{
+			Selectors:    selectors,
+			Size:         PageSize{Auto: true},
+			Declarations: make(map[string]string),
+			MarginBoxes:  make(map[string]map[string]string),
+		})
+		h.page = true
+	case h.depth == 2 && h.page:
+		if !pageMarginBoxes[name] {
+			return fmt.Errorf("unknown margin box @%s in @page", name)
+		}
+		h.box = name
+		rule := h.rules[len(h.rules)-1]
+		if rule.MarginBoxes[name] == nil {
+			rule.MarginBoxes[name] = make(map[string]string)
+		}
+	}
+	return nil
+}
+
+func (h *pageHandler) Declaration(name, value string, important bool) error {
+	if !h.page {
+		return nil
+	}
+	rule := &h.rules[len(h.rules)-1]
+	name = strings.ToLower(name)
+	switch {
+	case h.depth == 2 && h.box != "":
+		rule.MarginBoxes[h.box][name] = value
+	case h.depth != 1:
+	case name == "size":
+		size, err := ParsePageSize(value)
+		if err != nil {
+			return fmt.Errorf("@page size: %w", err)
+		}
+		rule.Size = size
+		rule.Declarations[name] = value
+	case name == "margin":
+		longhands, err := ExpandShorthand(name, value)
+		if err != nil {
+			return fmt.Errorf("@page margin: %w", err)
+		}
+		for longhand, v := range longhands {
+			rule.Declarations[longhand] = v
+		}
+	default:
+		rule.Declarations[name] = value
+	}
+	return nil
+}
+
+func (h *pageHandler) EndAtRule(name string) error {
+	h.depth--
+	switch h.depth {
+	case 0:
+		h.page = false
+	case 1:
+		h.box = ""
+	}
+	return nil
+}
+
+// parsePageSelectors parses the prelude of a @page rule, like
+// "chapter:first, :left".
+func parsePageSelectors(prelude string) ([]PageSelector, error) {
+	prelude = strings.TrimSpace(prelude)
+	if prelude == "" {
+		return nil, nil
+	}
+	var selectors []PageSelector
+	for _, part := range strings.Split(prelude, ",") {
+		parts := strings.Split(strings.TrimSpace(part), ":")
+		s := PageSelector{Name: parts[0]}
+		if s.Name != "" && !isIdentifier(s.Name) {
+			return nil, fmt.Errorf("invalid page name %q", s.Name)
+		}
+		for _, pseudo := range parts[1:] {
+			pseudo = strings.ToLower(pseudo)
+			switch pseudo {
+			case "first", "left", "right", "blank":
+				s.PseudoClasses = append(s.PseudoClasses, pseudo)
+			default:
+				return nil, fmt.Errorf("invalid page selector %q", part)
+			}
+		}
+		if s.Name == "" && len(s.PseudoClasses) == 0 {
+			return nil, fmt.Errorf("invalid page selector %q", part)
+		}
+		selectors = append(selectors, s)
+	}
+	return selectors, nil
+}
@@ -0,0 +1,136 @@
+package css
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakProperties(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"break-before", "recto", "recto"},
+		{"break-after", "Avoid-Column", "avoid-column"},
+		{"break-inside", "avoid-page", "avoid-page"},
+		{"page-break-before", "always", "page"},
+		{"page-break-after", "left", "left"},
+		{"page-break-inside", "avoid", "avoid"},
+		{"orphans", "3", 3},
+		{"widows", "calc(1 + 1)", 2},
+		{"widows", "calc(2.6)", 3},
+		{"orphans", "calc(1e30)", math.MaxInt32},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"break-inside":      "page",
+		"page-break-before": "page",
+		"page-break-inside": "always",
+		"orphans":           "0",
+		"widows":            "1.5",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	longhands, err := ExpandShorthand("page-break-after", "always")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"break-after": "page"}, longhands)
+
+	m, _ := DefaultRegistry.Metadata("break-before")
+	assert.Equal(t, []string{"page-break-before"}, m.Shorthands)
+}
+
+func TestParsePageSize(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected PageSize
+	}{
+		{"auto", PageSize{Auto: true}},
+		{"A4", PageSize{Name: "a4", Width: Length{Value: 210, Unit: "mm"}, Height: Length{Value: 297, Unit: "mm"}}},
+		{"landscape letter", PageSize{Name: "letter", Orientation: "landscape", Width: Length{Value: 11, Unit: "in"}, Height: Length{Value: 8.5, Unit: "in"}}},
+		{"portrait", PageSize{Orientation: "portrait"}},
+		{"10cm", PageSize{Width: Length{Value: 10, Unit: "cm"}, Height: Length{Value: 10, Unit: "cm"}}},
+		{"0 5in", PageSize{Width: Length{}, Height: Length{Value: 5, Unit: "in"}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := ParsePageSize(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+
+	for _, value := range []string{"A6", "-1in", "auto landscape", "1in 2in 3in", "landscape portrait"} {
+		_, err := ParsePageSize(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestUnmarshalPages(t *testing.T) {
+	rules, err := UnmarshalPages([]byte(`
+		body { margin: 0 }
+		@page { size: A4; margin: 2cm 1cm; }
+		@page :first { margin-top: 5cm; @top-center { content: "Title" } }
+		@page chapter:left, :blank {
+			@bottom-left-corner { content: counter(page); }
+			@bottom-left { content: "left"; }
+		}
+		@page :right { @top-right { content: "right" } }
+		@media print { @page { size: letter } }
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, rules, 4)
+
+	assert.Nil(t, rules[0].Selectors)
+	assert.Equal(t, "a4", rules[0].Size.Name)
+	assert.Equal(t, [4]Length{{Value: 2, Unit: "cm"}, {Value: 1, Unit: "cm"}, {Value: 2, Unit: "cm"}, {Value: 1, Unit: "cm"}}, rules[0].Margins())
+	assert.Equal(t, []PageSelector{{PseudoClasses: []string{"first"}}}, rules[1].Selectors)
+	assert.True(t, rules[1].Size.Auto)
+	assert.Equal(t, map[string]map[string]string{"top-center": {"content": `"Title"`}}, rules[1].MarginBoxes)
+	assert.Equal(t, []PageSelector{{Name: "chapter", PseudoClasses: []string{"left"}}, {PseudoClasses: []string{"blank"}}}, rules[2].Selectors)
+	assert.Equal(t, "counter(page)", rules[2].MarginBoxes["bottom-left-corner"]["content"])
+
+	declarations, boxes := PageContext(rules, Page{Number: 1})
+	assert.Equal(t, "5cm", declarations["margin-top"])
+	assert.Equal(t, "1cm", declarations["margin-left"])
+	assert.Equal(t, `"Title"`, boxes["top-center"]["content"])
+	assert.Equal(t, `"right"`, boxes["top-right"]["content"])
+
+	_, boxes = PageContext(rules, Page{Name: "chapter", Number: 4})
+	assert.Equal(t, `"left"`, boxes["bottom-left"]["content"])
+	assert.Nil(t, boxes["top-right"])
+
+	_, boxes = PageContext(rules, Page{Number: 3, Blank: true, RTL: true})
+	assert.Equal(t, `"left"`, boxes["bottom-left"]["content"])
+
+	for _, css := range []string{
+		`@page :middle {}`,
+		`@page { size: A7 }`,
+		`@page { @top-middle { content: "x" } }`,
+		`@page { margin: 1px 2px 3px 4px 5px }`,
+	} {
+		_, err := UnmarshalPages([]byte(css))
+		assert.Error(t, err, css)
+	}
+}
@@ -1,7 +1,9 @@
 package css
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +53,19 @@ rule1 {
 	style: value;
 }`
 
+	ex9 := `h1, h2 {
+	style: value !important;
+	last: value
+}
+@media print {
+	h1 { style: other; }
+}`
+
+	ex10 := `rule {
+	margin:   0
+		/* vertical */ auto;
+}`
+
 	cases := []struct {
 		name     string
 		CSS      string
@@ -103,6 +118,21 @@ rule1 {
 				"style": "value",
 			},
 		}},
+		{"Comma separated rules", ex9, map[Rule]map[string]string{
+			"h1": {
+				"style": "value !important",
+				"last":  "value",
+			},
+			"h2": {
+				"style": "value !important",
+				"last":  "value",
+			},
+		}},
+		{"Multiline value", ex10, map[Rule]map[string]string{
+			"rule": {
+				"margin": "0 auto",
+			},
+		}},
 	}
 
 	for _, tt := range cases {
@@ -164,6 +194,20 @@ body {
 	}
 }
 
+func TestUnmarshalContext(t *testing.T) {
+	styleSheet := largeStylesheet(100)
+
+	css, err := UnmarshalContext(context.Background(), styleSheet)
+	assert.NoError(t, err)
+	assert.Len(t, css, 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = UnmarshalContext(ctx, styleSheet)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "line 1: context canceled")
+}
+
 func BenchmarkParser(b *testing.B) {
 	ex1 := ""
 	for i := 0; i < 100; i++ {
@@ -180,3 +224,32 @@ func BenchmarkParser(b *testing.B) {
 		}
 	}
 }
+
+// largeStylesheet generates a stylesheet of roughly 220 bytes per rule,
+// similar to the output of CSS bundlers.
+func largeStylesheet(rules int) []byte {
+	var sb strings.Builder
+	for i := 0; i < rules; i++ {
+		if i%10 == 0 {
+			fmt.Fprintf(&sb, "/* section %d */\n", i/10)
+		}
+		fmt.Fprintf(&sb, ".component-%d .item-%d {\n", i, i%7)
+		fmt.Fprintf(&sb, "\tdisplay: block;\n\tmargin: 0 auto;\n\tpadding: %dpx %dpx;\n", i%16, i%24)
+		fmt.Fprintf(&sb, "\tfont-family: 'Helvetica Neue', Arial, sans-serif;\n\tcolor: #%06x;\n", i*2654435761%0xffffff)
+		fmt.Fprintf(&sb, "\tbackground-image: url(\"img/bg-%d.png\");\n\tborder: 1px solid #ccc;\n}\n", i)
+	}
+	return []byte(sb.String())
+}
+
+func BenchmarkUnmarshalLarge(b *testing.B) {
+	styleSheet := largeStylesheet(2000)
+	b.SetBytes(int64(len(styleSheet)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Unmarshal(styleSheet)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
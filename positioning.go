@@ -0,0 +1,117 @@
+package css
+
+import (
+	"strings"
+)
+
+// PositionScheme is the typed value of the position property.
+type PositionScheme int
+
+const (
+	PositionStatic PositionScheme = iota
+	PositionRelative
+	PositionAbsolute
+	PositionSticky
+	PositionFixed
+)
+
+var positionSchemes = []string{"static", "relative", "absolute", "sticky", "fixed"}
+
+func (p PositionScheme) String() string {
+	return enumString(positionSchemes, int(p))
+}
+
+// IsAbsolute reports whether p takes the box out of flow, which is the
+// case for absolute and fixed positioning.
+func (p PositionScheme) IsAbsolute() bool {
+	return p == PositionAbsolute || p == PositionFixed
+}
+
+// Float is the typed value of the float property.
+type Float int
+
+const (
+	FloatNone Float = iota
+	FloatLeft
+	FloatRight
+	FloatInlineStart
+	FloatInlineEnd
+)
+
+var floats = []string{"none", "left", "right", "inline-start", "inline-end"}
+
+func (f Float) String() string {
+	return enumString(floats, int(f))
+}
+
+// Clear is the typed value of the clear property.
+type Clear int
+
+const (
+	ClearNone Clear = iota
+	ClearLeft
+	ClearRight
+	ClearBoth
+	ClearInlineStart
+	ClearInlineEnd
+)
+
+var clears = []string{"none", "left", "right", "both", "inline-start", "inline-end"}
+
+func (c Clear) String() string {
+	return enumString(clears, int(c))
+}
+
+// ZIndex is the typed value of the z-index property.
+type ZIndex struct {
+	Auto  bool
+	Value int
+}
+
+func (z ZIndex) String() string {
+	if z.Auto {
+		return "auto"
+	}
+	return formatNumber(float64(z.Value))
+}
+
+// enumString returns the keyword of an enum value.
+func enumString(keywords []string, i int) string {
+	if i < 0 || i >= len(keywords) {
+		return "unknown"
+	}
+	return keywords[i]
+}
+
+// enumIndex returns the index of the keyword v in keywords.
+func enumIndex(keywords []string, v ComponentValue) int {
+	for i, k := range keywords {
+		if strings.EqualFold(v.Text, k) {
+			return i
+		}
+	}
+	return -1
+}
+
+func positionScheme(values []ComponentValue) interface{} {
+	return PositionScheme(enumIndex(positionSchemes, values[0]))
+}
+
+func float(values []ComponentValue) interface{} {
+	return Float(enumIndex(floats, values[0]))
+}
+
+func clear(values []ComponentValue) interface{} {
+	return Clear(enumIndex(clears, values[0]))
+}
+
+func zIndex(values []ComponentValue) interface{} {
+	if values[0].Is("auto") {
+		return ZIndex{Auto: true}
+	}
+	n := values[0].Number
+	if values[0].Type == FunctionValue {
+		n, _ = evalMath(values[0], nil)
+	}
+	return ZIndex{Value: toInteger(n)}
+}
@@ -0,0 +1,82 @@
+package css
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositioning(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"position", "static", PositionStatic},
+		{"position", "Sticky", PositionSticky},
+		{"position", "fixed", PositionFixed},
+		{"float", "inline-end", FloatInlineEnd},
+		{"float", "none", FloatNone},
+		{"clear", "both", ClearBoth},
+		{"clear", "left", ClearLeft},
+		{"z-index", "auto", ZIndex{Auto: true}},
+		{"z-index", "-3", ZIndex{Value: -3}},
+		{"z-index", "calc(2 * 5)", ZIndex{Value: 10}},
+		{"z-index", "calc(1e30)", ZIndex{Value: math.MaxInt32}},
+		{"z-index", "calc(-1e30)", ZIndex{Value: math.MinInt32}},
+		{"top", "auto", Length{Keyword: "auto"}},
+		{"right", "-10px", Length{Value: -10, Unit: "px"}},
+		{"bottom", "50%", percent(50)},
+		{"left", "0", Length{}},
+		{"inset", "1px auto", [4]Length{{Value: 1, Unit: "px"}, {Keyword: "auto"}, {Value: 1, Unit: "px"}, {Keyword: "auto"}}},
+		{"inset-inline", "10%", [2]Length{percent(10), percent(10)}},
+		{"inset-block-start", "2em", Length{Value: 2, Unit: "em"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"position": "floating",
+		"float":    "center",
+		"clear":    "auto",
+		"z-index":  "1.5",
+		"top":      "none",
+		"inset":    "1px 2px 3px 4px 5px",
+		"bottom":   "calc(1px * 2px)",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+
+	assert.True(t, PositionFixed.IsAbsolute())
+	assert.False(t, PositionSticky.IsAbsolute())
+	assert.Equal(t, "inline-start", ClearInlineStart.String())
+	assert.Equal(t, "-2", ZIndex{Value: -2}.String())
+}
+
+func TestExpandInset(t *testing.T) {
+	longhands, err := ExpandShorthand("inset", "0 auto 10%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"top": "0", "right": "auto", "bottom": "10%", "left": "auto"}, longhands)
+
+	longhands, err = ExpandShorthand("inset-block", "1px 2px")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"inset-block-start": "1px", "inset-block-end": "2px"}, longhands)
+
+	value, err := CollapseShorthand("inset", longhands)
+	assert.Error(t, err)
+	assert.Empty(t, value)
+}
@@ -0,0 +1,184 @@
+package css
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// PropertyMetadata describes a CSS property beyond the syntax of its value,
+// following the property definition tables of the CSS specifications.
+type PropertyMetadata struct {
+	// Inherited reports whether the property is inherited by default.
+	Inherited bool
+	// Initial is the initial value of the property.
+	Initial string
+	// AppliesTo describes the elements the property applies to.
+	AppliesTo string
+	// Percentages describes what percentages refer to, "n/a" if the
+	// property doesn't accept them.
+	Percentages string
+	// ComputedValue describes the kind of the computed value.
+	ComputedValue string
+	// AnimationType describes how the property is interpolated.
+	AnimationType AnimationType
+	// LogicalGroup is the group of related logical and physical properties,
+	// e.g. "margin" for margin-top, or empty.
+	LogicalGroup string
+	// Shorthands lists the shorthand properties that set this property.
+	Shorthands []string
+	// Longhands lists the properties set by a shorthand property.
+	Longhands []string
+}
+
+// IsShorthand reports whether the metadata belongs to a shorthand property.
+func (m PropertyMetadata) IsShorthand() bool {
+	return len(m.Longhands) > 0
+}
+
+// clone returns a copy of m that doesn't share its slices.
+func (m PropertyMetadata) clone() PropertyMetadata {
+	m.Shorthands = append([]string(nil), m.Shorthands...)
+	m.Longhands = append([]string(nil), m.Longhands...)
+	return m
+}
+
+type property struct {
+	handler  StyleHandler
+	metadata PropertyMetadata
+}
+
+// Registry holds the style handlers and metadata of CSS properties. It is
+// safe for concurrent use, so libraries can register their own properties
+// while styles are being checked.
+type Registry struct {
+	mu         sync.RWMutex
+	properties map[string]property
+}
+
+// DefaultRegistry is the registry used by CSSStyle. It contains the
+// metadata of all properties known to the package, and handlers that call
+// the handlers of StylesTable until other handlers are registered.
+var DefaultRegistry = newRegistry(StylesTable, propertyMetadata())
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		properties: make(map[string]property),
+	}
+}
+
+func newRegistry(table map[string]StyleHandler, metadata map[string]PropertyMetadata) *Registry {
+	r := NewRegistry()
+	for name := range table {
+		r.properties[name] = property{handler: tableHandler(table, name)}
+	}
+	for name, m := range metadata {
+		p := r.properties[name]
+		p.metadata = m
+		r.properties[name] = p
+	}
+	return r
+}
+
+// tableHandler returns a handler that calls the handler of name in table
+// when a style is checked, so that overwriting it keeps working.
+func tableHandler(table map[string]StyleHandler, name string) StyleHandler {
+	return func(value string) (Style, error) {
+		styleFn := table[name]
+		if styleFn == nil {
+			return Style{}, errors.New("unknown style")
+		}
+		return styleFn(value)
+	}
+}
+
+// Register adds the property name to the registry, replacing any previous
+// handler and metadata. A nil handler registers only the metadata.
+func (r *Registry) Register(name string, handler StyleHandler, metadata PropertyMetadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.properties[name] = property{
+		handler:  handler,
+		metadata: metadata.clone(),
+	}
+}
+
+// RegisterHandler replaces the style handler of the property name, keeping
+// its metadata.
+func (r *Registry) RegisterHandler(name string, handler StyleHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.properties[name]
+	p.handler = handler
+	r.properties[name] = p
+}
+
+// Lookup returns the style handler of the property name.
+func (r *Registry) Lookup(name string) (StyleHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.properties[name]
+	return p.handler, ok && p.handler != nil
+}
+
+// Metadata returns the metadata of the property name.
+func (r *Registry) Metadata(name string) (PropertyMetadata, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.properties[name]
+	return p.metadata.clone(), ok
+}
+
+// Names returns the sorted names of all registered properties.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.properties))
+	for name := range r.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Clone returns a copy of the registry that can be modified independently.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := &Registry{
+		properties: make(map[string]property, len(r.properties)),
+	}
+	for name, p := range r.properties {
+		p.metadata = p.metadata.clone()
+		clone.properties[name] = p
+	}
+	return clone
+}
+
+// CSSStyle returns an error-checked parsed style, or an error if the
+// style is not registered.
+func (r *Registry) CSSStyle(name string, styles map[string]string) (Style, error) {
+	styleFn, ok := r.Lookup(name)
+	if !ok {
+		return Style{}, errors.New("unknown style")
+	}
+	return styleFn(styles[name])
+}
+
+// CSSStyle returns an error-checked parsed style, or an error if the
+// style is unknown. Most of the styles are not supported yet.
+//
+// Styles are checked with DefaultRegistry, which calls the handlers of
+// StylesTable for the built-in properties. Handlers added to StylesTable
+// for other properties are used if the registry doesn't know them, so code
+// that modifies it keeps working.
+func CSSStyle(name string, styles map[string]string) (Style, error) {
+	if styleFn, ok := DefaultRegistry.Lookup(name); ok {
+		return styleFn(styles[name])
+	}
+	if styleFn, ok := StylesTable[name]; ok {
+		return styleFn(styles[name])
+	}
+	return Style{}, errors.New("unknown style")
+}
@@ -0,0 +1,166 @@
+package css
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// backgroundColor is a handler that keeps valid colors as specified, like
+// the handler of background-color did before its value became a Color.
+func backgroundColor(value string) (Style, error) {
+	if _, err := ParseColor(value); err != nil {
+		return Style{}, err
+	}
+	return Style{Value: value}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	_, err := r.CSSStyle("background-color", map[string]string{"background-color": "red"})
+	assert.Error(t, err, "empty registry should not know any style")
+
+	r.Register("background-color", backgroundColor, PropertyMetadata{Initial: "transparent"})
+	style, err := r.CSSStyle("background-color", map[string]string{"background-color": "red"})
+	assert.NoError(t, err)
+	assert.Equal(t, "red", style.String())
+
+	metadata, ok := r.Metadata("background-color")
+	assert.True(t, ok)
+	assert.Equal(t, "transparent", metadata.Initial)
+
+	r.Register("-x-custom", nil, PropertyMetadata{Inherited: true})
+	_, ok = r.Lookup("-x-custom")
+	assert.False(t, ok, "metadata only properties have no handler")
+	metadata, ok = r.Metadata("-x-custom")
+	assert.True(t, ok)
+	assert.True(t, metadata.Inherited)
+	assert.Equal(t, []string{"-x-custom", "background-color"}, r.Names())
+}
+
+func TestRegistryClone(t *testing.T) {
+	clone := DefaultRegistry.Clone()
+	clone.Register("background-color", func(value string) (Style, error) {
+		return Style{Value: "overridden"}, nil
+	}, PropertyMetadata{})
+	clone.Register("-x-custom", backgroundColor, PropertyMetadata{})
+
+	style, err := clone.CSSStyle("background-color", map[string]string{"background-color": "bla"})
+	assert.NoError(t, err)
+	assert.Equal(t, "overridden", style.Value)
+
+	_, err = DefaultRegistry.CSSStyle("background-color", map[string]string{"background-color": "bla"})
+	assert.Error(t, err, "the default registry must not change")
+	_, ok := DefaultRegistry.Lookup("-x-custom")
+	assert.False(t, ok)
+}
+
+func TestRegistryMetadata(t *testing.T) {
+	r := DefaultRegistry.Clone()
+	builtin, _ := r.Metadata("margin-top")
+
+	r.RegisterHandler("margin-top", backgroundColor)
+	style, err := r.CSSStyle("margin-top", map[string]string{"margin-top": "red"})
+	assert.NoError(t, err)
+	assert.Equal(t, "red", style.Value)
+	metadata, _ := r.Metadata("margin-top")
+	assert.Equal(t, builtin, metadata, "RegisterHandler keeps the metadata")
+
+	metadata.Shorthands[0] = "changed"
+	metadata, _ = r.Metadata("margin-top")
+	assert.Equal(t, "margin", metadata.Shorthands[0], "returned metadata must not alias the registry")
+
+	shorthands := []string{"-x-shorthand"}
+	r.Register("-x-longhand", nil, PropertyMetadata{Shorthands: shorthands})
+	shorthands[0] = "changed"
+	metadata, _ = r.Metadata("-x-longhand")
+	assert.Equal(t, []string{"-x-shorthand"}, metadata.Shorthands, "registered metadata must be copied")
+
+	clone := r.Clone()
+	clone.RegisterHandler("margin-top", nil)
+	clone.Register("-x-longhand", nil, PropertyMetadata{Shorthands: []string{"-x-cloned"}})
+	_, ok := r.Lookup("margin-top")
+	assert.True(t, ok)
+	metadata, _ = r.Metadata("-x-longhand")
+	assert.Equal(t, []string{"-x-shorthand"}, metadata.Shorthands)
+}
+
+func TestRegistryReplaceMetadata(t *testing.T) {
+	// Register replaces every field, also with zero values
+	cases := []struct {
+		name     string
+		metadata PropertyMetadata
+	}{
+		{"color", PropertyMetadata{Inherited: false, Initial: "canvastext"}},
+		{"opacity", PropertyMetadata{AnimationType: AnimationDiscrete}},
+		{"margin-top", PropertyMetadata{Initial: "auto", AppliesTo: "boxes", Percentages: "n/a", ComputedValue: "length"}},
+		{"margin-top", PropertyMetadata{Shorthands: nil, LogicalGroup: ""}},
+		{"margin", PropertyMetadata{Longhands: nil}},
+	}
+	for _, tt := range cases {
+		r := DefaultRegistry.Clone()
+		handler, _ := r.Lookup(tt.name)
+		builtin, _ := r.Metadata(tt.name)
+		assert.NotEqual(t, builtin, tt.metadata, tt.name)
+		r.Register(tt.name, handler, tt.metadata)
+		metadata, ok := r.Metadata(tt.name)
+		assert.True(t, ok, tt.name)
+		assert.Equal(t, tt.metadata, metadata, tt.name)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	r := DefaultRegistry.Clone()
+	styles := map[string]string{"background-color": "#fff"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Register(fmt.Sprintf("-x-%d-%d", i, j), backgroundColor, PropertyMetadata{})
+				if _, err := r.CSSStyle("background-color", styles); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, r.Names(), len(DefaultRegistry.Names())+800)
+}
+
+func TestCSSStyleDefaultRegistry(t *testing.T) {
+	old, _ := DefaultRegistry.Lookup("color")
+	metadata, _ := DefaultRegistry.Metadata("color")
+	defer DefaultRegistry.Register("color", old, metadata)
+
+	DefaultRegistry.Register("color", func(value string) (Style, error) {
+		return Style{Value: "overridden"}, nil
+	}, metadata)
+	style, err := CSSStyle("color", map[string]string{"color": "red"})
+	assert.NoError(t, err)
+	assert.Equal(t, "overridden", style.Value)
+}
+
+func TestStylesTableCompatibility(t *testing.T) {
+	old := StylesTable["background-color"]
+	defer func() {
+		StylesTable["background-color"] = old
+		delete(StylesTable, "-x-legacy")
+	}()
+
+	StylesTable["background-color"] = func(value string) (Style, error) {
+		return Style{Value: "from table"}, nil
+	}
+	style, err := CSSStyle("background-color", map[string]string{"background-color": "bla"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from table", style.Value)
+
+	StylesTable["-x-legacy"] = StylesTable["background-color"]
+	style, err = CSSStyle("-x-legacy", map[string]string{"-x-legacy": "bla"})
+	assert.NoError(t, err)
+	assert.Equal(t, "from table", style.Value)
+}
@@ -0,0 +1,88 @@
+package css
+
+import "fmt"
+
+// Shadow is one shadow of the box-shadow and text-shadow properties. Text
+// shadows have no spread and are never inset.
+type Shadow struct {
+	Color            Color
+	OffsetX, OffsetY Length
+	Blur, Spread     Length
+	Inset            bool
+}
+
+func (s Shadow) String() string {
+	str := fmt.Sprintf("%s %s %s %s %s", s.Color, s.OffsetX, s.OffsetY, s.Blur, s.Spread)
+	if s.Inset {
+		str += " inset"
+	}
+	return str
+}
+
+// shadows converts box-shadow and text-shadow. none is an empty list.
+func shadows(values []ComponentValue) interface{} {
+	if values[0].Is("none") {
+		return []Shadow(nil)
+	}
+	var list []Shadow
+	for _, layer := range splitCommas(values) {
+		s := Shadow{Color: Color{CurrentColor: true}}
+		var lengths []Length
+		for _, v := range layer {
+			switch {
+			case v.Is("inset"):
+				s.Inset = true
+			case v.Type == DimensionValue || v.Type == NumberValue || mathType(v) != "":
+				lengths = append(lengths, lengthOf(v))
+			default:
+				s.Color = colorOf(v)
+			}
+		}
+		s.OffsetX, s.OffsetY = lengths[0], lengths[1]
+		if len(lengths) > 2 {
+			s.Blur = lengths[2]
+		}
+		if len(lengths) > 3 {
+			s.Spread = lengths[3]
+		}
+		list = append(list, s)
+	}
+	return list
+}
+
+// interpolateShadows interpolates shadow lists shadow by shadow. The
+// shorter list is padded with transparent shadows of zero length, and
+// inset shadows only interpolate with inset shadows.
+func interpolateShadows(a, b []Shadow, p float64) (interface{}, bool) {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	list := make([]Shadow, n)
+	for i := range list {
+		var sa, sb Shadow
+		switch {
+		case i >= len(a):
+			sb = b[i]
+			sa = Shadow{Inset: sb.Inset}
+		case i >= len(b):
+			sa = a[i]
+			sb = Shadow{Inset: sa.Inset}
+		default:
+			sa, sb = a[i], b[i]
+		}
+		if sa.Inset != sb.Inset {
+			return nil, false
+		}
+		l, ok := interpolateLengths([]Length{sa.OffsetX, sa.OffsetY, sa.Blur, sa.Spread}, []Length{sb.OffsetX, sb.OffsetY, sb.Blur, sb.Spread}, p)
+		if !ok {
+			return nil, false
+		}
+		c, ok := interpolateColor(sa.Color, sb.Color, p)
+		if !ok {
+			return nil, false
+		}
+		list[i] = Shadow{Color: c.(Color), OffsetX: l[0], OffsetY: l[1], Blur: l[2], Spread: l[3], Inset: sa.Inset}
+	}
+	return list, true
+}
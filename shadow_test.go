@@ -0,0 +1,76 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadows(t *testing.T) {
+	px := func(n float64) Length { return Length{Value: n, Unit: "px"} }
+	cases := []struct {
+		name     string
+		value    string
+		expected []Shadow
+	}{
+		{"box-shadow", "none", nil},
+		{"box-shadow", "1px 2px", []Shadow{{Color: Color{CurrentColor: true}, OffsetX: px(1), OffsetY: px(2)}}},
+		{"box-shadow", "inset red 1px 2px 3px -4px, 0 0 2px rgb(0 0 255)", []Shadow{
+			{Color: Color{R: 1, A: 1}, OffsetX: px(1), OffsetY: px(2), Blur: px(3), Spread: px(-4), Inset: true},
+			{Color: Color{B: 1, A: 1}, OffsetX: Length{}, OffsetY: Length{}, Blur: px(2)},
+		}},
+		{"text-shadow", "1px 1px 2px black, 0 0 1em blue", []Shadow{
+			{Color: Color{A: 1}, OffsetX: px(1), OffsetY: px(1), Blur: px(2)},
+			{Color: Color{B: 1, A: 1}, Blur: Length{Value: 1, Unit: "em"}},
+		}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"box-shadow":  "1px",
+		"text-shadow": "1px 2px 3px 4px",
+		"-x-shadow":   "1px 2px",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+	_, err := CSSStyle("text-shadow", map[string]string{"text-shadow": "1px 2px inset"})
+	assert.Error(t, err)
+	_, err = CSSStyle("box-shadow", map[string]string{"box-shadow": "1px 2px -3px"})
+	assert.Error(t, err)
+
+	assert.Equal(t, "#ff0000 1px 2px 3px 0 inset", Shadow{Color: Color{R: 1, A: 1}, OffsetX: px(1), OffsetY: px(2), Blur: px(3), Inset: true}.String())
+}
+
+func TestInterpolateShadows(t *testing.T) {
+	from, err := CSSStyle("box-shadow", map[string]string{"box-shadow": "0 0 red, inset 2px 2px blue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := CSSStyle("box-shadow", map[string]string{"box-shadow": "10px 20px 4px red"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	style, err := Interpolate("box-shadow", from, to, 0.5)
+	assert.NoError(t, err)
+	shadows := style.Value.([]Shadow)
+	if assert.Len(t, shadows, 2) {
+		assert.Equal(t, "#ff0000 5px 10px 2px 0", shadows[0].String())
+		assert.True(t, shadows[1].Inset)
+		assert.Equal(t, Length{Value: 1, Unit: "px"}, shadows[1].OffsetX)
+		assert.InDelta(t, 0.5, shadows[1].Color.A, 1e-9)
+	}
+
+	inset, _ := CSSStyle("box-shadow", map[string]string{"box-shadow": "inset 10px 20px red"})
+	style, err = Interpolate("box-shadow", to, inset, 0.25)
+	assert.NoError(t, err)
+	assert.Equal(t, to, style, "inset and outer shadows animate discretely")
+}
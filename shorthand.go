@@ -0,0 +1,83 @@
+package css
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shorthandExpanders expand the values matched by the syntax of a shorthand
+// property into the values of its longhands.
+var shorthandExpanders = withBoxExpanders(map[string]func(values []ComponentValue) map[string]string{
+	"animation":           expandAnimation,
+	"background":          expandBackground,
+	"border":              expandBorder,
+	"border-block":        expandBorderSides("border-block-start", "border-block-end"),
+	"border-block-end":    expandBorderSides("border-block-end"),
+	"border-block-start":  expandBorderSides("border-block-start"),
+	"border-bottom":       expandBorderSides("border-bottom"),
+	"border-image":        expandBorderImage,
+	"border-inline":       expandBorderSides("border-inline-start", "border-inline-end"),
+	"border-inline-end":   expandBorderSides("border-inline-end"),
+	"border-inline-start": expandBorderSides("border-inline-start"),
+	"border-left":         expandBorderSides("border-left"),
+	"border-radius":       expandBorderRadius,
+	"border-right":        expandBorderSides("border-right"),
+	"border-top":          expandBorderSides("border-top"),
+	"flex":                expandFlex,
+	"flex-flow":           expandFlexFlow,
+	"font":                expandFont,
+	"gap":                 expandGap,
+	"grid":                expandGrid,
+	"grid-area":           expandGridArea,
+	"grid-column":         expandGridPlacement("grid-column"),
+	"grid-row":            expandGridPlacement("grid-row"),
+	"grid-template":       expandGridTemplate,
+	"list-style":          expandListStyle,
+	"outline":             expandOutline,
+	"overflow":            expandOverflow,
+	"page-break-after":    expandPageBreak("break-after"),
+	"page-break-before":   expandPageBreak("break-before"),
+	"page-break-inside":   expandPageBreak("break-inside"),
+	"text-decoration":     expandTextDecoration,
+	"transition":          expandTransition,
+})
+
+// withBoxExpanders adds the expanders of the boxShorthands to expanders.
+func withBoxExpanders(expanders map[string]func([]ComponentValue) map[string]string) map[string]func([]ComponentValue) map[string]string {
+	for name, b := range boxShorthands {
+		expanders[name] = b.expand
+	}
+	return expanders
+}
+
+// ExpandShorthand returns the longhand properties that the shorthand
+// property name sets to value. Longhands omitted from value are set to
+// their initial value, and CSS-wide keywords apply to every longhand.
+func ExpandShorthand(name, value string) (map[string]string, error) {
+	name = strings.ToLower(name)
+	expand, ok := shorthandExpanders[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a shorthand property", name)
+	}
+	values, err := ParseValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if isCSSWideKeyword(values) {
+		m, _ := DefaultRegistry.Metadata(name)
+		longhands := make(map[string]string, len(m.Longhands))
+		for _, longhand := range m.Longhands {
+			longhands[longhand] = strings.ToLower(values[0].Text)
+		}
+		return longhands, nil
+	}
+	if g := propertyGrammars[name]; !g.MatchValues(values) {
+		return nil, fmt.Errorf("invalid value %q, expected %s", value, g.def)
+	}
+	if validate, ok := propertyValidators[name]; ok {
+		if err := validate(values); err != nil {
+			return nil, err
+		}
+	}
+	return expand(values), nil
+}
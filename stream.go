@@ -0,0 +1,739 @@
+package css
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Handler receives events from Parse as a stylesheet is read. Returning a
+// non-nil error from any callback stops parsing and Parse returns that error.
+type Handler interface {
+	// StartRule is called when a style rule block opens. Selectors are the
+	// comma separated parts of the rule prelude with whitespace collapsed.
+	StartRule(selectors []string) error
+	// Declaration is called for every "name: value" pair inside a block.
+	// A trailing "!important" is removed from value and reported in important.
+	Declaration(name, value string, important bool) error
+	// EndRule is called when a style rule block closes.
+	EndRule() error
+	// StartAtRule is called for at-rules such as @media or @import. The name
+	// is lower case and without the "@", prelude is the text before the block.
+	StartAtRule(name, prelude string) error
+	// EndAtRule is called when the at-rule block closes, or immediately
+	// after StartAtRule for statement at-rules ending with a semicolon.
+	EndAtRule(name string) error
+	// Comment is called with the text between "/*" and "*/".
+	Comment(text string) error
+}
+
+// NopHandler implements Handler by ignoring every event. Embed it to
+// implement only the callbacks you need.
+type NopHandler struct{}
+
+func (NopHandler) StartRule(selectors []string) error                   { return nil }
+func (NopHandler) Declaration(name, value string, important bool) error { return nil }
+func (NopHandler) EndRule() error                                       { return nil }
+func (NopHandler) StartAtRule(name, prelude string) error               { return nil }
+func (NopHandler) EndAtRule(name string) error                          { return nil }
+func (NopHandler) Comment(text string) error                            { return nil }
+
+// conditionalHandler reports the events of a stylesheet to h, replacing
+// the conditional at-rules named rule, like @media, by their contents if
+// their prelude matches and skipping them otherwise.
+type conditionalHandler struct {
+	h       Handler
+	rule    string
+	matches func(prelude string) bool
+	// skip counts the open blocks of a skipped rule, if any
+	skip int
+	// unwrapped holds for each open at-rule whether it is a matching
+	// conditional rule that isn't reported
+	unwrapped []bool
+}
+
+func (c *conditionalHandler) StartRule(selectors []string) error {
+	if c.skip > 0 {
+		c.skip++
+		return nil
+	}
+	return c.h.StartRule(selectors)
+}
+
+func (c *conditionalHandler) Declaration(name, value string, important bool) error {
+	if c.skip > 0 {
+		return nil
+	}
+	return c.h.Declaration(name, value, important)
+}
+
+func (c *conditionalHandler) EndRule() error {
+	if c.skip > 0 {
+		c.skip--
+		return nil
+	}
+	return c.h.EndRule()
+}
+
+func (c *conditionalHandler) StartAtRule(name, prelude string) error {
+	switch {
+	case c.skip > 0:
+		c.skip++
+		return nil
+	case name != c.rule:
+		c.unwrapped = append(c.unwrapped, false)
+		return c.h.StartAtRule(name, prelude)
+	case !c.matches(prelude):
+		c.skip = 1
+		return nil
+	}
+	c.unwrapped = append(c.unwrapped, true)
+	return nil
+}
+
+func (c *conditionalHandler) EndAtRule(name string) error {
+	if c.skip > 0 {
+		c.skip--
+		return nil
+	}
+	unwrapped := c.unwrapped[len(c.unwrapped)-1]
+	c.unwrapped = c.unwrapped[:len(c.unwrapped)-1]
+	if unwrapped {
+		return nil
+	}
+	return c.h.EndAtRule(name)
+}
+
+func (c *conditionalHandler) Comment(text string) error {
+	if c.skip > 0 {
+		return nil
+	}
+	return c.h.Comment(text)
+}
+
+const (
+	readBufferSize = 4096
+	// maxInternedNames bounds the memory used for property name interning.
+	maxInternedNames = 1024
+	// cancelCheckInterval is the number of bytes read between checks of
+	// the context passed to ParseContext.
+	cancelCheckInterval = 1024
+)
+
+// Parse reads a stylesheet from r and reports its structure to h. The input
+// is consumed incrementally, so memory use depends on the block nesting depth
+// and the size of a single rule prelude or declaration, not on the input size.
+func Parse(r io.Reader, h Handler) error {
+	return ParseContext(context.Background(), r, h)
+}
+
+// ParseContext is like Parse, but stops when ctx is done. The returned error
+// wraps ctx.Err() and reports the line that was reached.
+func ParseContext(ctx context.Context, r io.Reader, h Handler) error {
+	p := &streamParser{
+		r:   r,
+		buf: make([]byte, 0, readBufferSize),
+	}
+	return p.run(ctx, h)
+}
+
+// parseBytes is like ParseContext, but the reported strings are views into a
+// single copy of b instead of separate allocations.
+func parseBytes(ctx context.Context, b []byte, h Handler) error {
+	p := &streamParser{
+		src:   string(b),
+		buf:   b,
+		whole: true,
+	}
+	return p.run(ctx, h)
+}
+
+// streamParser groups the input into rule preludes and declarations, and
+// dispatches each item once a '{', ';' or '}' outside of parentheses ends it.
+//
+// Items are tracked as byte offsets into buf. When the input is read from an
+// io.Reader, buf is a window that is refilled as needed, keeping only the
+// unfinished item. When the whole input is available, buf covers all of it
+// and item text is taken as substrings of src without copying.
+type streamParser struct {
+	r     io.Reader
+	whole bool
+	src   string
+	buf   []byte
+	pos   int
+	eof   bool
+	rerr  error
+
+	line     int
+	itemLine int
+
+	ctx    context.Context
+	done   <-chan struct{}
+	unread int // bytes left until the next check of ctx
+
+	// The current item is buf[start:end] with surrounding whitespace
+	// excluded, start is -1 between items. The item is dirty if it contains
+	// comments or whitespace other than single spaces and must be collapsed
+	// into scratch before use.
+	start   int
+	end     int
+	dirty   bool
+	space   byte
+	depth   int
+	scratch []byte
+
+	// Text of the finished item, and its offset in src if it is a view of
+	// the input or -1 if its parts must be copied.
+	text    []byte
+	textOff int
+
+	// Start of the comment being read, -1 outside of comments.
+	comment int
+
+	names map[string]string
+
+	// Open blocks, "" for style rules and the at-rule name otherwise.
+	stack []string
+}
+
+func (p *streamParser) run(ctx context.Context, h Handler) error {
+	p.line = 1
+	p.start = -1
+	p.comment = -1
+	p.ctx = ctx
+	p.done = ctx.Done()
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("line %d: %w", p.line, err)
+	}
+
+	for {
+		c, ok := p.next()
+		if !ok {
+			if p.rerr != nil {
+				return p.rerr
+			}
+			return p.finish(h)
+		}
+
+		var err error
+		switch c {
+		case '\n':
+			p.line++
+			p.markSpace(c)
+		case ' ', '\t', '\r', '\f':
+			p.markSpace(c)
+		case '"', '\'':
+			p.include()
+			err = p.readString(c)
+		case '\\':
+			p.include()
+			if _, ok := p.next(); ok {
+				p.end = p.pos
+			}
+		case '/':
+			if p.peek() == '*' {
+				p.pos++
+				err = p.readComment(h)
+				break
+			}
+			p.include()
+		case '*':
+			if p.peek() == '/' {
+				return fmt.Errorf("line %d: unexpected end of comment: %w", p.line, InvalidCSSError)
+			}
+			p.include()
+		case '(', '[':
+			p.depth++
+			p.include()
+		case ')', ']':
+			if p.depth > 0 {
+				p.depth--
+			}
+			p.include()
+		case '{':
+			if p.depth > 0 {
+				p.include()
+				break
+			}
+			err = p.openBlock(h)
+		case ';':
+			if p.depth > 0 {
+				p.include()
+				break
+			}
+			err = p.endStatement(h)
+		case '}':
+			if p.depth > 0 {
+				p.include()
+				break
+			}
+			err = p.closeBlock(h)
+		default:
+			p.include()
+		}
+
+		if err != nil {
+			if p.rerr != nil { // the input was cut short
+				return p.rerr
+			}
+			return err
+		}
+	}
+}
+
+// next returns the next input byte, refilling the buffer if needed. It
+// returns false at the end of the input, on read errors and when the
+// context is done.
+func (p *streamParser) next() (byte, bool) {
+	if p.pos == len(p.buf) && !p.fill() {
+		return 0, false
+	}
+	if p.done != nil {
+		p.unread--
+		if p.unread <= 0 {
+			p.unread = cancelCheckInterval
+			select {
+			case <-p.done:
+				p.rerr = fmt.Errorf("line %d: %w", p.line, p.ctx.Err())
+				p.pos = len(p.buf)
+				p.eof = true
+				return 0, false
+			default:
+			}
+		}
+	}
+	c := p.buf[p.pos]
+	p.pos++
+	return c, true
+}
+
+func (p *streamParser) peek() byte {
+	if p.pos == len(p.buf) && !p.fill() {
+		return 0
+	}
+	return p.buf[p.pos]
+}
+
+// fill reads more input into the buffer, discarding everything before the
+// current item or comment. It returns false at the end of the input.
+func (p *streamParser) fill() bool {
+	if p.whole || p.eof {
+		return false
+	}
+
+	keep := p.pos
+	if p.start >= 0 && p.start < keep {
+		keep = p.start
+	}
+	if p.comment >= 0 && p.comment < keep {
+		keep = p.comment
+	}
+	if keep > 0 {
+		p.buf = p.buf[:copy(p.buf, p.buf[keep:])]
+		p.pos -= keep
+		if p.start >= 0 {
+			p.start -= keep
+			p.end -= keep
+		}
+		if p.comment >= 0 {
+			p.comment -= keep
+		}
+	}
+	if len(p.buf) == cap(p.buf) {
+		buf := make([]byte, len(p.buf), 2*cap(p.buf))
+		copy(buf, p.buf)
+		p.buf = buf
+	}
+
+	for {
+		n, err := p.r.Read(p.buf[len(p.buf):cap(p.buf)])
+		p.buf = p.buf[:len(p.buf)+n]
+		if err != nil {
+			if err != io.EOF {
+				p.rerr = err
+			}
+			p.eof = true
+		}
+		if n > 0 || p.eof {
+			return n > 0
+		}
+	}
+}
+
+// include adds the byte just read to the current item.
+func (p *streamParser) include() {
+	if p.start < 0 {
+		p.start = p.pos - 1
+		p.itemLine = p.line
+	} else if p.space != 0 && p.space != ' ' {
+		p.dirty = true
+	}
+	p.space = 0
+	p.end = p.pos
+}
+
+// markSpace records whitespace; anything but a single space inside an item
+// has to be collapsed later.
+func (p *streamParser) markSpace(c byte) {
+	if p.space != 0 {
+		c = '\n'
+	}
+	p.space = c
+}
+
+// item finishes the current item and returns its text, which is only valid
+// until the next read. It returns false if there is no item.
+func (p *streamParser) item() ([]byte, bool) {
+	if p.start < 0 {
+		p.space = 0
+		return nil, false
+	}
+	p.text = p.buf[p.start:p.end]
+	p.textOff = -1
+	if p.dirty {
+		p.scratch = collapseSpace(p.scratch[:0], p.text)
+		p.text = p.scratch
+	} else if p.whole {
+		p.textOff = p.start
+	}
+	p.start = -1
+	p.dirty = false
+	p.space = 0
+	p.depth = 0
+	return p.text, true
+}
+
+// str returns b, which must be a part of the last item, as a string. Parts of
+// the input are returned as substrings of src without copying.
+func (p *streamParser) str(b []byte) string {
+	if p.textOff < 0 || len(b) == 0 {
+		return string(b)
+	}
+	off := p.textOff + cap(p.text) - cap(b)
+	return p.src[off : off+len(b)]
+}
+
+// intern is like str, but reuses the string of earlier occurrences of name
+// when it has to be copied.
+func (p *streamParser) intern(name []byte) string {
+	if p.textOff >= 0 {
+		return p.str(name)
+	}
+	if s, ok := p.names[string(name)]; ok {
+		return s
+	}
+	s := string(name)
+	if p.names == nil {
+		p.names = make(map[string]string)
+	}
+	if len(p.names) < maxInternedNames {
+		p.names[s] = s
+	}
+	return s
+}
+
+func (p *streamParser) readString(quote byte) error {
+	for {
+		c, ok := p.next()
+		if !ok || c == '\n' {
+			return fmt.Errorf("line %d: unterminated string: %w", p.line, InvalidCSSError)
+		}
+		switch c {
+		case quote:
+			p.end = p.pos
+			return nil
+		case '\\':
+			c, ok = p.next()
+			if !ok {
+				return fmt.Errorf("line %d: unterminated string: %w", p.line, InvalidCSSError)
+			}
+			if c == '\n' {
+				p.line++
+			}
+		}
+	}
+}
+
+func (p *streamParser) readComment(h Handler) error {
+	p.comment = p.pos
+	for {
+		c, ok := p.next()
+		if !ok {
+			return fmt.Errorf("line %d: unterminated comment: %w", p.line, InvalidCSSError)
+		}
+		if c == '\n' {
+			p.line++
+		}
+		if c == '*' && p.peek() == '/' {
+			break
+		}
+	}
+	var text string
+	if p.whole {
+		text = p.src[p.comment : p.pos-1]
+	} else {
+		text = string(p.buf[p.comment : p.pos-1])
+	}
+	p.pos++
+	p.comment = -1
+	if p.start >= 0 {
+		p.dirty = true
+	}
+	p.markSpace('\n')
+	return h.Comment(text)
+}
+
+func (p *streamParser) openBlock(h Handler) error {
+	text, ok := p.item()
+	if ok && text[0] == '@' {
+		name, prelude, err := p.splitAtRule(text)
+		if err != nil {
+			return err
+		}
+		p.stack = append(p.stack, name)
+		return h.StartAtRule(name, prelude)
+	}
+
+	if !ok {
+		return fmt.Errorf("line %d: block is missing rule identifier: %w", p.line, InvalidCSSError)
+	}
+	var selectors []string
+	for len(text) > 0 {
+		i := indexTopLevel(text, ',')
+		if i < 0 {
+			i = len(text)
+		}
+		selector := bytes.TrimSpace(text[:i])
+		if len(selector) == 0 {
+			return fmt.Errorf("line %d: empty selector: %w", p.itemLine, InvalidCSSError)
+		}
+		selectors = append(selectors, p.str(selector))
+		if i == len(text) {
+			break
+		}
+		text = text[i+1:]
+		if len(text) == 0 {
+			return fmt.Errorf("line %d: empty selector: %w", p.itemLine, InvalidCSSError)
+		}
+	}
+	p.stack = append(p.stack, "")
+	return h.StartRule(selectors)
+}
+
+func (p *streamParser) endStatement(h Handler) error {
+	text, ok := p.item()
+	if !ok {
+		return nil
+	}
+	return p.statement(h, text)
+}
+
+// statement handles an item that is not followed by a block: a statement
+// at-rule or a declaration.
+func (p *streamParser) statement(h Handler, text []byte) error {
+	if text[0] == '@' {
+		name, prelude, err := p.splitAtRule(text)
+		if err != nil {
+			return err
+		}
+		if err := h.StartAtRule(name, prelude); err != nil {
+			return err
+		}
+		return h.EndAtRule(name)
+	}
+	if len(p.stack) == 0 {
+		return fmt.Errorf("line %d: declaration outside of a block: %w", p.itemLine, InvalidCSSError)
+	}
+
+	colon := indexTopLevel(text, ':')
+	if colon < 0 {
+		return fmt.Errorf("line %d: expected style before semicolon: %w", p.itemLine, InvalidCSSError)
+	}
+	name := bytes.TrimSpace(text[:colon])
+	if len(name) == 0 {
+		return fmt.Errorf("line %d: missing style name: %w", p.itemLine, InvalidCSSError)
+	}
+	if bytes.IndexByte(name, ' ') >= 0 {
+		return fmt.Errorf("line %d: expected only one name before value: %w", p.itemLine, InvalidCSSError)
+	}
+
+	value := bytes.TrimSpace(text[colon+1:])
+	important := false
+	if bang := bytes.LastIndexByte(value, '!'); bang >= 0 && bytes.EqualFold(bytes.TrimSpace(value[bang+1:]), []byte("important")) {
+		value = bytes.TrimSpace(value[:bang])
+		important = true
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("line %d: missing value for style %q: %w", p.itemLine, name, InvalidCSSError)
+	}
+	if startsDeclaration(value) { // most likely a missing semicolon
+		return fmt.Errorf("line %d: multiple style names before value: %w", p.itemLine, InvalidCSSError)
+	}
+	return h.Declaration(p.intern(name), p.str(value), important)
+}
+
+func (p *streamParser) closeBlock(h Handler) error {
+	if len(p.stack) == 0 {
+		return fmt.Errorf("line %d: rule block ends without a beginning: %w", p.line, InvalidCSSError)
+	}
+	// the last declaration in a block doesn't need a semicolon
+	if text, ok := p.item(); ok {
+		if err := p.statement(h, text); err != nil {
+			return err
+		}
+	}
+
+	name := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	if name == "" {
+		return h.EndRule()
+	}
+	return h.EndAtRule(name)
+}
+
+func (p *streamParser) finish(h Handler) error {
+	if text, ok := p.item(); ok {
+		return fmt.Errorf("line %d: unexpected end of input after %q: %w", p.itemLine, text, InvalidCSSError)
+	}
+	if len(p.stack) > 0 {
+		return fmt.Errorf("line %d: unclosed block: %w", p.line, InvalidCSSError)
+	}
+	return nil
+}
+
+func (p *streamParser) splitAtRule(text []byte) (string, string, error) {
+	i := 1
+	lower := true
+	for i < len(text) && isNameByte(text[i]) {
+		if text[i] >= 'A' && text[i] <= 'Z' {
+			lower = false
+		}
+		i++
+	}
+	if i == 1 {
+		return "", "", fmt.Errorf("line %d: at-rule is missing a name: %w", p.itemLine, InvalidCSSError)
+	}
+
+	name := p.intern(text[1:i])
+	if !lower {
+		name = strings.ToLower(name)
+	}
+	return name, p.str(bytes.TrimSpace(text[i:])), nil
+}
+
+// startsDeclaration reports whether a value contains another declaration
+// after whitespace, like "a b: c". Values may contain colons otherwise, like
+// legacy filters such as "progid:DXImageTransform.Microsoft.Alpha(Opacity=80)".
+func startsDeclaration(value []byte) bool {
+	for {
+		colon := indexTopLevel(value, ':')
+		if colon < 0 {
+			return false
+		}
+		name := colon
+		for name > 0 && isNameByte(value[name-1]) {
+			name--
+		}
+		if name > 0 && name < colon && isSpace(value[name-1]) && (colon+1 == len(value) || isSpace(value[colon+1])) {
+			return true
+		}
+		value = value[colon+1:]
+	}
+}
+
+func isNameByte(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f':
+		return true
+	}
+	return false
+}
+
+// indexTopLevel returns the index of the first c in s that is not inside a
+// string, parentheses or brackets, or -1.
+func indexTopLevel(s []byte, c byte) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\\':
+			i++
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '(' || ch == '[':
+			depth++
+		case ch == ')' || ch == ']':
+			if depth > 0 {
+				depth--
+			}
+		case ch == c && depth == 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// collapseSpace appends src to dst with comments removed and whitespace
+// outside of strings collapsed into single spaces.
+func collapseSpace(dst, src []byte) []byte {
+	space := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case isSpace(c):
+			space = true
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+			space = true
+			continue
+		}
+
+		if space && len(dst) > 0 {
+			dst = append(dst, ' ')
+		}
+		space = false
+
+		switch c {
+		case '\\':
+			dst = append(dst, c)
+			if i+1 < len(src) {
+				i++
+				dst = append(dst, src[i])
+			}
+		case '"', '\'':
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			dst = append(dst, src[i:j+1]...)
+			i = j
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,264 @@
+package css
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) StartRule(selectors []string) error {
+	h.events = append(h.events, fmt.Sprintf("rule %q", selectors))
+	return nil
+}
+
+func (h *recordingHandler) Declaration(name, value string, important bool) error {
+	h.events = append(h.events, fmt.Sprintf("decl %s=%s important=%v", name, value, important))
+	return nil
+}
+
+func (h *recordingHandler) EndRule() error {
+	h.events = append(h.events, "end rule")
+	return nil
+}
+
+func (h *recordingHandler) StartAtRule(name, prelude string) error {
+	h.events = append(h.events, fmt.Sprintf("at %s %q", name, prelude))
+	return nil
+}
+
+func (h *recordingHandler) EndAtRule(name string) error {
+	h.events = append(h.events, "end at "+name)
+	return nil
+}
+
+func (h *recordingHandler) Comment(text string) error {
+	h.events = append(h.events, fmt.Sprintf("comment %q", text))
+	return nil
+}
+
+func TestParseEvents(t *testing.T) {
+	cases := []struct {
+		name     string
+		CSS      string
+		expected []string
+	}{
+		{"Single rule", `rule {
+	style1: value1;
+	style2: value2
+}`, []string{
+			`rule ["rule"]`,
+			"decl style1=value1 important=false",
+			"decl style2=value2 important=false",
+			"end rule",
+		}},
+		{"Selector list", `h1,  .a  #b,
+p > a { color: red !important; }`, []string{
+			`rule ["h1" ".a #b" "p > a"]`,
+			"decl color=red important=true",
+			"end rule",
+		}},
+		{"Comment", `/* header */ a { /* inner */ b: c; }`, []string{
+			`comment " header "`,
+			`rule ["a"]`,
+			`comment " inner "`,
+			"decl b=c important=false",
+			"end rule",
+		}},
+		{"Nested at-rules", `@import url("a;b.css");
+@media screen and (min-width: 100px) {
+	a { b: c; }
+}`, []string{
+			`at import "url(\"a;b.css\")"`,
+			"end at import",
+			`at media "screen and (min-width: 100px)"`,
+			`rule ["a"]`,
+			"decl b=c important=false",
+			"end rule",
+			"end at media",
+		}},
+		{"At-rule with declarations", `@font-face { font-family: "A {}"; src: url(a.woff) }`, []string{
+			`at font-face ""`,
+			`decl font-family="A {}" important=false`,
+			"decl src=url(a.woff) important=false",
+			"end at font-face",
+		}},
+		{"Multiline value", "a {\n\tfont-family: 'Zil',\n\t\tserif;\n}", []string{
+			`rule ["a"]`,
+			"decl font-family='Zil', serif important=false",
+			"end rule",
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recordingHandler{}
+			if err := Parse(strings.NewReader(tt.CSS), h); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, h.events)
+		})
+	}
+}
+
+func TestParseEventsError(t *testing.T) {
+	cases := []struct {
+		name string
+		CSS  string
+	}{
+		{"Missing rule", "{ a: b; }"},
+		{"Missing value", "a { b:; }"},
+		{"Missing semicolon", "a {\n b: c\n d: e;\n}"},
+		{"Block ends without beginning", "} a { b: c; }"},
+		{"Unexpected end of comment", "a { b: c; */ }"},
+		{"Unclosed block", "a { b: c;"},
+		{"Unterminated comment", "a { b: c; } /*"},
+		{"Unterminated string", "a { b: \"c; }\n"},
+		{"Declaration outside block", "a: b;"},
+		{"Empty selector", "a, { b: c; }"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Parse(strings.NewReader(tt.CSS), NopHandler{})
+			assert.ErrorIs(t, err, InvalidCSSError)
+		})
+	}
+}
+
+func TestParseSmallReads(t *testing.T) {
+	styleSheet := `/* long comment spanning reads */
+.a  .b,
+.c:hover > d {
+	font-family: "Some Font",   serif;
+	margin: 0 /* top */ auto !important
+}
+@media (min-width: 100px) { e { f: g } }`
+
+	expected := &recordingHandler{}
+	if err := Parse(strings.NewReader(styleSheet), expected); err != nil {
+		t.Fatal(err)
+	}
+	h := &recordingHandler{}
+	if err := Parse(iotest.OneByteReader(strings.NewReader(styleSheet)), h); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected.events, h.events)
+	assert.Contains(t, h.events, "decl margin=0 auto important=true")
+}
+
+func TestParseReadError(t *testing.T) {
+	err := Parse(iotest.TimeoutReader(strings.NewReader("a { b: c; }")), NopHandler{})
+	assert.ErrorIs(t, err, iotest.ErrTimeout)
+}
+
+type stopHandler struct {
+	NopHandler
+	rules int
+}
+
+var errStop = errors.New("stop")
+
+func (h *stopHandler) StartRule(selectors []string) error {
+	h.rules++
+	if h.rules == 3 {
+		return errStop
+	}
+	return nil
+}
+
+func TestParseHandlerError(t *testing.T) {
+	h := &stopHandler{}
+	err := Parse(strings.NewReader("a{} b{} c{} d{}"), h)
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 3, h.rules)
+}
+
+// ruleReader generates n rules without holding the stylesheet in memory.
+type ruleReader struct {
+	n, i int
+	buf  []byte
+}
+
+func (r *ruleReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.i == r.n {
+			return 0, io.EOF
+		}
+		r.buf = []byte(fmt.Sprintf(".block%d > p {\n\tstyle%d: value%d;\n}\n", r.i, r.i, r.i))
+		r.i++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type countingHandler struct {
+	NopHandler
+	declarations int
+}
+
+func (h *countingHandler) Declaration(name, value string, important bool) error {
+	h.declarations++
+	return nil
+}
+
+func TestParseLargeInput(t *testing.T) {
+	h := &countingHandler{}
+	if err := Parse(&ruleReader{n: 100000}, h); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 100000, h.declarations)
+}
+
+type cancelHandler struct {
+	NopHandler
+	cancel context.CancelFunc
+	rules  int
+}
+
+func (h *cancelHandler) StartRule(selectors []string) error {
+	h.rules++
+	if h.rules == 10 {
+		h.cancel()
+	}
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := &cancelHandler{cancel: cancel}
+	err := ParseContext(ctx, &ruleReader{n: 100000}, h)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, h.rules, 100)
+	assert.Contains(t, err.Error(), "line ")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	err = ParseContext(ctx, &ruleReader{n: 10}, NopHandler{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func BenchmarkParseLarge(b *testing.B) {
+	styleSheet := largeStylesheet(2000)
+	b.SetBytes(int64(len(styleSheet)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Parse(bytes.NewReader(styleSheet), NopHandler{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -33,80 +33,13 @@ func (style Style) String() string {
 // and returns a Style
 type StyleHandler func(value string) (Style, error)
 
-// Common CSS styles. You can overwrite the handlers with your own.
-var StylesTable = map[string]StyleHandler{
-	"background":                    background,
-	"background-attachment":         backgroundAttachment,
-	"background-color":              backgroundColor,
-	"background-image":              backgroundImage,
-	"background-position":           backgroundPosition,
-	"background-repeat":             backgroundRepeat,
-	"border":                        border,
-	"border-bottom":                 borderBottom,
-	"border-bottom-color":           borderBottomColor,
-	"border-bottom-style":           borderBottomStyle,
-	"border-bottom-width":           borderBottomWidth,
-	"border-color":                  borderColor,
-	"border-left":                   borderLeft,
-	"border-left-color":             borderLeftColor,
-	"border-left-style":             borderLeftStyle,
-	"border-left-width":             borderLeftWidth,
-	"border-right":                  borderRight,
-	"border-right-color":            borderRightColor,
-	"border-right-style":            borderRightStyle,
-	"border-right-width":            borderRightWidth,
-	"border-style":                  borderStyle,
-	"border-top":                    borderTop,
-	"border-top-color":              borderTopColor,
-	"border-top-style":              borderTopStyle,
-	"border-top-width":              borderTopWidth,
-	"border-width":                  borderWidth,
-	"clear":                         clear,
-	"clip":                          clip,
-	"color":                         color,
-	"cursor":                        cursor,
-	"display":                       display,
-	"filter":                        filter,
-	"font":                          font,
-	"font-family":                   fontFamily,
-	"font-size":                     fontSize,
-	"font-variant":                  fontVariant,
-	"font-weight":                   fontWeight,
-	"height":                        height,
-	"left":                          left,
-	"letter-spacing":                letterSpacing,
-	"line-height":                   lineHeight,
-	"list-style":                    listStyle,
-	"list-style-image":              listStyleImage,
-	"list-style-position":           listStylePosition,
-	"list-style-type":               listStyleType,
-	"margin":                        margin,
-	"margin-bottom":                 marginBottom,
-	"margin-left":                   marginLeft,
-	"margin-right":                  marginRight,
-	"margin-top":                    marginTop,
-	"overflow":                      overflow,
-	"padding":                       padding,
-	"padding-bottom":                paddingBottom,
-	"padding-left":                  paddingLeft,
-	"padding-right":                 paddingRight,
-	"padding-top":                   paddingTop,
-	"page-break-after":              pageBreakAfter,
-	"page-break-before":             pageBreakBefore,
-	"position":                      position,
-	"float":                         float,
-	"text-align":                    textAlign,
-	"text-decoration":               textDecoration,
-	"text-decoration: blink":        textDecorationBlink,
-	"text-decoration: line-through": textDecorationLineThrough,
-	"text-decoration: none":         textDecorationNone,
-	"text-decoration: overline":     textDecorationOverline,
-	"text-decoration: underline":    textDecorationUnderline,
-	"text-indent":                   textIndent,
-	"text-transform":                textTransform,
-	"top":                           top,
-	"vertical-align":                verticalAlign,
-	"visibility":                    visibility,
-	"width":                         width,
-	"z-index":                       zIndex,
-}
+// Common CSS styles. Handlers of the properties in propertySyntax are
+// generated from their grammar. CSSStyle uses the handlers of the table,
+// also overwritten ones, unless other handlers are registered with
+// DefaultRegistry.
+//
+// Deprecated: StylesTable is not safe for concurrent modification. Register
+// handlers with DefaultRegistry or a Registry of your own instead.
+var StylesTable = withSyntaxHandlers(map[string]StyleHandler{
+	"filter": filterHandler,
+})
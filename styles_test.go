@@ -12,3 +12,44 @@ func TestStyles(t *testing.T) {
 		t.Fatalf("should be valid color, but got %v", err)
 	}
 }
+
+func TestGeneratedStyles(t *testing.T) {
+	cases := []struct {
+		name    string
+		valid   []string
+		invalid []string
+	}{
+		{"background", []string{"red", "url(a.png) no-repeat left top / 50% auto, #fff", "none fixed padding-box content-box"}, []string{"red, blue", "solid"}},
+		{"background-position", []string{"left", "10px 20%", "right 10px bottom", "center top, 0 0"}, []string{"left right", "1px 2px 3px 4px 5px"}},
+		{"border", []string{"1px solid red", "solid", "thick dashed #000"}, []string{"1px 2px", "solid dotted", "-1px solid"}},
+		{"border-width", []string{"thin", "1px 2px 3px 4px"}, []string{"1px 2px 3px 4px 5px"}},
+		{"clip", []string{"auto", "rect(1px, 2px, 3px, 4px)", "rect(1px 2px auto 4px)"}, []string{"rect(1px, 2px)"}},
+		{"cursor", []string{"pointer", "url(a.cur) 1 2, url(b.cur), auto"}, []string{"url(a.cur)", "hand"}},
+		{"display", []string{"block", "inline flow-root", "list-item", "inline list-item", "contents", "inline-flex"}, []string{"block inline", "flex grid"}},
+		{"filter", []string{"none", "blur(2px) grayscale(50%)", "drop-shadow(1px 2px red)"}, []string{"blur(red)"}},
+		{"font", []string{"12px serif", "italic bold 12px/1.5 'Helvetica Neue', Arial, sans-serif", "caption"}, []string{"bold serif", "12px"}},
+		{"font-weight", []string{"bold", "100", "1000"}, []string{"1001", "heavy"}},
+		{"line-height", []string{"normal", "1.5", "12px", "120%"}, []string{"-1", "auto"}},
+		{"margin", []string{"0", "1px auto", "1px 2px 3px 4px"}, []string{"1px 2px 3px 4px 5px", "none"}},
+		{"padding", []string{"0", "1px 2%"}, []string{"-1px", "auto"}},
+		{"text-indent", []string{"1em", "1em hanging", "each-line 5% hanging"}, []string{"hanging"}},
+		{"z-index", []string{"auto", "10", "-1"}, []string{"1.5", "none"}},
+		{"width", []string{"auto", "100%", "fit-content(10px)", "inherit"}, []string{"-1px"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range tt.valid {
+				_, err := CSSStyle(tt.name, map[string]string{tt.name: value})
+				if err != nil {
+					t.Errorf("%s: %q should be valid, but got %v", tt.name, value, err)
+				}
+			}
+			for _, value := range tt.invalid {
+				if _, err := CSSStyle(tt.name, map[string]string{tt.name: value}); err == nil {
+					t.Errorf("%s: %q should be invalid", tt.name, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package css
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SupportsEnvironment is the set of features that @supports conditions
+// are evaluated against.
+type SupportsEnvironment struct {
+	// Registry validates the declarations tested by conditions like
+	// "(display: grid)". DefaultRegistry is used if it is nil.
+	Registry *Registry
+	// Selector reports whether the selector tested by selector() is
+	// supported. No selector is if it is nil.
+	Selector func(selector string) bool
+	// FontTechs and FontFormats are the lower case font technologies and
+	// formats tested by font-tech() and font-format(), like "color-colrv1"
+	// and "woff2".
+	FontTechs, FontFormats []string
+}
+
+// SupportsCondition is a condition of an @supports rule: a feature test,
+// or the not, and or or of other conditions.
+type SupportsCondition struct {
+	// Op is "not", "and" or "or" for conditions combining Conditions, and
+	// empty for the other conditions.
+	Op         string
+	Conditions []SupportsCondition
+	// Property and Value are the declaration tested by conditions like
+	// "(display: grid)". Property is lower case, unless it is a custom
+	// property.
+	Property, Value string
+	// Function is "selector", "font-tech" or "font-format" for the
+	// conditions testing the selector, font technology or font format
+	// Argument.
+	Function, Argument string
+	// Enclosed is the text of a general enclosed condition, a function or
+	// parenthesized text that isn't a known condition. It is false.
+	Enclosed string
+}
+
+// maxSupportsDepth bounds the nesting of conditions in parentheses. Each
+// level tokenizes the text inside it again, so deeper conditions are
+// general enclosed.
+const maxSupportsDepth = 32
+
+// ParseSupportsCondition parses the prelude of an @supports rule.
+func ParseSupportsCondition(s string) (SupportsCondition, error) {
+	return parseSupportsText(s, 0)
+}
+
+func parseSupportsText(s string, depth int) (SupportsCondition, error) {
+	values, raws, err := rawValues(s)
+	if err != nil {
+		return SupportsCondition{}, err
+	}
+	c, err := parseSupportsCondition(values, raws, depth)
+	if err != nil {
+		return SupportsCondition{}, fmt.Errorf("invalid supports condition %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// rawValues splits s into component values like ParseValue, and also
+// returns the source text of each value.
+func rawValues(s string) ([]ComponentValue, []string, error) {
+	t := valueTokenizer{s: s}
+	var values []ComponentValue
+	var raws []string
+	for {
+		t.skipSpace()
+		if t.pos == len(s) {
+			return values, raws, nil
+		}
+		start := t.pos
+		if c := s[start]; c == ')' || c == ']' {
+			return nil, nil, fmt.Errorf("unexpected %q in %q", c, s)
+		}
+		v, err := t.value()
+		if err != nil {
+			return nil, nil, err
+		}
+		raw := s[start:t.pos]
+		if (v.Type == FunctionValue || v.Type == BlockValue && v.Text == "(") && !strings.HasSuffix(raw, ")") {
+			return nil, nil, fmt.Errorf("missing ')' in %q", s)
+		}
+		values, raws = append(values, v), append(raws, raw)
+	}
+}
+
+func parseSupportsCondition(values []ComponentValue, raws []string, depth int) (SupportsCondition, error) {
+	if len(values) == 0 {
+		return SupportsCondition{}, fmt.Errorf("expected a condition")
+	}
+	if values[0].Is("not") {
+		if len(values) != 2 {
+			return SupportsCondition{}, fmt.Errorf("expected a single condition after not")
+		}
+		c, err := parseSupportsInParens(values[1], raws[1], depth)
+		if err != nil {
+			return SupportsCondition{}, err
+		}
+		return SupportsCondition{Op: "not", Conditions: []SupportsCondition{c}}, nil
+	}
+	first, err := parseSupportsInParens(values[0], raws[0], depth)
+	if err != nil || len(values) == 1 {
+		return first, err
+	}
+	op := strings.ToLower(values[1].Text)
+	if values[1].Type != IdentValue || op != "and" && op != "or" {
+		return SupportsCondition{}, fmt.Errorf("unexpected %s", values[1])
+	}
+	c := SupportsCondition{Op: op, Conditions: []SupportsCondition{first}}
+	for i := 1; i < len(values); i += 2 {
+		if !values[i].Is(op) || i+1 == len(values) {
+			return SupportsCondition{}, fmt.Errorf("and and or can't be mixed without parentheses")
+		}
+		next, err := parseSupportsInParens(values[i+1], raws[i+1], depth)
+		if err != nil {
+			return SupportsCondition{}, err
+		}
+		c.Conditions = append(c.Conditions, next)
+	}
+	return c, nil
+}
+
+// parseSupportsInParens parses a <supports-in-parens>. Functions and
+// parenthesized values that aren't a known condition are general enclosed.
+func parseSupportsInParens(v ComponentValue, raw string, depth int) (SupportsCondition, error) {
+	switch {
+	case v.Type == FunctionValue:
+		arg := strings.TrimSpace(raw[len(v.Text)+1 : len(raw)-1])
+		switch {
+		case v.Text == "selector" && arg != "":
+			return SupportsCondition{Function: v.Text, Argument: arg}, nil
+		case (v.Text == "font-tech" || v.Text == "font-format") && len(v.Args) == 1 && v.Args[0].Type == IdentValue:
+			return SupportsCondition{Function: v.Text, Argument: strings.ToLower(v.Args[0].Text)}, nil
+		}
+		return SupportsCondition{Enclosed: raw}, nil
+	case v.Type != BlockValue || v.Text != "(":
+		return SupportsCondition{}, fmt.Errorf("unexpected %s", v)
+	}
+
+	inner := raw[1 : len(raw)-1]
+	if len(v.Args) > 1 && v.Args[0].Type == IdentValue && v.Args[1].IsDelim(":") {
+		property := v.Args[0].Text
+		if !strings.HasPrefix(property, "--") {
+			property = strings.ToLower(property)
+		}
+		value := strings.TrimSpace(inner[strings.IndexByte(inner, ':')+1:])
+		if bang := strings.LastIndexByte(value, '!'); bang >= 0 && strings.EqualFold(strings.TrimSpace(value[bang+1:]), "important") {
+			value = strings.TrimSpace(value[:bang])
+		}
+		return SupportsCondition{Property: property, Value: value}, nil
+	}
+	if depth < maxSupportsDepth {
+		if c, err := parseSupportsText(inner, depth+1); err == nil {
+			return c, nil
+		}
+	}
+	return SupportsCondition{Enclosed: raw}, nil
+}
+
+// Matches reports whether env supports the features tested by c.
+// Declarations are supported if the registry accepts them, custom
+// properties always are.
+func (c SupportsCondition) Matches(env SupportsEnvironment) bool {
+	switch c.Op {
+	case "not":
+		return !c.Conditions[0].Matches(env)
+	case "and":
+		for _, c := range c.Conditions {
+			if !c.Matches(env) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, c := range c.Conditions {
+			if c.Matches(env) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch c.Function {
+	case "selector":
+		return env.Selector != nil && env.Selector(c.Argument)
+	case "font-tech":
+		return containsString(env.FontTechs, c.Argument)
+	case "font-format":
+		return containsString(env.FontFormats, c.Argument)
+	}
+	if c.Property == "" {
+		return false
+	}
+	if strings.HasPrefix(c.Property, "--") {
+		return true
+	}
+	r := env.Registry
+	if r == nil {
+		r = DefaultRegistry
+	}
+	_, err := r.CSSStyle(c.Property, map[string]string{c.Property: c.Value})
+	return err == nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (c SupportsCondition) String() string {
+	switch c.Op {
+	case "not":
+		return "not " + c.Conditions[0].inParens()
+	case "and", "or":
+		conditions := make([]string, len(c.Conditions))
+		for i, c := range c.Conditions {
+			conditions[i] = c.inParens()
+		}
+		return strings.Join(conditions, " "+c.Op+" ")
+	}
+	switch {
+	case c.Function != "":
+		return c.Function + "(" + c.Argument + ")"
+	case c.Property != "":
+		return "(" + c.Property + ": " + c.Value + ")"
+	}
+	return c.Enclosed
+}
+
+// inParens returns c as a <supports-in-parens>.
+func (c SupportsCondition) inParens() string {
+	if c.Op == "" {
+		return c.String()
+	}
+	return "(" + c.String() + ")"
+}
+
+// SupportsHandler returns a Handler that reports a stylesheet to h as it
+// applies to env: the contents of the @supports rules whose condition env
+// supports are reported without the rule, and the other @supports rules
+// are skipped.
+func SupportsHandler(h Handler, env SupportsEnvironment) Handler {
+	return &conditionalHandler{h: h, rule: "supports", matches: func(prelude string) bool {
+		// rules with a malformed condition are ignored
+		c, err := ParseSupportsCondition(prelude)
+		return err == nil && c.Matches(env)
+	}}
+}
+
+// UnmarshalSupports is like Unmarshal, but also returns the rules of the
+// @supports rules whose condition env supports. Later rules override
+// earlier ones.
+func UnmarshalSupports(b []byte, env SupportsEnvironment) (map[Rule]map[string]string, error) {
+	h := &mapHandler{
+		css: make(map[Rule]map[string]string),
+	}
+	if err := parseBytes(context.Background(), b, SupportsHandler(h, env)); err != nil {
+		return h.css, err
+	}
+	return h.css, nil
+}
@@ -0,0 +1,190 @@
+package css
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSupportsCondition(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected SupportsCondition
+	}{
+		{"(display: grid)", SupportsCondition{Property: "display", Value: "grid"}},
+		{"(Display:Grid !important)", SupportsCondition{Property: "display", Value: "Grid"}},
+		{"(--Accent: { a: b })", SupportsCondition{Property: "--Accent", Value: "{ a: b }"}},
+		{"not (display: grid)", SupportsCondition{Op: "not", Conditions: []SupportsCondition{{Property: "display", Value: "grid"}}}},
+		{"((display: grid))", SupportsCondition{Property: "display", Value: "grid"}},
+		{"(gap: 1px) AND selector(a:has(> img)) and font-tech(Color-COLRv1)", SupportsCondition{Op: "and", Conditions: []SupportsCondition{
+			{Property: "gap", Value: "1px"},
+			{Function: "selector", Argument: "a:has(> img)"},
+			{Function: "font-tech", Argument: "color-colrv1"},
+		}}},
+		{"font-format(woff2) or ((a: b) and (c: d))", SupportsCondition{Op: "or", Conditions: []SupportsCondition{
+			{Function: "font-format", Argument: "woff2"},
+			{Op: "and", Conditions: []SupportsCondition{{Property: "a", Value: "b"}, {Property: "c", Value: "d"}}},
+		}}},
+		{"not(display: grid) or (foo bar) or font-tech(a b)", SupportsCondition{Op: "or", Conditions: []SupportsCondition{
+			{Enclosed: "not(display: grid)"},
+			{Enclosed: "(foo bar)"},
+			{Enclosed: "font-tech(a b)"},
+		}}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.value, func(t *testing.T) {
+			c, err := ParseSupportsCondition(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, c)
+		})
+	}
+
+	c, err := ParseSupportsCondition("(not ((a:b) or selector( a  b ))) and font-tech(palettes)")
+	assert.NoError(t, err)
+	assert.Equal(t, "(not ((a: b) or selector(a  b))) and font-tech(palettes)", c.String())
+
+	for _, value := range []string{
+		"",
+		"display: grid",
+		"not",
+		"(a: b) and",
+		"(a: b) (c: d)",
+		"(a: b) and (c: d) or (e: f)",
+		"not (a: b) and (c: d)",
+		"not ((a:b) or selector(a b)) and font-tech(palettes)",
+		"(a: b",
+		"selector(a))",
+	} {
+		_, err := ParseSupportsCondition(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseSupportsConditionNesting(t *testing.T) {
+	nested := func(levels int) string {
+		return strings.Repeat("(", levels) + "display: grid" + strings.Repeat(")", levels)
+	}
+	c, err := ParseSupportsCondition(nested(10))
+	assert.NoError(t, err)
+	assert.Equal(t, SupportsCondition{Property: "display", Value: "grid"}, c)
+
+	// deeper conditions are general enclosed, and parsed in linear time
+	start := time.Now()
+	c, err = ParseSupportsCondition(nested(5000))
+	assert.NoError(t, err)
+	assert.False(t, c.Matches(SupportsEnvironment{}))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSupportsConditionMatches(t *testing.T) {
+	env := SupportsEnvironment{
+		Selector:    func(selector string) bool { return selector == ":has(a)" },
+		FontTechs:   []string{"color-colrv1"},
+		FontFormats: []string{"woff2"},
+	}
+	noGrid := DefaultRegistry.Clone()
+	noGrid.Register("display", func(value string) (Style, error) {
+		if strings.Contains(value, "grid") {
+			return Style{}, errors.New("grid is not supported")
+		}
+		return DefaultRegistry.CSSStyle("display", map[string]string{"display": value})
+	}, PropertyMetadata{Initial: "inline"})
+	legacy := env
+	legacy.Registry = noGrid
+
+	cases := []struct {
+		condition string
+		expected  [2]bool
+	}{
+		{"(display: grid)", [2]bool{true, false}},
+		{"(display: flex)", [2]bool{true, true}},
+		{"(display: flexbox)", [2]bool{false, false}},
+		{"(display: grid !important)", [2]bool{true, false}},
+		{"(display: inherit)", [2]bool{true, true}},
+		{"not (display: grid)", [2]bool{false, true}},
+		{"(display: grid) and (gap: 1px)", [2]bool{true, false}},
+		{"(display: grid) or (margin: 1px 2px)", [2]bool{true, true}},
+		{"(transform: rotate(10deg)) and (not (width: red))", [2]bool{true, true}},
+		{"(color: rgb(0 0 0 / 50%))", [2]bool{true, true}},
+		{"(opacity: .5) and (box-shadow: 1px 1px red)", [2]bool{true, true}},
+		{"(-x-unknown: 1)", [2]bool{false, false}},
+		{"(--anything: { } )", [2]bool{true, true}},
+		{"selector(:has(a))", [2]bool{true, true}},
+		{"selector(a > b)", [2]bool{false, false}},
+		{"font-tech(color-colrv1) and font-format(WOFF2)", [2]bool{true, true}},
+		{"font-tech(palettes) or font-format(truetype)", [2]bool{false, false}},
+		// general enclosed conditions are false
+		{"foo(bar) or (color: red)", [2]bool{true, true}},
+		{"not foo(bar)", [2]bool{true, true}},
+		{"not(display: grid)", [2]bool{false, false}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.condition, func(t *testing.T) {
+			c, err := ParseSupportsCondition(tt.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, [2]bool{c.Matches(env), c.Matches(legacy)})
+		})
+	}
+
+	c, err := ParseSupportsCondition("selector(a)")
+	assert.NoError(t, err)
+	assert.False(t, c.Matches(SupportsEnvironment{}))
+}
+
+func TestSupportsHandler(t *testing.T) {
+	h := &recordingHandler{}
+	err := Parse(strings.NewReader(`
+@supports (display: grid) {
+	a { b: c }
+	@supports not (display: grid) { d { e: f } }
+	@media print { g { h: i } }
+}
+@supports (display: flexbox) { j { k: l } }
+@supports display: grid { m { n: o } }`), SupportsHandler(h, SupportsEnvironment{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		`rule ["a"]`, "decl b=c important=false", "end rule",
+		`at media "print"`, `rule ["g"]`, "decl h=i important=false", "end rule", "end at media",
+	}, h.events)
+
+	h = &recordingHandler{}
+	err = Parse(strings.NewReader(`@supports (display: grid) { @media print { g { h: i } } }`),
+		SupportsHandler(MediaHandler(h, MediaEnvironment{Type: "print"}), SupportsEnvironment{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{`rule ["g"]`, "decl h=i important=false", "end rule"}, h.events)
+}
+
+func TestUnmarshalSupports(t *testing.T) {
+	styleSheet := []byte(`
+.grid { display: block }
+@supports (display: grid) and (gap: 1px) {
+	.grid { display: grid; gap: 1px }
+}
+@supports not (display: grid) {
+	.grid { float: left }
+}`)
+	css, err := UnmarshalSupports(styleSheet, SupportsEnvironment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[Rule]map[string]string{".grid": {"display": "grid", "gap": "1px"}}, css)
+
+	noGap := DefaultRegistry.Clone()
+	noGap.Register("gap", func(string) (Style, error) { return Style{}, errors.New("unsupported") }, PropertyMetadata{})
+	css, err = UnmarshalSupports(styleSheet, SupportsEnvironment{Registry: noGap})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[Rule]map[string]string{".grid": {"display": "block"}}, css)
+}
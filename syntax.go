@@ -0,0 +1,739 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// primitiveTypes are the data types that match a single component value.
+var primitiveTypes = map[string]func(v ComponentValue) bool{
+	"length":            isLength,
+	"percentage":        isPercentage,
+	"length-percentage": isLengthPercentage,
+	"number":            isNumber,
+	"integer":           func(v ComponentValue) bool { return (v.Type == NumberValue && v.Integer) || mathType(v) == "number" },
+	"angle":             func(v ComponentValue) bool { return isDimension(v, angleUnits) || isZero(v) || mathType(v) == "angle" },
+	"time":              func(v ComponentValue) bool { return isDimension(v, timeUnits) || mathType(v) == "time" },
+	"resolution":        func(v ComponentValue) bool { return isDimension(v, resolutionUnits) || mathType(v) == "resolution" },
+	"flex":              func(v ComponentValue) bool { return v.Type == DimensionValue && v.Unit == "fr" },
+	"string":            func(v ComponentValue) bool { return v.Type == StringValue },
+	"url":               func(v ComponentValue) bool { return v.Type == URLValue },
+	"ident":             func(v ComponentValue) bool { return v.Type == IdentValue },
+	"custom-ident":      isCustomIdent,
+	"dashed-ident":      func(v ComponentValue) bool { return v.Type == IdentValue && strings.HasPrefix(v.Text, "--") },
+	"hex-color":         isHexColor,
+	"named-color":       isNamedColor,
+}
+
+var (
+	lengthUnits = map[string]bool{
+		"px": true, "em": true, "rem": true, "ex": true, "rex": true, "ch": true, "rch": true,
+		"cap": true, "ic": true, "lh": true, "rlh": true,
+		"vw": true, "vh": true, "vi": true, "vb": true, "vmin": true, "vmax": true,
+		"svw": true, "svh": true, "lvw": true, "lvh": true, "dvw": true, "dvh": true,
+		"cqw": true, "cqh": true, "cqi": true, "cqb": true, "cqmin": true, "cqmax": true,
+		"cm": true, "mm": true, "q": true, "in": true, "pt": true, "pc": true,
+	}
+	angleUnits      = map[string]bool{"deg": true, "rad": true, "grad": true, "turn": true}
+	timeUnits       = map[string]bool{"s": true, "ms": true}
+	resolutionUnits = map[string]bool{"dpi": true, "dpcm": true, "dppx": true, "x": true}
+)
+
+func isZero(v ComponentValue) bool {
+	return v.Type == NumberValue && v.Number == 0
+}
+
+func isNumber(v ComponentValue) bool {
+	return v.Type == NumberValue || mathType(v) == "number"
+}
+
+func isLength(v ComponentValue) bool {
+	return isDimension(v, lengthUnits) || isZero(v) || mathType(v) == "length"
+}
+
+func isPercentage(v ComponentValue) bool {
+	return v.Type == PercentageValue || mathType(v) == "percentage"
+}
+
+func isLengthPercentage(v ComponentValue) bool {
+	return isLength(v) || isPercentage(v) || mathType(v) == "length-percentage"
+}
+
+func isDimension(v ComponentValue, units map[string]bool) bool {
+	return v.Type == DimensionValue && units[v.Unit]
+}
+
+func isCustomIdent(v ComponentValue) bool {
+	if v.Type != IdentValue {
+		return false
+	}
+	switch strings.ToLower(v.Text) {
+	case "initial", "inherit", "unset", "revert", "revert-layer", "default":
+		return false
+	}
+	return true
+}
+
+func isNamedColor(v ComponentValue) bool {
+	if v.Type != IdentValue {
+		return false
+	}
+	_, ok := namedColors[strings.ToLower(v.Text)]
+	return ok
+}
+
+func isHexColor(v ComponentValue) bool {
+	if v.Type != HashValue {
+		return false
+	}
+	switch len(v.Text) {
+	case 3, 4, 6, 8:
+		_, err := strconv.ParseUint(v.Text, 16, 32)
+		return err == nil
+	}
+	return false
+}
+
+// productionSyntax holds the named productions that can be referenced as
+// <name> in value definitions.
+var productionSyntax = map[string]string{
+	"absolute-size":                    "xx-small | x-small | small | medium | large | x-large | xx-large | xxx-large",
+	"alpha-value":                      "<number> | <percentage>",
+	"angular-color-stop":               "<color> [ <angle> | <percentage> ]{0,2}",
+	"attachment":                       "scroll | fixed | local",
+	"auto-repeat":                      "repeat( [ auto-fill | auto-fit ] , [ <line-names>? <fixed-size> ]+ <line-names>? )",
+	"auto-track-list":                  "[ <line-names>? [ <fixed-size> | <fixed-repeat> ] ]* <line-names>? <auto-repeat> [ <line-names>? [ <fixed-size> | <fixed-repeat> ] ]* <line-names>?",
+	"baseline-position":                "[ first | last ]? baseline",
+	"bg-image":                         "none | <image>",
+	"bg-layer":                         "<bg-image> || <bg-position> [ / <bg-size> ]? || <repeat-style> || <attachment> || <visual-box> || <visual-box>",
+	"bg-position":                      "<position>",
+	"bg-size":                          "[ <length-percentage [0,∞]> | auto ]{1,2} | cover | contain",
+	"color":                            "<hex-color> | <named-color> | currentcolor | transparent | <color-function>",
+	"color-function":                   "rgb( <rgb-args> ) | rgba( <rgb-args> ) | hsl( <hsl-args> ) | hsla( <hsl-args> ) | hwb( <hwb-args> ) | lab( <lab-args> ) | lch( <lch-args> ) | oklab( <lab-args> ) | oklch( <lch-args> ) | color( <ident> [ <number> | <percentage> | none ]{3} [ / [ <alpha-value> | none ] ]? )",
+	"color-stop-list":                  "[ <linear-color-stop> | <length-percentage> ]#",
+	"conic-gradient-args":              "[ [ [ from <angle> ]? [ at <position> ]? ]! , ]? [ <angular-color-stop> | <angle> | <percentage> ]#",
+	"content-distribution":             "space-between | space-around | space-evenly | stretch",
+	"content-position":                 "center | start | end | flex-start | flex-end",
+	"counter-style":                    "<custom-ident> | symbols( <symbols-type>? [ <string> | <image> ]+ )",
+	"cubic-bezier-easing-function":     "ease | ease-in | ease-out | ease-in-out | cubic-bezier( <number [0,1]> , <number> , <number [0,1]> , <number> )",
+	"cursor-keyword":                   "auto | default | none | context-menu | help | pointer | progress | wait | cell | crosshair | text | vertical-text | alias | copy | move | no-drop | not-allowed | grab | grabbing | e-resize | n-resize | ne-resize | nw-resize | s-resize | se-resize | sw-resize | w-resize | ew-resize | ns-resize | nesw-resize | nwse-resize | col-resize | row-resize | all-scroll | zoom-in | zoom-out",
+	"display-box":                      "contents | none",
+	"display-inside":                   "flow | flow-root | table | flex | grid | ruby",
+	"display-internal":                 "table-row-group | table-header-group | table-footer-group | table-row | table-cell | table-column-group | table-column | table-caption | ruby-base | ruby-text | ruby-base-container | ruby-text-container",
+	"display-legacy":                   "inline-block | inline-table | inline-flex | inline-grid",
+	"display-listitem":                 "<display-outside>? && [ flow | flow-root ]? && list-item",
+	"display-outside":                  "block | inline | run-in",
+	"easing-function":                  "linear | <linear-easing-function> | <cubic-bezier-easing-function> | <step-easing-function>",
+	"explicit-track-list":              "[ <line-names>? <track-size> ]+ <line-names>?",
+	"family-name":                      "<string> | <custom-ident>+",
+	"feature-tag-value":                "<string> [ <integer [0,∞]> | on | off ]?",
+	"filter-function":                  "blur( <length [0,∞]>? ) | brightness( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | contrast( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | drop-shadow( [ <color>? && [ <length>{2} <length [0,∞]>? ] ] ) | grayscale( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | hue-rotate( <angle>? ) | invert( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | opacity( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | saturate( [ <number [0,∞]> | <percentage [0,∞]> ]? ) | sepia( [ <number [0,∞]> | <percentage [0,∞]> ]? )",
+	"filter-value-list":                "[ <filter-function> | <url> ]+",
+	"final-bg-layer":                   "<bg-image> || <bg-position> [ / <bg-size> ]? || <repeat-style> || <attachment> || <visual-box> || <visual-box> || <'background-color'>",
+	"fixed-breadth":                    "<length-percentage [0,∞]>",
+	"fixed-repeat":                     "repeat( <integer [1,∞]> , [ <line-names>? <fixed-size> ]+ <line-names>? )",
+	"fixed-size":                       "<fixed-breadth> | minmax( <fixed-breadth> , <track-breadth> ) | minmax( <inflexible-breadth> , <fixed-breadth> )",
+	"font-variant-css2":                "normal | small-caps",
+	"font-weight-absolute":             "normal | bold | <number [1,1000]>",
+	"font-width-css3":                  "normal | ultra-condensed | extra-condensed | condensed | semi-condensed | semi-expanded | expanded | extra-expanded | ultra-expanded",
+	"generic-family":                   "serif | sans-serif | cursive | fantasy | monospace | system-ui | emoji | math | fangsong | ui-serif | ui-sans-serif | ui-monospace | ui-rounded",
+	"gradient":                         "linear-gradient( <linear-gradient-args> ) | repeating-linear-gradient( <linear-gradient-args> ) | radial-gradient( <radial-gradient-args> ) | repeating-radial-gradient( <radial-gradient-args> ) | conic-gradient( <conic-gradient-args> ) | repeating-conic-gradient( <conic-gradient-args> )",
+	"grid-line":                        "auto | span && [ <integer [1,∞]> || <custom-ident> ] | [ <integer> && <custom-ident>? ] | <custom-ident>",
+	"hsl-args":                         "[ <hue> | none ] [ <percentage> | <number> | none ]{2} [ / [ <alpha-value> | none ] ]? | <hue> , <percentage>#{2} [ , <alpha-value> ]?",
+	"hue":                              "<number> | <angle>",
+	"hwb-args":                         "[ <hue> | none ] [ <percentage> | <number> | none ]{2} [ / [ <alpha-value> | none ] ]?",
+	"image":                            "<url> | <gradient>",
+	"inflexible-breadth":               "<length-percentage [0,∞]> | min-content | max-content | auto",
+	"keyframes-name":                   "<custom-ident> | <string>",
+	"lab-args":                         "[ <percentage> | <number> | none ]{3} [ / [ <alpha-value> | none ] ]?",
+	"lch-args":                         "[ <percentage> | <number> | none ]{2} [ <hue> | none ] [ / [ <alpha-value> | none ] ]?",
+	"line-name-list":                   "[ <line-names> | <name-repeat> ]+",
+	"line-names":                       "'[' <custom-ident>* ']'",
+	"line-style":                       "none | hidden | dotted | dashed | solid | double | groove | ridge | inset | outset",
+	"line-width":                       "<length [0,∞]> | thin | medium | thick",
+	"linear-color-stop":                "<color> <length-percentage>{0,2}",
+	"linear-easing-function":           "linear( [ <number> && <percentage>{0,2} ]# )",
+	"linear-gradient-args":             "[ [ <angle> | to <side-or-corner> ] , ]? <color-stop-list>",
+	"name-repeat":                      "repeat( [ <integer [1,∞]> | auto-fill ] , <line-names>+ )",
+	"outline-line-style":               "none | dotted | dashed | solid | double | groove | ridge | inset | outset",
+	"overflow-position":                "unsafe | safe",
+	"page-size":                        "A5 | A4 | A3 | B5 | B4 | JIS-B5 | JIS-B4 | letter | legal | ledger",
+	"position":                         "[ left | center | right | top | bottom | <length-percentage> ] | [ left | center | right | <length-percentage> ] [ top | center | bottom | <length-percentage> ] | [ center | [ left | right ] <length-percentage>? ] && [ center | [ top | bottom ] <length-percentage>? ]",
+	"radial-gradient-args":             "[ [ [ <radial-shape> || <radial-size> ]? [ at <position> ]? ]! , ]? <color-stop-list>",
+	"radial-shape":                     "circle | ellipse",
+	"radial-size":                      "closest-side | closest-corner | farthest-side | farthest-corner | <length [0,∞]> | <length-percentage [0,∞]>{2}",
+	"relative-size":                    "larger | smaller",
+	"repeat-style":                     "repeat-x | repeat-y | [ repeat | space | round | no-repeat ]{1,2}",
+	"rgb-args":                         "[ <percentage> | none ]{3} [ / [ <alpha-value> | none ] ]? | [ <number> | none ]{3} [ / [ <alpha-value> | none ] ]? | <percentage>#{3} [ , <alpha-value> ]? | <number>#{3} [ , <alpha-value> ]?",
+	"self-position":                    "center | start | end | self-start | self-end | flex-start | flex-end",
+	"shadow":                           "<color>? && [ <length>{2} [ <length [0,∞]> <length>? ]? ] && inset?",
+	"shadow-t":                         "[ <length>{2} <length [0,∞]>? ] && <color>?",
+	"side-or-corner":                   "[ left | right ] || [ top | bottom ]",
+	"single-animation":                 "<time [0,∞]> || <easing-function> || <time> || <single-animation-iteration-count> || <single-animation-direction> || <single-animation-fill-mode> || <single-animation-play-state> || [ none | <keyframes-name> ]",
+	"single-animation-direction":       "normal | reverse | alternate | alternate-reverse",
+	"single-animation-fill-mode":       "none | forwards | backwards | both",
+	"single-animation-iteration-count": "infinite | <number [0,∞]>",
+	"single-animation-play-state":      "running | paused",
+	"single-transition":                "<time [0,∞]> || <easing-function> || <time> || <transition-behavior-value> || [ none | <single-transition-property> ]",
+	"single-transition-property":       "all | <custom-ident>",
+	"size-keyword":                     "auto | min-content | max-content | fit-content( <length-percentage [0,∞]> )",
+	"step-easing-function":             "step-start | step-end | steps( <integer [1,∞]> [ , <step-position> ]? )",
+	"step-position":                    "jump-start | jump-end | jump-none | jump-both | start | end",
+	"symbols-type":                     "cyclic | numeric | alphabetic | symbolic | fixed",
+	"system-font":                      "caption | icon | menu | message-box | small-caption | status-bar",
+	"track-breadth":                    "<length-percentage [0,∞]> | <flex [0,∞]> | min-content | max-content | auto",
+	"track-list":                       "[ <line-names>? [ <track-size> | <track-repeat> ] ]+ <line-names>?",
+	"track-repeat":                     "repeat( <integer [1,∞]> , [ <line-names>? <track-size> ]+ <line-names>? )",
+	"track-size":                       "<track-breadth> | minmax( <inflexible-breadth> , <track-breadth> ) | fit-content( <length-percentage [0,∞]> )",
+	"transform-function":               "matrix( <number>#{6} ) | matrix3d( <number>#{16} ) | perspective( [ <length [0,∞]> | none ] ) | rotate( <angle> ) | rotate3d( <number> , <number> , <number> , <angle> ) | rotateX( <angle> ) | rotateY( <angle> ) | rotateZ( <angle> ) | scale( [ <number> | <percentage> ]#{1,2} ) | scale3d( [ <number> | <percentage> ]#{3} ) | scaleX( [ <number> | <percentage> ] ) | scaleY( [ <number> | <percentage> ] ) | scaleZ( [ <number> | <percentage> ] ) | skew( <angle> [ , <angle> ]? ) | skewX( <angle> ) | skewY( <angle> ) | translate( <length-percentage> [ , <length-percentage> ]? ) | translate3d( <length-percentage> , <length-percentage> , <length> ) | translateX( <length-percentage> ) | translateY( <length-percentage> ) | translateZ( <length> )",
+	"transform-list":                   "<transform-function>+",
+	"transition-behavior-value":        "normal | allow-discrete",
+	"visual-box":                       "border-box | padding-box | content-box",
+}
+
+// propertySyntax holds the value definitions of the properties whose style
+// handlers are generated from their grammar. Adding a line here adds the
+// property to DefaultRegistry.
+var propertySyntax = map[string]string{
+	"align-content":              "normal | <baseline-position> | <content-distribution> | <overflow-position>? <content-position>",
+	"align-items":                "normal | stretch | <baseline-position> | <overflow-position>? <self-position>",
+	"align-self":                 "auto | normal | stretch | <baseline-position> | <overflow-position>? <self-position>",
+	"animation":                  "<single-animation>#",
+	"animation-delay":            "<time>#",
+	"animation-direction":        "<single-animation-direction>#",
+	"animation-duration":         "<time [0,∞]>#",
+	"animation-fill-mode":        "<single-animation-fill-mode>#",
+	"animation-iteration-count":  "<single-animation-iteration-count>#",
+	"animation-name":             "[ none | <keyframes-name> ]#",
+	"animation-play-state":       "<single-animation-play-state>#",
+	"animation-timing-function":  "<easing-function>#",
+	"backdrop-filter":            "none | <filter-value-list>",
+	"background":                 "[ <bg-layer> , ]* <final-bg-layer>",
+	"background-attachment":      "<attachment>#",
+	"background-clip":            "<visual-box>#",
+	"background-color":           "<color>",
+	"background-image":           "<bg-image>#",
+	"background-origin":          "<visual-box>#",
+	"background-position":        "<bg-position>#",
+	"background-repeat":          "<repeat-style>#",
+	"background-size":            "<bg-size>#",
+	"border":                     "<line-width> || <line-style> || <color>",
+	"border-block":               "<line-width> || <line-style> || <color>",
+	"border-block-color":         "<color>{1,2}",
+	"border-block-end":           "<line-width> || <line-style> || <color>",
+	"border-block-end-color":     "<color>",
+	"border-block-end-style":     "<line-style>",
+	"border-block-end-width":     "<line-width>",
+	"border-block-start":         "<line-width> || <line-style> || <color>",
+	"border-block-start-color":   "<color>",
+	"border-block-start-style":   "<line-style>",
+	"border-block-start-width":   "<line-width>",
+	"border-block-style":         "<line-style>{1,2}",
+	"border-block-width":         "<line-width>{1,2}",
+	"border-bottom":              "<line-width> || <line-style> || <color>",
+	"border-bottom-color":        "<color>",
+	"border-bottom-left-radius":  "<length-percentage [0,∞]>{1,2}",
+	"border-bottom-right-radius": "<length-percentage [0,∞]>{1,2}",
+	"border-bottom-style":        "<line-style>",
+	"border-bottom-width":        "<line-width>",
+	"border-collapse":            "separate | collapse",
+	"border-color":               "<color>{1,4}",
+	"border-image":               "<'border-image-source'> || <'border-image-slice'> [ / <'border-image-width'> | / <'border-image-width'>? / <'border-image-outset'> ]? || <'border-image-repeat'>",
+	"border-image-outset":        "[ <length [0,∞]> | <number [0,∞]> ]{1,4}",
+	"border-image-repeat":        "[ stretch | repeat | round | space ]{1,2}",
+	"border-image-slice":         "[ <number [0,∞]> | <percentage [0,∞]> ]{1,4} && fill?",
+	"border-image-source":        "none | <image>",
+	"border-image-width":         "[ <length-percentage [0,∞]> | <number [0,∞]> | auto ]{1,4}",
+	"border-inline":              "<line-width> || <line-style> || <color>",
+	"border-inline-color":        "<color>{1,2}",
+	"border-inline-end":          "<line-width> || <line-style> || <color>",
+	"border-inline-end-color":    "<color>",
+	"border-inline-end-style":    "<line-style>",
+	"border-inline-end-width":    "<line-width>",
+	"border-inline-start":        "<line-width> || <line-style> || <color>",
+	"border-inline-start-color":  "<color>",
+	"border-inline-start-style":  "<line-style>",
+	"border-inline-start-width":  "<line-width>",
+	"border-inline-style":        "<line-style>{1,2}",
+	"border-inline-width":        "<line-width>{1,2}",
+	"border-left":                "<line-width> || <line-style> || <color>",
+	"border-left-color":          "<color>",
+	"border-left-style":          "<line-style>",
+	"border-left-width":          "<line-width>",
+	"border-radius":              "<length-percentage [0,∞]>{1,4} [ / <length-percentage [0,∞]>{1,4} ]?",
+	"border-right":               "<line-width> || <line-style> || <color>",
+	"border-right-color":         "<color>",
+	"border-right-style":         "<line-style>",
+	"border-right-width":         "<line-width>",
+	"border-spacing":             "<length [0,∞]>{1,2}",
+	"border-style":               "<line-style>{1,4}",
+	"border-top":                 "<line-width> || <line-style> || <color>",
+	"border-top-color":           "<color>",
+	"border-top-left-radius":     "<length-percentage [0,∞]>{1,2}",
+	"border-top-right-radius":    "<length-percentage [0,∞]>{1,2}",
+	"border-top-style":           "<line-style>",
+	"border-top-width":           "<line-width>",
+	"border-width":               "<line-width>{1,4}",
+	"bottom":                     "<length-percentage> | auto",
+	"box-shadow":                 "none | <shadow>#",
+	"box-sizing":                 "content-box | border-box",
+	"break-after":                "auto | avoid | always | all | avoid-page | page | left | right | recto | verso | avoid-column | column | avoid-region | region",
+	"break-before":               "auto | avoid | always | all | avoid-page | page | left | right | recto | verso | avoid-column | column | avoid-region | region",
+	"break-inside":               "auto | avoid | avoid-page | avoid-column | avoid-region",
+	"caption-side":               "top | bottom",
+	"clear":                      "none | left | right | both | inline-start | inline-end",
+	"clip":                       "rect( [ <length> | auto ]#{4} ) | rect( [ <length> | auto ]{4} ) | auto",
+	"color":                      "<color>",
+	"column-gap":                 "normal | <length-percentage [0,∞]>",
+	"cursor":                     "[ <url> [ <number> <number> ]? , ]* <cursor-keyword>",
+	"direction":                  "ltr | rtl",
+	"display":                    "[ <display-outside> || <display-inside> ] | <display-listitem> | <display-internal> | <display-box> | <display-legacy>",
+	"empty-cells":                "show | hide",
+	"filter":                     "none | <filter-value-list>",
+	"flex":                       "none | [ <'flex-grow'> <'flex-shrink'>? || <'flex-basis'> ]",
+	"flex-basis":                 "content | <'width'>",
+	"flex-direction":             "row | row-reverse | column | column-reverse",
+	"flex-flow":                  "<'flex-direction'> || <'flex-wrap'>",
+	"flex-grow":                  "<number [0,∞]>",
+	"flex-shrink":                "<number [0,∞]>",
+	"flex-wrap":                  "nowrap | wrap | wrap-reverse",
+	"float":                      "left | right | none | inline-start | inline-end",
+	"font":                       "[ [ <'font-style'> || <font-variant-css2> || <'font-weight'> || <font-width-css3> ]? <'font-size'> [ / <'line-height'> ]? <'font-family'> ] | <system-font>",
+	"font-family":                "[ <generic-family> | <family-name> ]#",
+	"font-feature-settings":      "normal | <feature-tag-value>#",
+	"font-size":                  "<absolute-size> | <relative-size> | <length-percentage [0,∞]>",
+	"font-stretch":               "<font-width-css3> | <percentage [0,∞]>",
+	"font-style":                 "normal | italic | oblique <angle [-90,90]>?",
+	"font-variant":               "normal | none | small-caps | all-small-caps | petite-caps | all-petite-caps | unicase | titling-caps",
+	"font-weight":                "<font-weight-absolute> | bolder | lighter",
+	"gap":                        "<'row-gap'> <'column-gap'>?",
+	"grid":                       "<'grid-template'> | <'grid-template-rows'> / [ auto-flow && dense? ] <'grid-auto-columns'>? | [ auto-flow && dense? ] <'grid-auto-rows'>? / <'grid-template-columns'>",
+	"grid-area":                  "<grid-line> [ / <grid-line> ]{0,3}",
+	"grid-auto-columns":          "<track-size>+",
+	"grid-auto-flow":             "[ row | column ] || dense",
+	"grid-auto-rows":             "<track-size>+",
+	"grid-column":                "<grid-line> [ / <grid-line> ]?",
+	"grid-column-end":            "<grid-line>",
+	"grid-column-start":          "<grid-line>",
+	"grid-row":                   "<grid-line> [ / <grid-line> ]?",
+	"grid-row-end":               "<grid-line>",
+	"grid-row-start":             "<grid-line>",
+	"grid-template":              "none | [ <'grid-template-rows'> / <'grid-template-columns'> ] | [ <line-names>? <string> <track-size>? <line-names>? ]+ [ / <explicit-track-list> ]?",
+	"grid-template-areas":        "none | <string>+",
+	"grid-template-columns":      "none | <track-list> | <auto-track-list> | subgrid <line-name-list>?",
+	"grid-template-rows":         "none | <track-list> | <auto-track-list> | subgrid <line-name-list>?",
+	"height":                     "<length-percentage [0,∞]> | <size-keyword>",
+	"inset":                      "<'top'>{1,4}",
+	"inset-block":                "<'top'>{1,2}",
+	"inset-block-end":            "<'top'>",
+	"inset-block-start":          "<'top'>",
+	"inset-inline":               "<'top'>{1,2}",
+	"inset-inline-end":           "<'top'>",
+	"inset-inline-start":         "<'top'>",
+	"justify-content":            "normal | <content-distribution> | <overflow-position>? [ <content-position> | left | right ]",
+	"justify-items":              "normal | stretch | <baseline-position> | <overflow-position>? [ <self-position> | left | right ] | legacy | legacy && [ left | right | center ]",
+	"justify-self":               "auto | normal | stretch | <baseline-position> | <overflow-position>? [ <self-position> | left | right ]",
+	"left":                       "<length-percentage> | auto",
+	"letter-spacing":             "normal | <length>",
+	"line-height":                "normal | <number [0,∞]> | <length-percentage [0,∞]>",
+	"list-style":                 "<'list-style-position'> || <'list-style-image'> || <'list-style-type'>",
+	"list-style-image":           "<image> | none",
+	"list-style-position":        "inside | outside",
+	"list-style-type":            "<counter-style> | <string> | none",
+	"margin":                     "<'margin-top'>{1,4}",
+	"margin-block":               "<'margin-top'>{1,2}",
+	"margin-block-end":           "<'margin-top'>",
+	"margin-block-start":         "<'margin-top'>",
+	"margin-bottom":              "<length-percentage> | auto",
+	"margin-inline":              "<'margin-top'>{1,2}",
+	"margin-inline-end":          "<'margin-top'>",
+	"margin-inline-start":        "<'margin-top'>",
+	"margin-left":                "<length-percentage> | auto",
+	"margin-right":               "<length-percentage> | auto",
+	"margin-top":                 "<length-percentage> | auto",
+	"max-height":                 "none | <length-percentage [0,∞]> | min-content | max-content | fit-content( <length-percentage [0,∞]> )",
+	"max-width":                  "none | <length-percentage [0,∞]> | min-content | max-content | fit-content( <length-percentage [0,∞]> )",
+	"min-height":                 "<length-percentage [0,∞]> | <size-keyword>",
+	"min-width":                  "<length-percentage [0,∞]> | <size-keyword>",
+	"opacity":                    "<alpha-value>",
+	"order":                      "<integer>",
+	"orphans":                    "<integer [1,∞]>",
+	"outline":                    "<'outline-width'> || <'outline-style'> || <'outline-color'>",
+	"outline-color":              "auto | <color>",
+	"outline-offset":             "<length>",
+	"outline-style":              "auto | <outline-line-style>",
+	"outline-width":              "<line-width>",
+	"overflow":                   "[ visible | hidden | clip | scroll | auto ]{1,2}",
+	"overflow-wrap":              "normal | break-word | anywhere",
+	"overflow-x":                 "visible | hidden | clip | scroll | auto",
+	"overflow-y":                 "visible | hidden | clip | scroll | auto",
+	"padding":                    "<'padding-top'>{1,4}",
+	"padding-block":              "<'padding-top'>{1,2}",
+	"padding-block-end":          "<'padding-top'>",
+	"padding-block-start":        "<'padding-top'>",
+	"padding-bottom":             "<length-percentage [0,∞]>",
+	"padding-inline":             "<'padding-top'>{1,2}",
+	"padding-inline-end":         "<'padding-top'>",
+	"padding-inline-start":       "<'padding-top'>",
+	"padding-left":               "<length-percentage [0,∞]>",
+	"padding-right":              "<length-percentage [0,∞]>",
+	"padding-top":                "<length-percentage [0,∞]>",
+	"page-break-after":           "auto | always | avoid | left | right",
+	"page-break-before":          "auto | always | avoid | left | right",
+	"page-break-inside":          "auto | avoid",
+	"perspective":                "none | <length [0,∞]>",
+	"position":                   "static | relative | absolute | sticky | fixed",
+	"right":                      "<length-percentage> | auto",
+	"rotate":                     "none | <angle> | [ x | y | z | <number>{3} ] && <angle>",
+	"row-gap":                    "normal | <length-percentage [0,∞]>",
+	"scale":                      "none | [ <number> | <percentage> ]{1,3}",
+	"table-layout":               "auto | fixed",
+	"text-align":                 "start | end | left | right | center | justify | match-parent | justify-all",
+	"text-decoration":            "<'text-decoration-line'> || <'text-decoration-style'> || <'text-decoration-color'> || <'text-decoration-thickness'>",
+	"text-decoration-color":      "<color>",
+	"text-decoration-line":       "none | [ underline || overline || line-through || blink ]",
+	"text-decoration-style":      "solid | double | dotted | dashed | wavy",
+	"text-decoration-thickness":  "auto | from-font | <length-percentage>",
+	"text-indent":                "<length-percentage> && hanging? && each-line?",
+	"text-overflow":              "[ clip | ellipsis | <string> ]{1,2}",
+	"text-shadow":                "none | <shadow-t>#",
+	"text-transform":             "none | [ capitalize | uppercase | lowercase ] || full-width || full-size-kana",
+	"text-underline-offset":      "auto | <length-percentage>",
+	"top":                        "<length-percentage> | auto",
+	"transform":                  "none | <transform-list>",
+	"transform-origin":           "[ left | center | right | top | bottom | <length-percentage> ] | [ left | center | right | <length-percentage> ] [ top | center | bottom | <length-percentage> ] <length>? | [ [ center | left | right ] && [ center | top | bottom ] ] <length>?",
+	"transition":                 "<single-transition>#",
+	"transition-behavior":        "<transition-behavior-value>#",
+	"transition-delay":           "<time>#",
+	"transition-duration":        "<time [0,∞]>#",
+	"transition-property":        "none | <single-transition-property>#",
+	"transition-timing-function": "<easing-function>#",
+	"translate":                  "none | <length-percentage> [ <length-percentage> <length>? ]?",
+	"unicode-bidi":               "normal | embed | isolate | bidi-override | isolate-override | plaintext",
+	"vertical-align":             "baseline | sub | super | text-top | text-bottom | middle | top | bottom | <length-percentage>",
+	"visibility":                 "visible | hidden | collapse",
+	"white-space":                "normal | pre | nowrap | pre-wrap | break-spaces | pre-line",
+	"widows":                     "<integer [1,∞]>",
+	"width":                      "<length-percentage [0,∞]> | <size-keyword>",
+	"word-break":                 "normal | break-all | keep-all | break-word",
+	"word-spacing":               "normal | <length-percentage>",
+	"z-index":                    "auto | <integer>",
+}
+
+// propertyConverters turn the values matched by the syntax of a property
+// into its typed style value. Properties without a converter have the
+// matched []ComponentValue as their value.
+var propertyConverters = map[string]func(values []ComponentValue) interface{}{
+	"align-content":              alignment,
+	"align-items":                alignment,
+	"align-self":                 alignment,
+	"animation":                  animation,
+	"animation-delay":            timeList,
+	"animation-direction":        keywordList,
+	"animation-duration":         timeList,
+	"animation-fill-mode":        keywordList,
+	"animation-iteration-count":  iterationCounts,
+	"animation-name":             animationNames,
+	"animation-play-state":       keywordList,
+	"animation-timing-function":  easingList,
+	"backdrop-filter":            filter,
+	"background":                 background,
+	"background-attachment":      keywordList,
+	"background-clip":            keywordList,
+	"background-color":           colorValue,
+	"background-image":           backgroundImages,
+	"background-origin":          keywordList,
+	"background-position":        backgroundPositions,
+	"background-repeat":          backgroundRepeats,
+	"background-size":            backgroundSizes,
+	"border":                     border,
+	"border-block":               border,
+	"border-block-color":         logicalColors,
+	"border-block-end":           border,
+	"border-block-end-color":     colorValue,
+	"border-block-end-style":     keyword,
+	"border-block-end-width":     lengthValue,
+	"border-block-start":         border,
+	"border-block-start-color":   colorValue,
+	"border-block-start-style":   keyword,
+	"border-block-start-width":   lengthValue,
+	"border-block-style":         logicalKeywords,
+	"border-block-width":         logicalLengths,
+	"border-bottom":              border,
+	"border-bottom-color":        colorValue,
+	"border-bottom-left-radius":  cornerRadius,
+	"border-bottom-right-radius": cornerRadius,
+	"border-bottom-style":        keyword,
+	"border-bottom-width":        lengthValue,
+	"border-collapse":            keyword,
+	"border-color":               boxColors,
+	"border-image":               borderImage,
+	"border-image-outset":        boxLengths,
+	"border-image-repeat":        borderImageRepeat,
+	"border-image-slice":         borderImageSlice,
+	"border-image-source":        imageValue,
+	"border-image-width":         boxLengths,
+	"border-inline":              border,
+	"border-inline-color":        logicalColors,
+	"border-inline-end":          border,
+	"border-inline-end-color":    colorValue,
+	"border-inline-end-style":    keyword,
+	"border-inline-end-width":    lengthValue,
+	"border-inline-start":        border,
+	"border-inline-start-color":  colorValue,
+	"border-inline-start-style":  keyword,
+	"border-inline-start-width":  lengthValue,
+	"border-inline-style":        logicalKeywords,
+	"border-inline-width":        logicalLengths,
+	"border-left":                border,
+	"border-left-color":          colorValue,
+	"border-left-style":          keyword,
+	"border-left-width":          lengthValue,
+	"border-radius":              borderRadius,
+	"border-right":               border,
+	"border-right-color":         colorValue,
+	"border-right-style":         keyword,
+	"border-right-width":         lengthValue,
+	"border-spacing":             logicalLengths,
+	"border-style":               boxKeywords,
+	"border-top":                 border,
+	"border-top-color":           colorValue,
+	"border-top-left-radius":     cornerRadius,
+	"border-top-right-radius":    cornerRadius,
+	"border-top-style":           keyword,
+	"border-top-width":           lengthValue,
+	"border-width":               boxLengths,
+	"bottom":                     lengthValue,
+	"box-shadow":                 shadows,
+	"box-sizing":                 keyword,
+	"break-after":                keyword,
+	"break-before":               keyword,
+	"break-inside":               keyword,
+	"caption-side":               keyword,
+	"clear":                      clear,
+	"clip":                       clip,
+	"color":                      colorValue,
+	"column-gap":                 lengthValue,
+	"cursor":                     cursor,
+	"direction":                  keyword,
+	"display":                    display,
+	"empty-cells":                keyword,
+	"filter":                     filter,
+	"flex":                       flex,
+	"flex-basis":                 lengthValue,
+	"flex-direction":             keyword,
+	"flex-flow":                  flexFlow,
+	"flex-grow":                  numberValue,
+	"flex-shrink":                numberValue,
+	"flex-wrap":                  keyword,
+	"float":                      float,
+	"font":                       font,
+	"font-family":                fontFamilies,
+	"font-feature-settings":      fontFeatureSettings,
+	"font-size":                  lengthValue,
+	"font-stretch":               fontStretch,
+	"font-style":                 fontStyle,
+	"font-variant":               keyword,
+	"font-weight":                fontWeight,
+	"gap":                        gap,
+	"grid":                       grid,
+	"grid-area":                  gridArea,
+	"grid-auto-columns":          gridTrackSizes,
+	"grid-auto-flow":             gridAutoFlow,
+	"grid-auto-rows":             gridTrackSizes,
+	"grid-column":                gridPlacement,
+	"grid-column-end":            gridLine,
+	"grid-column-start":          gridLine,
+	"grid-row":                   gridPlacement,
+	"grid-row-end":               gridLine,
+	"grid-row-start":             gridLine,
+	"grid-template":              gridTemplate,
+	"grid-template-areas":        gridTemplateAreas,
+	"grid-template-columns":      gridTrackList,
+	"grid-template-rows":         gridTrackList,
+	"height":                     lengthValue,
+	"inset":                      boxLengths,
+	"inset-block":                logicalLengths,
+	"inset-block-end":            lengthValue,
+	"inset-block-start":          lengthValue,
+	"inset-inline":               logicalLengths,
+	"inset-inline-end":           lengthValue,
+	"inset-inline-start":         lengthValue,
+	"justify-content":            alignment,
+	"justify-items":              alignment,
+	"justify-self":               alignment,
+	"left":                       lengthValue,
+	"letter-spacing":             lengthValue,
+	"line-height":                lengthValue,
+	"list-style":                 listStyle,
+	"list-style-image":           imageValue,
+	"list-style-position":        keyword,
+	"list-style-type":            listStyleType,
+	"margin":                     boxLengths,
+	"margin-block":               logicalLengths,
+	"margin-block-end":           lengthValue,
+	"margin-block-start":         lengthValue,
+	"margin-bottom":              lengthValue,
+	"margin-inline":              logicalLengths,
+	"margin-inline-end":          lengthValue,
+	"margin-inline-start":        lengthValue,
+	"margin-left":                lengthValue,
+	"margin-right":               lengthValue,
+	"margin-top":                 lengthValue,
+	"max-height":                 lengthValue,
+	"max-width":                  lengthValue,
+	"min-height":                 lengthValue,
+	"min-width":                  lengthValue,
+	"opacity":                    alphaValue,
+	"order":                      integerValue,
+	"orphans":                    integerValue,
+	"outline":                    outline,
+	"outline-color":              outlineColor,
+	"outline-offset":             lengthValue,
+	"outline-style":              keyword,
+	"outline-width":              lengthValue,
+	"overflow":                   overflow,
+	"overflow-wrap":              overflowWrap,
+	"overflow-x":                 keyword,
+	"overflow-y":                 keyword,
+	"padding":                    boxLengths,
+	"padding-block":              logicalLengths,
+	"padding-block-end":          lengthValue,
+	"padding-block-start":        lengthValue,
+	"padding-bottom":             lengthValue,
+	"padding-inline":             logicalLengths,
+	"padding-inline-end":         lengthValue,
+	"padding-inline-start":       lengthValue,
+	"padding-left":               lengthValue,
+	"padding-right":              lengthValue,
+	"padding-top":                lengthValue,
+	"page-break-after":           pageBreak,
+	"page-break-before":          pageBreak,
+	"page-break-inside":          pageBreak,
+	"perspective":                lengthValue,
+	"position":                   positionScheme,
+	"right":                      lengthValue,
+	"rotate":                     rotateValue,
+	"row-gap":                    lengthValue,
+	"scale":                      scaleValue,
+	"table-layout":               keyword,
+	"text-align":                 textAlign,
+	"text-decoration":            textDecoration,
+	"text-decoration-color":      colorValue,
+	"text-decoration-line":       textDecorationLine,
+	"text-decoration-style":      keyword,
+	"text-decoration-thickness":  lengthValue,
+	"text-indent":                textIndent,
+	"text-overflow":              textOverflow,
+	"text-shadow":                shadows,
+	"text-transform":             textTransform,
+	"text-underline-offset":      lengthValue,
+	"top":                        lengthValue,
+	"transform":                  transform,
+	"transform-origin":           transformOrigin,
+	"transition":                 transition,
+	"transition-behavior":        keywordList,
+	"transition-delay":           timeList,
+	"transition-duration":        timeList,
+	"transition-property":        transitionProperties,
+	"transition-timing-function": easingList,
+	"translate":                  translateValue,
+	"unicode-bidi":               keyword,
+	"vertical-align":             lengthValue,
+	"visibility":                 visibility,
+	"white-space":                whiteSpace,
+	"widows":                     integerValue,
+	"width":                      lengthValue,
+	"word-break":                 wordBreak,
+	"word-spacing":               lengthValue,
+	"z-index":                    zIndex,
+}
+
+// keyword converts a single keyword to lower case.
+func keyword(values []ComponentValue) interface{} {
+	return strings.ToLower(values[0].Text)
+}
+
+// keywordList converts a comma separated list of keywords.
+func keywordList(values []ComponentValue) interface{} {
+	var keywords []string
+	for _, layer := range splitCommas(values) {
+		keywords = append(keywords, strings.ToLower(layer[0].Text))
+	}
+	return keywords
+}
+
+func colorValue(values []ComponentValue) interface{} {
+	return colorOf(values[0])
+}
+
+func lengthValue(values []ComponentValue) interface{} {
+	return lengthOf(values[0])
+}
+
+func imageValue(values []ComponentValue) interface{} {
+	return imageOf(values[0])
+}
+
+var (
+	productionGrammars = compileGrammars(productionSyntax)
+	propertyGrammars   = compileGrammars(propertySyntax)
+)
+
+// withSyntaxHandlers adds the handlers generated from propertySyntax to
+// handlers, unless they have one already.
+func withSyntaxHandlers(handlers map[string]StyleHandler) map[string]StyleHandler {
+	for name, g := range propertyGrammars {
+		if _, ok := handlers[name]; ok {
+			continue
+		}
+		if convert, ok := propertyConverters[name]; ok {
+			handlers[name] = g.typedHandler(convert)
+		} else {
+			handlers[name] = g.Handler()
+		}
+		if validate, ok := propertyValidators[name]; ok {
+			handlers[name] = validatedHandler(handlers[name], validate)
+		}
+	}
+	return handlers
+}
+
+// propertyValidators check constraints on property values that the value
+// definition syntax can't express.
+var propertyValidators = map[string]func(values []ComponentValue) error{
+	"animation":                  validateEasing,
+	"animation-timing-function":  validateEasing,
+	"grid":                       validateGridAreas,
+	"grid-area":                  validateGridLines,
+	"grid-column":                validateGridLines,
+	"grid-column-end":            validateGridLines,
+	"grid-column-start":          validateGridLines,
+	"grid-row":                   validateGridLines,
+	"grid-row-end":               validateGridLines,
+	"grid-row-start":             validateGridLines,
+	"grid-template":              validateGridAreas,
+	"grid-template-areas":        validateGridAreas,
+	"transition":                 validateTransition,
+	"transition-property":        validateTransition,
+	"transition-timing-function": validateEasing,
+}
+
+// validatedHandler returns a handler that rejects the values accepted by h
+// that validate returns an error for.
+func validatedHandler(h StyleHandler, validate func([]ComponentValue) error) StyleHandler {
+	return func(value string) (Style, error) {
+		s, err := h(value)
+		if err != nil {
+			return s, err
+		}
+		if _, ok := s.Value.(CSSWideKeyword); ok {
+			return s, nil
+		}
+		values, _ := ParseValue(value)
+		if err := validate(values); err != nil {
+			return Style{}, err
+		}
+		return s, nil
+	}
+}
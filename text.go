@@ -0,0 +1,225 @@
+package css
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TextAlign is the typed value of the text-align property.
+type TextAlign int
+
+const (
+	TextAlignStart TextAlign = iota
+	TextAlignEnd
+	TextAlignLeft
+	TextAlignRight
+	TextAlignCenter
+	TextAlignJustify
+	TextAlignMatchParent
+	TextAlignJustifyAll
+)
+
+var textAligns = []string{"start", "end", "left", "right", "center", "justify", "match-parent", "justify-all"}
+
+func (a TextAlign) String() string {
+	return enumString(textAligns, int(a))
+}
+
+// Physical returns the alignment with start and end resolved to left or
+// right for the given direction.
+func (a TextAlign) Physical(rtl bool) TextAlign {
+	switch {
+	case a == TextAlignStart && !rtl, a == TextAlignEnd && rtl:
+		return TextAlignLeft
+	case a == TextAlignStart, a == TextAlignEnd:
+		return TextAlignRight
+	}
+	return a
+}
+
+// TextIndent is the typed value of the text-indent property.
+type TextIndent struct {
+	Length Length
+	// Hanging inverts which lines are indented.
+	Hanging bool
+	// EachLine indents every line after a forced line break too.
+	EachLine bool
+}
+
+func (i TextIndent) String() string {
+	s := i.Length.String()
+	if i.Hanging {
+		s += " hanging"
+	}
+	if i.EachLine {
+		s += " each-line"
+	}
+	return s
+}
+
+// TextTransform is the typed value of the text-transform property.
+type TextTransform struct {
+	// Case is "none", "capitalize", "uppercase", "lowercase" or empty.
+	Case         string
+	FullWidth    bool
+	FullSizeKana bool
+}
+
+func (t TextTransform) String() string {
+	var parts []string
+	if t.Case != "" {
+		parts = append(parts, t.Case)
+	}
+	if t.FullWidth {
+		parts = append(parts, "full-width")
+	}
+	if t.FullSizeKana {
+		parts = append(parts, "full-size-kana")
+	}
+	return strings.Join(parts, " ")
+}
+
+// WhiteSpace is the typed value of the white-space property.
+type WhiteSpace int
+
+const (
+	WhiteSpaceNormal WhiteSpace = iota
+	WhiteSpacePre
+	WhiteSpaceNowrap
+	WhiteSpacePreWrap
+	WhiteSpaceBreakSpaces
+	WhiteSpacePreLine
+)
+
+var whiteSpaces = []string{"normal", "pre", "nowrap", "pre-wrap", "break-spaces", "pre-line"}
+
+func (w WhiteSpace) String() string {
+	return enumString(whiteSpaces, int(w))
+}
+
+// CollapsesSpaces reports whether sequences of white space collapse.
+func (w WhiteSpace) CollapsesSpaces() bool {
+	return w == WhiteSpaceNormal || w == WhiteSpaceNowrap || w == WhiteSpacePreLine
+}
+
+// Wraps reports whether lines wrap at soft wrap opportunities.
+func (w WhiteSpace) Wraps() bool {
+	return w != WhiteSpacePre && w != WhiteSpaceNowrap
+}
+
+// WordBreak is the typed value of the word-break property.
+type WordBreak int
+
+const (
+	WordBreakNormal WordBreak = iota
+	WordBreakBreakAll
+	WordBreakKeepAll
+	WordBreakBreakWord
+)
+
+var wordBreaks = []string{"normal", "break-all", "keep-all", "break-word"}
+
+func (w WordBreak) String() string {
+	return enumString(wordBreaks, int(w))
+}
+
+// OverflowWrap is the typed value of the overflow-wrap property.
+type OverflowWrap int
+
+const (
+	OverflowWrapNormal OverflowWrap = iota
+	OverflowWrapBreakWord
+	OverflowWrapAnywhere
+)
+
+var overflowWraps = []string{"normal", "break-word", "anywhere"}
+
+func (w OverflowWrap) String() string {
+	return enumString(overflowWraps, int(w))
+}
+
+// OverflowMarker is how text-overflow renders one side of overflowing
+// text: the keyword "clip" or "ellipsis", or a custom string in Text.
+type OverflowMarker struct {
+	Keyword string
+	Text    string
+}
+
+func (m OverflowMarker) String() string {
+	if m.Keyword != "" {
+		return m.Keyword
+	}
+	return strconv.Quote(m.Text)
+}
+
+// TextOverflow is the typed value of the text-overflow property.
+type TextOverflow struct {
+	Start, End OverflowMarker
+}
+
+func (t TextOverflow) String() string {
+	if t.Start.Keyword == "clip" {
+		return t.End.String()
+	}
+	return t.Start.String() + " " + t.End.String()
+}
+
+func overflowMarkerOf(v ComponentValue) OverflowMarker {
+	if v.Type == StringValue {
+		return OverflowMarker{Text: v.Text}
+	}
+	return OverflowMarker{Keyword: strings.ToLower(v.Text)}
+}
+
+func textAlign(values []ComponentValue) interface{} {
+	return TextAlign(enumIndex(textAligns, values[0]))
+}
+
+func textIndent(values []ComponentValue) interface{} {
+	var indent TextIndent
+	for _, v := range values {
+		switch {
+		case v.Is("hanging"):
+			indent.Hanging = true
+		case v.Is("each-line"):
+			indent.EachLine = true
+		default:
+			indent.Length = lengthOf(v)
+		}
+	}
+	return indent
+}
+
+func textTransform(values []ComponentValue) interface{} {
+	var t TextTransform
+	for _, v := range values {
+		switch {
+		case v.Is("full-width"):
+			t.FullWidth = true
+		case v.Is("full-size-kana"):
+			t.FullSizeKana = true
+		default:
+			t.Case = strings.ToLower(v.Text)
+		}
+	}
+	return t
+}
+
+func whiteSpace(values []ComponentValue) interface{} {
+	return WhiteSpace(enumIndex(whiteSpaces, values[0]))
+}
+
+func wordBreak(values []ComponentValue) interface{} {
+	return WordBreak(enumIndex(wordBreaks, values[0]))
+}
+
+func overflowWrap(values []ComponentValue) interface{} {
+	return OverflowWrap(enumIndex(overflowWraps, values[0]))
+}
+
+func textOverflow(values []ComponentValue) interface{} {
+	if len(values) == 1 {
+		return TextOverflow{Start: OverflowMarker{Keyword: "clip"}, End: overflowMarkerOf(values[0])}
+	}
+	return TextOverflow{Start: overflowMarkerOf(values[0]), End: overflowMarkerOf(values[1])}
+}
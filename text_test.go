@@ -0,0 +1,79 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextProperties(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"text-align", "start", TextAlignStart},
+		{"text-align", "match-parent", TextAlignMatchParent},
+		{"text-align", "JUSTIFY-ALL", TextAlignJustifyAll},
+		{"text-indent", "2em", TextIndent{Length: Length{Value: 2, Unit: "em"}}},
+		{"text-indent", "each-line 10% hanging", TextIndent{Length: percent(10), Hanging: true, EachLine: true}},
+		{"text-transform", "uppercase full-width", TextTransform{Case: "uppercase", FullWidth: true}},
+		{"text-transform", "full-size-kana", TextTransform{FullSizeKana: true}},
+		{"letter-spacing", "normal", Length{Keyword: "normal"}},
+		{"letter-spacing", "-0.05em", Length{Value: -0.05, Unit: "em"}},
+		{"word-spacing", "10%", percent(10)},
+		{"line-height", "1.5", Length{Value: 1.5}},
+		{"line-height", "normal", Length{Keyword: "normal"}},
+		{"line-height", "120%", percent(120)},
+		{"line-height", "20px", Length{Value: 20, Unit: "px"}},
+		{"vertical-align", "text-top", Length{Keyword: "text-top"}},
+		{"vertical-align", "-2px", Length{Value: -2, Unit: "px"}},
+		{"white-space", "pre-wrap", WhiteSpacePreWrap},
+		{"word-break", "keep-all", WordBreakKeepAll},
+		{"overflow-wrap", "anywhere", OverflowWrapAnywhere},
+		{"text-overflow", "ellipsis", TextOverflow{Start: OverflowMarker{Keyword: "clip"}, End: OverflowMarker{Keyword: "ellipsis"}}},
+		{"text-overflow", `"…" clip`, TextOverflow{Start: OverflowMarker{Text: "…"}, End: OverflowMarker{Keyword: "clip"}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for name, value := range map[string]string{
+		"text-align":     "middle",
+		"text-indent":    "hanging",
+		"text-transform": "uppercase lowercase",
+		"line-height":    "-1",
+		"letter-spacing": "10%",
+		"vertical-align": "auto",
+		"white-space":    "wrap",
+		"word-break":     "anywhere",
+		"overflow-wrap":  "break-all",
+		"text-overflow":  "clip clip clip",
+	} {
+		_, err := CSSStyle(name, map[string]string{name: value})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTextValues(t *testing.T) {
+	assert.Equal(t, TextAlignLeft, TextAlignStart.Physical(false))
+	assert.Equal(t, TextAlignLeft, TextAlignEnd.Physical(true))
+	assert.Equal(t, TextAlignRight, TextAlignStart.Physical(true))
+	assert.Equal(t, TextAlignCenter, TextAlignCenter.Physical(true))
+
+	assert.True(t, WhiteSpacePreLine.CollapsesSpaces())
+	assert.False(t, WhiteSpaceBreakSpaces.CollapsesSpaces())
+	assert.False(t, WhiteSpaceNowrap.Wraps())
+
+	assert.Equal(t, "1em hanging", TextIndent{Length: Length{Value: 1, Unit: "em"}, Hanging: true}.String())
+	assert.Equal(t, "capitalize full-size-kana", TextTransform{Case: "capitalize", FullSizeKana: true}.String())
+	assert.Equal(t, `ellipsis "-"`, TextOverflow{Start: OverflowMarker{Keyword: "ellipsis"}, End: OverflowMarker{Text: "-"}}.String())
+	assert.Equal(t, "ellipsis", TextOverflow{Start: OverflowMarker{Keyword: "clip"}, End: OverflowMarker{Keyword: "ellipsis"}}.String())
+}
@@ -0,0 +1,537 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TransformFunction is a transform function of the transform property,
+// with the defaults of omitted arguments filled in.
+type TransformFunction struct {
+	// Name is the function name like "translateX".
+	Name string
+	// Lengths are the arguments of the translate functions and of
+	// perspective(), which has none for perspective(none).
+	Lengths []Length
+	// Numbers are the arguments of matrix(), matrix3d() and the scale
+	// functions, with percentages as numbers, and the axis of rotate3d().
+	Numbers []float64
+	// Angles are the arguments of the rotate and skew functions in degrees.
+	Angles []float64
+}
+
+// transformFunctionNames are the names of the transform functions by their
+// lowercase names.
+var transformFunctionNames = map[string]string{}
+
+func init() {
+	for _, name := range []string{
+		"matrix", "matrix3d", "perspective",
+		"rotate", "rotate3d", "rotateX", "rotateY", "rotateZ",
+		"scale", "scale3d", "scaleX", "scaleY", "scaleZ",
+		"skew", "skewX", "skewY",
+		"translate", "translate3d", "translateX", "translateY", "translateZ",
+	} {
+		transformFunctionNames[strings.ToLower(name)] = name
+	}
+}
+
+func (f TransformFunction) String() string {
+	var args []string
+	for _, l := range f.Lengths {
+		args = append(args, l.String())
+	}
+	for _, n := range f.Numbers {
+		args = append(args, formatNumber(n))
+	}
+	for _, a := range f.Angles {
+		args = append(args, formatNumber(a)+"deg")
+	}
+	if f.Name == "perspective" && len(args) == 0 {
+		args = []string{"none"}
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Transform is the typed value of the transform property. none is an empty
+// list of functions.
+type Transform struct {
+	Functions []TransformFunction
+}
+
+func (t Transform) String() string {
+	if len(t.Functions) == 0 {
+		return "none"
+	}
+	functions := make([]string, len(t.Functions))
+	for i, f := range t.Functions {
+		functions[i] = f.String()
+	}
+	return strings.Join(functions, " ")
+}
+
+func transform(values []ComponentValue) interface{} {
+	var t Transform
+	for _, v := range values {
+		if v.Type == FunctionValue {
+			t.Functions = append(t.Functions, transformFunctionOf(v))
+		}
+	}
+	return t
+}
+
+func transformFunctionOf(v ComponentValue) TransformFunction {
+	f := TransformFunction{Name: transformFunctionNames[v.Text]}
+	var args []ComponentValue
+	for _, group := range splitCommas(v.Args) {
+		args = append(args, group...)
+	}
+	switch f.Name {
+	case "translate", "translate3d", "translateX", "translateY", "translateZ":
+		for _, arg := range args {
+			f.Lengths = append(f.Lengths, lengthOf(arg))
+		}
+		if f.Name == "translate" && len(f.Lengths) == 1 {
+			f.Lengths = append(f.Lengths, Length{})
+		}
+	case "perspective":
+		if !args[0].Is("none") {
+			f.Lengths = []Length{lengthOf(args[0])}
+		}
+	case "rotate", "rotateX", "rotateY", "rotateZ", "skewX", "skewY":
+		f.Angles = []float64{angleOf(args[0])}
+	case "skew":
+		f.Angles = []float64{angleOf(args[0]), 0}
+		if len(args) == 2 {
+			f.Angles[1] = angleOf(args[1])
+		}
+	case "rotate3d":
+		for _, arg := range args[:3] {
+			f.Numbers = append(f.Numbers, scaleOf(arg))
+		}
+		f.Angles = []float64{angleOf(args[3])}
+	default:
+		for _, arg := range args {
+			f.Numbers = append(f.Numbers, scaleOf(arg))
+		}
+		if f.Name == "scale" && len(f.Numbers) == 1 {
+			f.Numbers = append(f.Numbers, f.Numbers[0])
+		}
+	}
+	return f
+}
+
+// angleOf returns an <angle> in degrees.
+func angleOf(v ComponentValue) float64 {
+	if v.Type == FunctionValue {
+		deg, _ := evalMath(v, func(v ComponentValue) (float64, bool) {
+			return angleDegrees(v), v.Type == DimensionValue
+		})
+		return deg
+	}
+	return angleDegrees(v)
+}
+
+// scaleOf returns a <number> or <percentage> as a number.
+func scaleOf(v ComponentValue) float64 {
+	switch v.Type {
+	case PercentageValue:
+		return v.Number / 100
+	case FunctionValue:
+		n, _ := evalMath(v, func(v ComponentValue) (float64, bool) {
+			return v.Number / 100, v.Type == PercentageValue
+		})
+		return n
+	}
+	return v.Number
+}
+
+// TransformOrigin is the typed value of the transform-origin property,
+// with the horizontal and vertical offsets from the top left corner of the
+// reference box.
+type TransformOrigin struct {
+	X, Y, Z Length
+}
+
+func (o TransformOrigin) String() string {
+	return o.X.String() + " " + o.Y.String() + " " + o.Z.String()
+}
+
+// originKeywords are the offsets of the keywords of transform-origin.
+var originKeywords = map[string]float64{"left": 0, "top": 0, "center": 50, "right": 100, "bottom": 100}
+
+func transformOrigin(values []ComponentValue) interface{} {
+	o := TransformOrigin{X: percent(50), Y: percent(50)}
+	if len(values) == 3 {
+		o.Z = lengthOf(values[2])
+		values = values[:2]
+	}
+	if len(values) == 2 && (isVerticalEdge(values[0]) || values[1].Is("left") || values[1].Is("right")) {
+		values = []ComponentValue{values[1], values[0]}
+	}
+	offsets := make([]Length, len(values))
+	for i, v := range values {
+		offsets[i] = lengthOf(v)
+		if v.Type == IdentValue {
+			offsets[i] = percent(originKeywords[strings.ToLower(v.Text)])
+		}
+	}
+	switch {
+	case len(values) == 2:
+		o.X, o.Y = offsets[0], offsets[1]
+	case isVerticalEdge(values[0]):
+		o.Y = offsets[0]
+	default:
+		o.X = offsets[0]
+	}
+	return o
+}
+
+// Translate is the typed value of the translate property.
+type Translate struct {
+	None    bool
+	X, Y, Z Length
+}
+
+func (t Translate) String() string {
+	switch {
+	case t.None:
+		return "none"
+	case t.Z != Length{}:
+		return t.X.String() + " " + t.Y.String() + " " + t.Z.String()
+	case t.Y != Length{}:
+		return t.X.String() + " " + t.Y.String()
+	}
+	return t.X.String()
+}
+
+func translateValue(values []ComponentValue) interface{} {
+	if values[0].Is("none") {
+		return Translate{None: true}
+	}
+	var t Translate
+	for i, l := range []*Length{&t.X, &t.Y, &t.Z}[:len(values)] {
+		*l = lengthOf(values[i])
+	}
+	return t
+}
+
+// Rotate is the typed value of the rotate property, a rotation by Angle
+// degrees around the axis X, Y, Z.
+type Rotate struct {
+	None    bool
+	X, Y, Z float64
+	Angle   float64
+}
+
+func (r Rotate) String() string {
+	angle := formatNumber(r.Angle) + "deg"
+	switch {
+	case r.None:
+		return "none"
+	case r.X == 0 && r.Y == 0 && r.Z == 1:
+		return angle
+	case r.X == 1 && r.Y == 0 && r.Z == 0:
+		return "x " + angle
+	case r.X == 0 && r.Y == 1 && r.Z == 0:
+		return "y " + angle
+	}
+	return fmt.Sprintf("%s %s %s %s", formatNumber(r.X), formatNumber(r.Y), formatNumber(r.Z), angle)
+}
+
+func rotateValue(values []ComponentValue) interface{} {
+	if values[0].Is("none") {
+		return Rotate{None: true}
+	}
+	r := Rotate{Z: 1}
+	var axis []float64
+	for _, v := range values {
+		switch {
+		case v.Is("x"):
+			r.Z, r.X = 0, 1
+		case v.Is("y"):
+			r.Z, r.Y = 0, 1
+		case v.Type == NumberValue || v.Type == FunctionValue && mathType(v) == "number":
+			axis = append(axis, scaleOf(v))
+		case !v.Is("z"):
+			r.Angle = angleOf(v)
+		}
+	}
+	if len(axis) == 3 {
+		r.X, r.Y, r.Z = axis[0], axis[1], axis[2]
+	}
+	return r
+}
+
+// Scale is the typed value of the scale property.
+type Scale struct {
+	None    bool
+	X, Y, Z float64
+}
+
+func (s Scale) String() string {
+	switch {
+	case s.None:
+		return "none"
+	case s.Z != 1:
+		return formatNumber(s.X) + " " + formatNumber(s.Y) + " " + formatNumber(s.Z)
+	case s.Y != s.X:
+		return formatNumber(s.X) + " " + formatNumber(s.Y)
+	}
+	return formatNumber(s.X)
+}
+
+func scaleValue(values []ComponentValue) interface{} {
+	if values[0].Is("none") {
+		return Scale{None: true}
+	}
+	s := Scale{Z: 1}
+	s.X = scaleOf(values[0])
+	s.Y = s.X
+	if len(values) > 1 {
+		s.Y = scaleOf(values[1])
+	}
+	if len(values) > 2 {
+		s.Z = scaleOf(values[2])
+	}
+	return s
+}
+
+// Matrix is a 4x4 transformation matrix in column-major order, like the
+// arguments of matrix3d(). The 2D matrix(a, b, c, d, e, f) is
+// Matrix{a, b, 0, 0, c, d, 0, 0, 0, 0, 1, 0, e, f, 0, 1}.
+type Matrix [16]float64
+
+// IdentityMatrix returns the matrix of no transform.
+func IdentityMatrix() Matrix {
+	return Matrix{0: 1, 5: 1, 10: 1, 15: 1}
+}
+
+func translation(x, y, z float64) Matrix {
+	m := IdentityMatrix()
+	m[12], m[13], m[14] = x, y, z
+	return m
+}
+
+// Multiply returns m × n, the transform that applies n and then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	var p Matrix
+	for column := 0; column < 4; column++ {
+		for row := 0; row < 4; row++ {
+			for k := 0; k < 4; k++ {
+				p[column*4+row] += m[k*4+row] * n[column*4+k]
+			}
+		}
+	}
+	return p
+}
+
+// Is2D reports whether m is a 2D transform that matrix() can represent.
+func (m Matrix) Is2D() bool {
+	for _, i := range []int{2, 3, 6, 7, 8, 9, 11, 14} {
+		if m[i] != 0 {
+			return false
+		}
+	}
+	return m[10] == 1 && m[15] == 1
+}
+
+// Affine returns the 2D components a, b, c, d, e and f of m, which map
+// (x, y) to (a*x + c*y + e, b*x + d*y + f).
+func (m Matrix) Affine() [6]float64 {
+	return [6]float64{m[0], m[1], m[4], m[5], m[12], m[13]}
+}
+
+// Apply transforms the point (x, y, z), including the perspective divide.
+func (m Matrix) Apply(x, y, z float64) (float64, float64, float64) {
+	w := m[3]*x + m[7]*y + m[11]*z + m[15]
+	return (m[0]*x + m[4]*y + m[8]*z + m[12]) / w,
+		(m[1]*x + m[5]*y + m[9]*z + m[13]) / w,
+		(m[2]*x + m[6]*y + m[10]*z + m[14]) / w
+}
+
+func (m Matrix) String() string {
+	var args []string
+	if m.Is2D() {
+		for _, n := range m.Affine() {
+			args = append(args, formatNumber(n))
+		}
+		return "matrix(" + strings.Join(args, ", ") + ")"
+	}
+	for _, n := range m {
+		args = append(args, formatNumber(n))
+	}
+	return "matrix3d(" + strings.Join(args, ", ") + ")"
+}
+
+// rotation returns the matrix of a rotation by angle degrees around the
+// axis x, y, z, or the identity matrix if the axis has no direction.
+func rotation(x, y, z, angle float64) Matrix {
+	length := math.Sqrt(x*x + y*y + z*z)
+	if length == 0 {
+		return IdentityMatrix()
+	}
+	x, y, z = x/length, y/length, z/length
+	sin, cos := sinCos(angle)
+	sc, sq := sin/2, (1-cos)/2
+	return Matrix{
+		1 - 2*(y*y+z*z)*sq, 2 * (x*y*sq + z*sc), 2 * (x*z*sq - y*sc), 0,
+		2 * (x*y*sq - z*sc), 1 - 2*(x*x+z*z)*sq, 2 * (y*z*sq + x*sc), 0,
+		2 * (x*z*sq + y*sc), 2 * (y*z*sq - x*sc), 1 - 2*(x*x+y*y)*sq, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// sinCos returns the sine and cosine of angle degrees, exactly for
+// multiples of 90 degrees.
+func sinCos(angle float64) (sin, cos float64) {
+	if quarter := angle / 90; quarter == math.Trunc(quarter) {
+		switch int(math.Mod(quarter, 4)+4) % 4 {
+		case 0:
+			return 0, 1
+		case 1:
+			return 1, 0
+		case 2:
+			return 0, -1
+		}
+		return -1, 0
+	}
+	return math.Sincos(angle * math.Pi / 180)
+}
+
+// Matrix returns the matrix of f. Percentages of translations are relative
+// to the size of the reference box, and font relative lengths to fontSize.
+func (f TransformFunction) Matrix(reference Size, fontSize float64) (Matrix, error) {
+	lengths := make([]float64, len(f.Lengths))
+	for i, l := range f.Lengths {
+		base := reference.Width
+		if i == 1 || f.Name == "translateY" {
+			base = reference.Height
+		}
+		px, ok := l.Pixels(base, fontSize)
+		if !ok {
+			return Matrix{}, fmt.Errorf("%s: can't resolve %s to pixels", f.Name, l)
+		}
+		lengths[i] = px
+	}
+
+	m := IdentityMatrix()
+	switch f.Name {
+	case "matrix":
+		n := f.Numbers
+		m[0], m[1], m[4], m[5], m[12], m[13] = n[0], n[1], n[2], n[3], n[4], n[5]
+	case "matrix3d":
+		copy(m[:], f.Numbers)
+	case "translate", "translate3d":
+		copy(m[12:15], lengths)
+	case "translateX":
+		m[12] = lengths[0]
+	case "translateY":
+		m[13] = lengths[0]
+	case "translateZ":
+		m[14] = lengths[0]
+	case "scale", "scale3d":
+		m[0], m[5] = f.Numbers[0], f.Numbers[1]
+		if len(f.Numbers) == 3 {
+			m[10] = f.Numbers[2]
+		}
+	case "scaleX":
+		m[0] = f.Numbers[0]
+	case "scaleY":
+		m[5] = f.Numbers[0]
+	case "scaleZ":
+		m[10] = f.Numbers[0]
+	case "rotate", "rotateZ":
+		return rotation(0, 0, 1, f.Angles[0]), nil
+	case "rotateX":
+		return rotation(1, 0, 0, f.Angles[0]), nil
+	case "rotateY":
+		return rotation(0, 1, 0, f.Angles[0]), nil
+	case "rotate3d":
+		return rotation(f.Numbers[0], f.Numbers[1], f.Numbers[2], f.Angles[0]), nil
+	case "skew":
+		m[4], m[1] = math.Tan(f.Angles[0]*math.Pi/180), math.Tan(f.Angles[1]*math.Pi/180)
+	case "skewX":
+		m[4] = math.Tan(f.Angles[0] * math.Pi / 180)
+	case "skewY":
+		m[1] = math.Tan(f.Angles[0] * math.Pi / 180)
+	case "perspective":
+		// perspective(none) is the identity, and smaller depths are
+		// clamped to 1px
+		if len(lengths) == 1 {
+			m[11] = -1 / math.Max(lengths[0], 1)
+		}
+	}
+	return m, nil
+}
+
+// Matrix returns the product of the matrices of the functions of t, from
+// left to right.
+func (t Transform) Matrix(reference Size, fontSize float64) (Matrix, error) {
+	m := IdentityMatrix()
+	for _, f := range t.Functions {
+		fm, err := f.Matrix(reference, fontSize)
+		if err != nil {
+			return Matrix{}, err
+		}
+		m = m.Multiply(fm)
+	}
+	return m, nil
+}
+
+// TransformMatrix returns the transformation matrix of a box with the
+// computed style, whose border box is box. It maps points in the coordinate
+// system of box to where they are drawn, applying the translate, rotate,
+// scale and transform properties in that order around the transform-origin.
+// The perspective property applies to the children of the box and isn't
+// part of the matrix.
+func TransformMatrix(style map[string]string, box Rect) (Matrix, error) {
+	b, err := newLayoutBox(style, box.Width)
+	if err != nil {
+		return Matrix{}, err
+	}
+	reference := Size{box.Width, box.Height}
+	origin := TransformOrigin{X: percent(50), Y: percent(50)}
+	if o, err := b.value("transform-origin"); err != nil {
+		return Matrix{}, err
+	} else if o, ok := o.(TransformOrigin); ok {
+		origin = o
+	}
+	var offsets [3]float64
+	for i, l := range []Length{origin.X, origin.Y, origin.Z} {
+		var ok bool
+		if offsets[i], ok = l.Pixels([]float64{box.Width, box.Height, 0}[i], b.fontSize); !ok {
+			return Matrix{}, fmt.Errorf("transform-origin: can't resolve %s to pixels", l)
+		}
+	}
+	x, y, z := box.X+offsets[0], box.Y+offsets[1], offsets[2]
+
+	var functions []TransformFunction
+	if t, err := b.value("translate"); err != nil {
+		return Matrix{}, err
+	} else if t, ok := t.(Translate); ok && !t.None {
+		functions = append(functions, TransformFunction{Name: "translate3d", Lengths: []Length{t.X, t.Y, t.Z}})
+	}
+	if r, err := b.value("rotate"); err != nil {
+		return Matrix{}, err
+	} else if r, ok := r.(Rotate); ok && !r.None {
+		functions = append(functions, TransformFunction{Name: "rotate3d", Numbers: []float64{r.X, r.Y, r.Z}, Angles: []float64{r.Angle}})
+	}
+	if s, err := b.value("scale"); err != nil {
+		return Matrix{}, err
+	} else if s, ok := s.(Scale); ok && !s.None {
+		functions = append(functions, TransformFunction{Name: "scale3d", Numbers: []float64{s.X, s.Y, s.Z}})
+	}
+	if t, err := b.value("transform"); err != nil {
+		return Matrix{}, err
+	} else if t, ok := t.(Transform); ok {
+		functions = append(functions, t.Functions...)
+	}
+
+	m, err := Transform{functions}.Matrix(reference, b.fontSize)
+	if err != nil {
+		return Matrix{}, err
+	}
+	return translation(x, y, z).Multiply(m).Multiply(translation(-x, -y, -z)), nil
+}
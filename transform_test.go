@@ -0,0 +1,154 @@
+package css
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	px := func(n float64) Length { return Length{Value: n, Unit: "px"} }
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"transform", "none", Transform{}},
+		{"transform", "translateX(10px) rotate(0.25turn) scale(2, 50%)", Transform{[]TransformFunction{
+			{Name: "translateX", Lengths: []Length{px(10)}},
+			{Name: "rotate", Angles: []float64{90}},
+			{Name: "scale", Numbers: []float64{2, 0.5}},
+		}}},
+		{"transform", "translate(5%) scale(2) skew(100grad) rotate(0)", Transform{[]TransformFunction{
+			{Name: "translate", Lengths: []Length{percent(5), {}}},
+			{Name: "scale", Numbers: []float64{2, 2}},
+			{Name: "skew", Angles: []float64{90, 0}},
+			{Name: "rotate", Angles: []float64{0}},
+		}}},
+		{"transform", "Matrix(1, 0, 0, 1, 10, 20) perspective(none) rotate3d(1, 1, 0, calc(45deg * 2))", Transform{[]TransformFunction{
+			{Name: "matrix", Numbers: []float64{1, 0, 0, 1, 10, 20}},
+			{Name: "perspective"},
+			{Name: "rotate3d", Numbers: []float64{1, 1, 0}, Angles: []float64{90}},
+		}}},
+		{"transform", "translate3d(10%, 1em, 5px) scaleZ(3) rotateX(1rad) skewY(-10deg)", Transform{[]TransformFunction{
+			{Name: "translate3d", Lengths: []Length{percent(10), {Value: 1, Unit: "em"}, px(5)}},
+			{Name: "scaleZ", Numbers: []float64{3}},
+			{Name: "rotateX", Angles: []float64{180 / math.Pi}},
+			{Name: "skewY", Angles: []float64{-10}},
+		}}},
+		{"transform-origin", "left", TransformOrigin{X: percent(0), Y: percent(50)}},
+		{"transform-origin", "top", TransformOrigin{X: percent(50), Y: percent(0)}},
+		{"transform-origin", "10px 20%", TransformOrigin{X: px(10), Y: percent(20)}},
+		{"transform-origin", "bottom right 10px", TransformOrigin{X: percent(100), Y: percent(100), Z: px(10)}},
+		{"transform-origin", "center left", TransformOrigin{X: percent(0), Y: percent(50)}},
+		{"translate", "none", Translate{None: true}},
+		{"translate", "10px 20% 1em", Translate{X: px(10), Y: percent(20), Z: Length{Value: 1, Unit: "em"}}},
+		{"rotate", "45deg", Rotate{Z: 1, Angle: 45}},
+		{"rotate", "45deg y", Rotate{Y: 1, Angle: 45}},
+		{"rotate", "1 1 0 -90deg", Rotate{X: 1, Y: 1, Angle: -90}},
+		{"scale", "2", Scale{X: 2, Y: 2, Z: 1}},
+		{"scale", "2 50% 3", Scale{X: 2, Y: 0.5, Z: 3}},
+		{"perspective", "100px", px(100)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for _, tt := range [][2]string{
+		{"transform", "rotate(10px)"},
+		{"transform", "translate(1px,)"},
+		{"transform", "translateZ(10%)"},
+		{"transform", "none rotate(1deg)"},
+		{"transform", "matrix(1, 2, 3)"},
+		{"transform", "perspective(-1px)"},
+		{"transform-origin", "left right"},
+		{"translate", "10px 10px 10%"},
+		{"rotate", "x y 1deg"},
+		{"scale", "1 2 3 4"},
+		{"perspective", "-1px"},
+	} {
+		_, err := CSSStyle(tt[0], map[string]string{tt[0]: tt[1]})
+		assert.Error(t, err, tt[1])
+	}
+
+	transform := Transform{[]TransformFunction{
+		{Name: "translate", Lengths: []Length{px(1), {}}},
+		{Name: "perspective"},
+		{Name: "rotate3d", Numbers: []float64{1, 0, 0}, Angles: []float64{90}},
+	}}
+	assert.Equal(t, "translate(1px, 0) perspective(none) rotate3d(1, 0, 0, 90deg)", transform.String())
+	assert.Equal(t, "none", Transform{}.String())
+	assert.Equal(t, "10px 20%", Translate{X: px(10), Y: percent(20)}.String())
+	assert.Equal(t, "x 45deg", Rotate{X: 1, Angle: 45}.String())
+	assert.Equal(t, "2", Scale{X: 2, Y: 2, Z: 1}.String())
+	assert.Equal(t, "50% 0% 0", TransformOrigin{X: percent(50), Y: percent(0)}.String())
+}
+
+func TestTransformMatrix(t *testing.T) {
+	cases := []struct {
+		name     string
+		style    map[string]string
+		box      Rect
+		expected Matrix
+	}{
+		{"none", nil, Rect{0, 0, 100, 50}, IdentityMatrix()},
+		{
+			"rotate around the center",
+			map[string]string{"transform": "rotate(90deg)"},
+			Rect{0, 0, 100, 50},
+			Matrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 75, -25, 0, 1},
+		},
+		{
+			"individual transforms before transform",
+			map[string]string{"transform": "scale(2)", "transform-origin": "0 0", "translate": "10px"},
+			Rect{10, 10, 100, 50},
+			Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1, 0, 0, -10, 0, 1},
+		},
+		{
+			"percentages and font relative lengths",
+			map[string]string{"font-size": "10px", "transform": "translate(50%, 2em) skewX(45deg)", "transform-origin": "left top"},
+			Rect{0, 0, 100, 50},
+			Matrix{1, 0, 0, 0, 1, 1, 0, 0, 0, 0, 1, 0, 50, 20, 0, 1},
+		},
+		{
+			"matrix3d and perspective",
+			map[string]string{"transform": "perspective(100px) matrix3d(1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 10, 1)", "transform-origin": "0 0"},
+			Rect{0, 0, 100, 50},
+			Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, -0.01, 0, 0, 10, 0.9},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := TransformMatrix(tt.style, tt.box)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.InDeltaSlice(t, tt.expected[:], m[:], 1e-9)
+		})
+	}
+
+	_, err := TransformMatrix(map[string]string{"transform": "translate(10vw)"}, Rect{})
+	assert.Error(t, err)
+
+	m := IdentityMatrix().Multiply(Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 3, 4, 0, 1})
+	assert.True(t, m.Is2D())
+	assert.Equal(t, [6]float64{1, 0, 0, 1, 3, 4}, m.Affine())
+	assert.Equal(t, "matrix(1, 0, 0, 1, 3, 4)", m.String())
+	x, y, z := m.Apply(1, 1, 0)
+	assert.Equal(t, []float64{4, 5, 0}, []float64{x, y, z})
+
+	rotateY, err := TransformFunction{Name: "rotateY", Angles: []float64{90}}.Matrix(Size{}, 16)
+	assert.NoError(t, err)
+	assert.False(t, rotateY.Is2D())
+	x, y, z = rotateY.Apply(1, 0, 0)
+	assert.InDeltaSlice(t, []float64{0, 0, -1}, []float64{x, y, z}, 1e-9)
+}
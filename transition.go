@@ -0,0 +1,206 @@
+package css
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transition is one transition of the transition properties, with the
+// defaults of omitted values filled in.
+type Transition struct {
+	// Property is the lower case name of the transitioned property, "all"
+	// or "none".
+	Property string
+	Duration time.Duration
+	Easing   EasingFunction
+	Delay    time.Duration
+	// Behavior is "normal" or "allow-discrete", which lets properties
+	// with a discrete animation type transition too.
+	Behavior string
+}
+
+func initialTransition() Transition {
+	return Transition{Property: "all", Easing: easingKeywords["ease"], Behavior: "normal"}
+}
+
+func (t Transition) String() string {
+	return strings.Join([]string{
+		t.Property, formatDuration(t.Duration), t.Easing.String(), formatDuration(t.Delay), t.Behavior,
+	}, " ")
+}
+
+func transition(values []ComponentValue) interface{} {
+	var transitions []Transition
+	for _, layer := range splitCommas(values) {
+		productionGrammars["single-transition"].MatchValues(layer)
+		t := initialTransition()
+		duration := false
+		for _, v := range layer {
+			switch v.Production {
+			case "easing-function":
+				t.Easing = easingOf(v)
+			case "transition-behavior-value":
+				t.Behavior = strings.ToLower(v.Text)
+			case "single-transition-property":
+				t.Property = strings.ToLower(v.Text)
+			default:
+				if v.Is("none") {
+					t.Property = "none"
+					continue
+				}
+				// the first time is the duration, unless it is negative
+				// and can only be the delay
+				if d := timeOf(v); !duration && d >= 0 {
+					t.Duration, duration = d, true
+				} else {
+					t.Delay = d
+				}
+			}
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions
+}
+
+func expandTransition(values []ComponentValue) map[string]string {
+	lists := make(map[string][]string)
+	for _, t := range transition(values).([]Transition) {
+		lists["transition-property"] = append(lists["transition-property"], t.Property)
+		lists["transition-duration"] = append(lists["transition-duration"], formatDuration(t.Duration))
+		lists["transition-timing-function"] = append(lists["transition-timing-function"], t.Easing.String())
+		lists["transition-delay"] = append(lists["transition-delay"], formatDuration(t.Delay))
+		lists["transition-behavior"] = append(lists["transition-behavior"], t.Behavior)
+	}
+	longhands := make(map[string]string, len(lists))
+	for name, list := range lists {
+		longhands[name] = strings.Join(list, ", ")
+	}
+	return longhands
+}
+
+// validateTransition checks that none is the only item of a list of
+// transitions or transitioned properties, and the easing functions of the
+// transitions.
+func validateTransition(values []ComponentValue) error {
+	if layers := splitCommas(values); len(layers) > 1 {
+		for _, layer := range layers {
+			for _, v := range layer {
+				if v.Is("none") {
+					return fmt.Errorf("none must be the only transition")
+				}
+			}
+		}
+	}
+	return validateEasing(values)
+}
+
+func transitionProperties(values []ComponentValue) interface{} {
+	var properties []string
+	for _, layer := range splitCommas(values) {
+		properties = append(properties, strings.ToLower(layer[0].Text))
+	}
+	return properties
+}
+
+// Transitions returns the transitions of the computed style, one for each
+// property of transition-property, or nil for none. The lists of the other
+// transition properties are repeated or truncated to the length of
+// transition-property.
+func Transitions(style map[string]string) ([]Transition, error) {
+	style, err := expandStyle(style)
+	if err != nil {
+		return nil, err
+	}
+	b := &layoutBox{style: style}
+	v, err := b.value("transition-property")
+	if err != nil {
+		return nil, err
+	}
+	properties, ok := v.([]string)
+	if !ok {
+		// the initial value is all
+		properties = []string{"all"}
+	}
+	if properties[0] == "none" {
+		return nil, nil
+	}
+	transitions := make([]Transition, len(properties))
+	for i, property := range properties {
+		transitions[i] = initialTransition()
+		transitions[i].Property = property
+	}
+
+	for _, name := range []string{"transition-duration", "transition-timing-function", "transition-delay", "transition-behavior"} {
+		v, err := b.value(name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range transitions {
+			t := &transitions[i]
+			switch list := v.(type) {
+			case []time.Duration:
+				if name == "transition-duration" {
+					t.Duration = list[i%len(list)]
+				} else {
+					t.Delay = list[i%len(list)]
+				}
+			case []EasingFunction:
+				t.Easing = list[i%len(list)]
+			case []string:
+				t.Behavior = list[i%len(list)]
+			}
+		}
+	}
+	return transitions, nil
+}
+
+// TransitionFor returns the transition of the longhand property name, the
+// last of transitions that names it, one of its shorthands or all. It
+// returns false if the property doesn't transition: if no transition
+// matches, if its combined duration is zero, or if the property isn't
+// animatable or discrete without allow-discrete.
+func TransitionFor(transitions []Transition, name string) (Transition, bool) {
+	name = strings.ToLower(name)
+	m, ok := DefaultRegistry.Metadata(name)
+	if !ok || m.IsShorthand() || m.AnimationType == AnimationNotAnimatable {
+		return Transition{}, false
+	}
+	for i := len(transitions) - 1; i >= 0; i-- {
+		t := transitions[i]
+		if !t.matches(name, m) {
+			continue
+		}
+		if t.Duration+t.Delay <= 0 || m.AnimationType == AnimationDiscrete && t.Behavior != "allow-discrete" {
+			return Transition{}, false
+		}
+		return t, true
+	}
+	return Transition{}, false
+}
+
+func (t Transition) matches(name string, m PropertyMetadata) bool {
+	if t.Property == "all" || t.Property == name {
+		return true
+	}
+	for _, shorthand := range m.Shorthands {
+		if t.Property == shorthand {
+			return true
+		}
+	}
+	return false
+}
+
+// Progress returns the eased progress of t at the time elapsed since the
+// transition started, which can be outside of [0,1] for easing functions
+// that overshoot. It is 0 before the delay and 1 after the end.
+func (t Transition) Progress(elapsed time.Duration) float64 {
+	elapsed -= t.Delay
+	switch {
+	case elapsed < 0:
+		return 0
+	case elapsed >= t.Duration:
+		return 1
+	}
+	return t.Easing.Eval(elapsed.Seconds() / t.Duration.Seconds())
+}
@@ -0,0 +1,142 @@
+package css
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionProperties(t *testing.T) {
+	ease := easingKeywords["ease"]
+	cases := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"transition-property", "none", []string{"none"}},
+		{"transition-property", "Width, all, margin", []string{"width", "all", "margin"}},
+		{"transition-duration", "1s, 250ms", []time.Duration{time.Second, 250 * time.Millisecond}},
+		{"transition-delay", "-1s", []time.Duration{-time.Second}},
+		{"transition-timing-function", "ease, linear", []EasingFunction{ease, {Name: "linear"}}},
+		{"transition-behavior", "allow-discrete, normal", []string{"allow-discrete", "normal"}},
+		{"transition", "1s", []Transition{{Property: "all", Duration: time.Second, Easing: ease, Behavior: "normal"}}},
+		{"transition", "none", []Transition{{Property: "none", Easing: ease, Behavior: "normal"}}},
+		{"transition", "-1s 2s display allow-discrete steps(2), width ease-in", []Transition{{
+			Property: "display", Duration: 2 * time.Second, Easing: EasingFunction{Name: "steps", Steps: 2, Position: "jump-end"},
+			Delay: -time.Second, Behavior: "allow-discrete",
+		}, {
+			Property: "width", Easing: easingKeywords["ease-in"], Behavior: "normal",
+		}}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			style, err := CSSStyle(tt.name, map[string]string{tt.name: tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, style.Value)
+		})
+	}
+
+	for _, tt := range [][2]string{
+		{"transition-property", "width, none"},
+		{"transition-property", "initial, width"},
+		{"transition-duration", "-1s"},
+		{"transition-behavior", "discrete"},
+		{"transition", "1s 2s 3s"},
+		{"transition", "width height"},
+		{"transition", "width 1s, none"},
+		{"transition", "width steps(1, jump-none)"},
+	} {
+		_, err := CSSStyle(tt[0], map[string]string{tt[0]: tt[1]})
+		assert.Error(t, err, tt[1])
+	}
+}
+
+func TestExpandTransition(t *testing.T) {
+	longhands, err := ExpandShorthand("transition", "width 1s ease-in, 500ms margin 1s allow-discrete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"transition-property":        "width, margin",
+		"transition-duration":        "1s, 0.5s",
+		"transition-timing-function": "ease-in, ease",
+		"transition-delay":           "0s, 1s",
+		"transition-behavior":        "normal, allow-discrete",
+	}, longhands)
+}
+
+func TestTransitions(t *testing.T) {
+	transitions, err := Transitions(map[string]string{
+		"transition":          "width 1s",
+		"transition-property": "width, height, color",
+		"transition-duration": "1s, 2s",
+		"transition-behavior": "allow-discrete, normal, normal, normal",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var properties, behaviors []string
+	var durations []time.Duration
+	for _, tr := range transitions {
+		properties = append(properties, tr.Property)
+		durations = append(durations, tr.Duration)
+		behaviors = append(behaviors, tr.Behavior)
+	}
+	assert.Equal(t, []string{"width", "height", "color"}, properties)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, time.Second}, durations)
+	assert.Equal(t, []string{"allow-discrete", "normal", "normal"}, behaviors)
+
+	transitions, err = Transitions(map[string]string{"transition-duration": "1s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Transition{{Property: "all", Duration: time.Second, Easing: easingKeywords["ease"], Behavior: "normal"}}, transitions)
+
+	transitions, err = Transitions(map[string]string{"transition": "none 1s"})
+	assert.NoError(t, err)
+	assert.Nil(t, transitions)
+}
+
+func TestTransitionFor(t *testing.T) {
+	transitions, err := Transitions(map[string]string{
+		"transition": "all 1s, margin 2s, width 0s, display 3s allow-discrete, visibility 4s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name     string
+		duration time.Duration
+		ok       bool
+	}{
+		{"height", time.Second, true},
+		{"margin-top", 2 * time.Second, true},
+		{"Margin-Left", 2 * time.Second, true},
+		{"width", 0, false},
+		{"display", 3 * time.Second, true},
+		{"visibility", 0, false},
+		{"position", 0, false},
+		{"margin", 0, false},
+		{"animation-name", 0, false},
+		{"-x-unknown", 0, false},
+	}
+	for _, tt := range cases {
+		tr, ok := TransitionFor(transitions, tt.name)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		assert.Equal(t, tt.duration, tr.Duration, tt.name)
+	}
+}
+
+func TestTransitionProgress(t *testing.T) {
+	style, err := CSSStyle("transition", map[string]string{"transition": "width 2s linear 1s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := style.Value.([]Transition)[0]
+	var progress []float64
+	for _, at := range []time.Duration{0, time.Second, 1500 * time.Millisecond, 3 * time.Second, 4 * time.Second} {
+		progress = append(progress, tr.Progress(at))
+	}
+	assert.InDeltaSlice(t, []float64{0, 0, 0.25, 1, 1}, progress, 1e-9)
+}
@@ -0,0 +1,384 @@
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ValueType is the type of a ComponentValue.
+type ValueType int
+
+const (
+	IdentValue ValueType = iota
+	NumberValue
+	PercentageValue
+	DimensionValue
+	StringValue
+	URLValue
+	HashValue
+	FunctionValue
+	// BlockValue is a parenthesized or bracketed block, its Text is "(" or "[".
+	BlockValue
+	// DelimValue is a single character like "," or "/".
+	DelimValue
+)
+
+// ComponentValue is a single token of a property value, like an identifier,
+// a dimension or a function with its arguments.
+type ComponentValue struct {
+	Type ValueType
+	// Text is the identifier, string or URL content, hash without "#",
+	// function name, block bracket or delimiter.
+	Text string
+	// Number is the value of numbers, percentages and dimensions.
+	Number float64
+	// Integer reports whether Number was written without a fraction or exponent.
+	Integer bool
+	// Unit is the lower case unit of a dimension.
+	Unit string
+	// Args holds the arguments of functions and the content of blocks.
+	Args []ComponentValue
+	// Production is the outermost named production of the value definition
+	// syntax that matched this value, e.g. "line-style" for the border
+	// property. It is set by Grammar.Match.
+	Production string
+}
+
+// Is reports whether v is the identifier ident, ignoring case.
+func (v ComponentValue) Is(ident string) bool {
+	return v.Type == IdentValue && strings.EqualFold(v.Text, ident)
+}
+
+// IsDelim reports whether v is the delimiter c.
+func (v ComponentValue) IsDelim(c string) bool {
+	return v.Type == DelimValue && v.Text == c
+}
+
+func (v ComponentValue) String() string {
+	switch v.Type {
+	case NumberValue:
+		return formatNumber(v.Number)
+	case PercentageValue:
+		return formatNumber(v.Number) + "%"
+	case DimensionValue:
+		return formatNumber(v.Number) + v.Unit
+	case StringValue:
+		return strconv.Quote(v.Text)
+	case URLValue:
+		return "url(" + strconv.Quote(v.Text) + ")"
+	case HashValue:
+		return "#" + v.Text
+	case FunctionValue:
+		return v.Text + "(" + serializeValues(v.Args) + ")"
+	case BlockValue:
+		if v.Text == "[" {
+			return "[" + serializeValues(v.Args) + "]"
+		}
+		return "(" + serializeValues(v.Args) + ")"
+	}
+	return v.Text
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// serializeValues joins values with spaces, except before commas.
+func serializeValues(values []ComponentValue) string {
+	var sb strings.Builder
+	for i, v := range values {
+		if i > 0 && !v.IsDelim(",") {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(v.String())
+	}
+	return sb.String()
+}
+
+// splitCommas splits values at the "," delimiters.
+func splitCommas(values []ComponentValue) [][]ComponentValue {
+	var groups [][]ComponentValue
+	start := 0
+	for i, v := range values {
+		if v.IsDelim(",") {
+			groups = append(groups, values[start:i])
+			start = i + 1
+		}
+	}
+	return append(groups, values[start:])
+}
+
+// ParseValue splits a property value into component values. Whitespace and
+// comments are dropped.
+func ParseValue(value string) ([]ComponentValue, error) {
+	t := valueTokenizer{s: value}
+	values, err := t.values(0)
+	if err != nil {
+		return nil, err
+	}
+	if t.pos < len(t.s) {
+		return nil, fmt.Errorf("unexpected %q in value %q", t.s[t.pos], value)
+	}
+	return values, nil
+}
+
+type valueTokenizer struct {
+	s   string
+	pos int
+}
+
+// values reads component values until the closing bracket end, or until
+// the end of input if end is 0.
+func (t *valueTokenizer) values(end byte) ([]ComponentValue, error) {
+	var values []ComponentValue
+	for {
+		t.skipSpace()
+		if t.pos == len(t.s) {
+			if end != 0 {
+				return nil, fmt.Errorf("missing %q in value %q", end, t.s)
+			}
+			return values, nil
+		}
+		c := t.s[t.pos]
+		if c == end {
+			t.pos++
+			return values, nil
+		}
+		if c == ')' || c == ']' {
+			return values, nil
+		}
+
+		v, err := t.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+func (t *valueTokenizer) skipSpace() {
+	for t.pos < len(t.s) {
+		switch {
+		case isSpace(t.s[t.pos]):
+			t.pos++
+		case strings.HasPrefix(t.s[t.pos:], "/*"):
+			end := strings.Index(t.s[t.pos+2:], "*/")
+			if end < 0 {
+				t.pos = len(t.s)
+				return
+			}
+			t.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+func (t *valueTokenizer) value() (ComponentValue, error) {
+	c := t.s[t.pos]
+	switch {
+	case c == '"' || c == '\'':
+		s, err := t.string()
+		return ComponentValue{Type: StringValue, Text: s}, err
+	case c == '#' && t.pos+1 < len(t.s) && isNameByte(t.s[t.pos+1]):
+		t.pos++
+		return ComponentValue{Type: HashValue, Text: t.name()}, nil
+	case t.startsNumber():
+		return t.numeric()
+	case t.startsIdent():
+		name := t.name()
+		if t.pos < len(t.s) && t.s[t.pos] == '(' {
+			t.pos++
+			return t.function(name)
+		}
+		return ComponentValue{Type: IdentValue, Text: name}, nil
+	case c == '(' || c == '[':
+		t.pos++
+		end := byte(')')
+		if c == '[' {
+			end = ']'
+		}
+		args, err := t.values(end)
+		return ComponentValue{Type: BlockValue, Text: string(c), Args: args}, err
+	}
+	t.pos++
+	return ComponentValue{Type: DelimValue, Text: string(c)}, nil
+}
+
+func (t *valueTokenizer) function(name string) (ComponentValue, error) {
+	if strings.EqualFold(name, "url") {
+		t.skipSpace()
+		if t.pos < len(t.s) && t.s[t.pos] != '"' && t.s[t.pos] != '\'' {
+			end := strings.IndexByte(t.s[t.pos:], ')')
+			if end < 0 {
+				return ComponentValue{}, fmt.Errorf("missing ')' in value %q", t.s)
+			}
+			url := strings.TrimSpace(t.s[t.pos : t.pos+end])
+			t.pos += end + 1
+			return ComponentValue{Type: URLValue, Text: url}, nil
+		}
+	}
+
+	args, err := t.values(')')
+	if err != nil {
+		return ComponentValue{}, err
+	}
+	if strings.EqualFold(name, "url") {
+		if len(args) != 1 || args[0].Type != StringValue {
+			return ComponentValue{}, fmt.Errorf("invalid url in value %q", t.s)
+		}
+		return ComponentValue{Type: URLValue, Text: args[0].Text}, nil
+	}
+	return ComponentValue{Type: FunctionValue, Text: strings.ToLower(name), Args: args}, nil
+}
+
+func (t *valueTokenizer) string() (string, error) {
+	quote := t.s[t.pos]
+	t.pos++
+	var sb strings.Builder
+	for t.pos < len(t.s) {
+		c := t.s[t.pos]
+		t.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			t.escape(&sb)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string in value %q", t.s)
+}
+
+// escape writes the character escaped by a backslash, either literally or
+// as up to six hex digits followed by an optional space, like "\2022 ".
+func (t *valueTokenizer) escape(sb *strings.Builder) {
+	end := t.pos
+	for end < len(t.s) && end-t.pos < 6 && isHexDigit(t.s[end]) {
+		end++
+	}
+	if end == t.pos {
+		if t.pos < len(t.s) {
+			sb.WriteByte(t.s[t.pos])
+			t.pos++
+		}
+		return
+	}
+	r, _ := strconv.ParseUint(t.s[t.pos:end], 16, 32)
+	if r == 0 || r > unicode.MaxRune || (r >= 0xd800 && r <= 0xdfff) {
+		r = unicode.ReplacementChar
+	}
+	sb.WriteRune(rune(r))
+	t.pos = end
+	if t.pos < len(t.s) && isSpace(t.s[t.pos]) {
+		t.pos++
+	}
+}
+
+// startsIdent reports whether an identifier starts at the position. A
+// backslash only starts one if it escapes a character.
+func (t *valueTokenizer) startsIdent() bool {
+	s := t.s[t.pos:]
+	if len(s) == 0 {
+		return false
+	}
+	if s[0] == '-' {
+		s = s[1:]
+		if len(s) > 0 && s[0] == '-' {
+			return true
+		}
+	}
+	if len(s) == 0 {
+		return false
+	}
+	c := s[0]
+	if c == '\\' {
+		return len(s) > 1 && s[1] != '\n'
+	}
+	return c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (t *valueTokenizer) startsNumber() bool {
+	s := t.s[t.pos:]
+	if s[0] == '+' || s[0] == '-' {
+		s = s[1:]
+	}
+	if len(s) > 0 && s[0] == '.' {
+		s = s[1:]
+	}
+	return len(s) > 0 && s[0] >= '0' && s[0] <= '9'
+}
+
+// name reads a name. Escaped characters are kept without their backslash.
+func (t *valueTokenizer) name() string {
+	var sb strings.Builder
+	for t.pos < len(t.s) && (isNameByte(t.s[t.pos]) || t.s[t.pos] == '\\') {
+		if t.s[t.pos] == '\\' {
+			if t.pos+1 == len(t.s) || t.s[t.pos+1] == '\n' {
+				break
+			}
+			t.pos++
+		}
+		sb.WriteByte(t.s[t.pos])
+		t.pos++
+	}
+	return sb.String()
+}
+
+func (t *valueTokenizer) numeric() (ComponentValue, error) {
+	start := t.pos
+	if c := t.s[t.pos]; c == '+' || c == '-' {
+		t.pos++
+	}
+	integer := true
+	t.digits()
+	if t.pos+1 < len(t.s) && t.s[t.pos] == '.' && isDigit(t.s[t.pos+1]) {
+		integer = false
+		t.pos++
+		t.digits()
+	}
+	if t.pos < len(t.s) && (t.s[t.pos] == 'e' || t.s[t.pos] == 'E') {
+		i := t.pos + 1
+		if i < len(t.s) && (t.s[i] == '+' || t.s[i] == '-') {
+			i++
+		}
+		if i < len(t.s) && isDigit(t.s[i]) {
+			integer = false
+			t.pos = i
+			t.digits()
+		}
+	}
+	n, err := strconv.ParseFloat(t.s[start:t.pos], 64)
+	if err != nil {
+		// only numbers too large for a float64 fail
+		return ComponentValue{}, fmt.Errorf("number %s out of range in value %q", t.s[start:t.pos], t.s)
+	}
+
+	v := ComponentValue{Type: NumberValue, Number: n, Integer: integer}
+	switch {
+	case t.pos < len(t.s) && t.s[t.pos] == '%':
+		t.pos++
+		v.Type = PercentageValue
+	case t.pos < len(t.s) && t.startsIdent():
+		v.Type = DimensionValue
+		v.Unit = strings.ToLower(t.name())
+	}
+	return v, nil
+}
+
+func (t *valueTokenizer) digits() {
+	for t.pos < len(t.s) && isDigit(t.s[t.pos]) {
+		t.pos++
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,102 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected []ComponentValue
+	}{
+		{"Keywords", "solid  RED", []ComponentValue{
+			{Type: IdentValue, Text: "solid"},
+			{Type: IdentValue, Text: "RED"},
+		}},
+		{"Numbers", "1px -.5em 50% 3 1e2", []ComponentValue{
+			{Type: DimensionValue, Number: 1, Integer: true, Unit: "px"},
+			{Type: DimensionValue, Number: -0.5, Unit: "em"},
+			{Type: PercentageValue, Number: 50, Integer: true},
+			{Type: NumberValue, Number: 3, Integer: true},
+			{Type: NumberValue, Number: 100},
+		}},
+		{"Strings and hashes", `'Zil', "a \"b\"" #fFf`, []ComponentValue{
+			{Type: StringValue, Text: "Zil"},
+			{Type: DelimValue, Text: ","},
+			{Type: StringValue, Text: `a "b"`},
+			{Type: HashValue, Text: "fFf"},
+		}},
+		{"Escapes", `"\2022 x" '\5F3\41' "\0"`, []ComponentValue{
+			{Type: StringValue, Text: "•x"},
+			{Type: StringValue, Text: "׳A"},
+			{Type: StringValue, Text: "\uFFFD"},
+		}},
+		{"URLs", `url(img/a.png) url( "b.png" )`, []ComponentValue{
+			{Type: URLValue, Text: "img/a.png"},
+			{Type: URLValue, Text: "b.png"},
+		}},
+		{"Functions and blocks", "RGB(0 0 0 / 50%) [a b] 1/2", []ComponentValue{
+			{Type: FunctionValue, Text: "rgb", Args: []ComponentValue{
+				{Type: NumberValue, Integer: true},
+				{Type: NumberValue, Integer: true},
+				{Type: NumberValue, Integer: true},
+				{Type: DelimValue, Text: "/"},
+				{Type: PercentageValue, Number: 50, Integer: true},
+			}},
+			{Type: BlockValue, Text: "[", Args: []ComponentValue{
+				{Type: IdentValue, Text: "a"},
+				{Type: IdentValue, Text: "b"},
+			}},
+			{Type: NumberValue, Number: 1, Integer: true},
+			{Type: DelimValue, Text: "/"},
+			{Type: NumberValue, Number: 2, Integer: true},
+		}},
+		{"Escaped identifiers", `a\ b \\ -\31 \`, []ComponentValue{
+			{Type: IdentValue, Text: "a b"},
+			{Type: IdentValue, Text: `\`},
+			{Type: IdentValue, Text: "-31"},
+			{Type: DelimValue, Text: `\`},
+		}},
+		{"Comments", "a /* b */ c", []ComponentValue{
+			{Type: IdentValue, Text: "a"},
+			{Type: IdentValue, Text: "c"},
+		}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := ParseValue(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+func TestParseValueError(t *testing.T) {
+	for _, value := range []string{"rgb(0 0 0", "'abc", "a)", "url(x", "1e309px", "calc(-1e400 * 1)"} {
+		_, err := ParseValue(value)
+		assert.Error(t, err, value)
+	}
+
+	// a lone backslash isn't an identifier
+	assert.False(t, isIdentifier(""))
+	for _, name := range []string{"animation", "font"} {
+		_, err := ExpandShorthand(name, `\`)
+		assert.Error(t, err, name)
+	}
+	_, err := CSSStyle("animation-name", map[string]string{"animation-name": `\`})
+	assert.Error(t, err)
+}
+
+func TestSerializeValues(t *testing.T) {
+	values, err := ParseValue(`'Zil' ,serif 1.50px url(a.png) rgb(1,2,3) #abc`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `"Zil", serif 1.5px url("a.png") rgb(1, 2, 3) #abc`, serializeValues(values))
+}